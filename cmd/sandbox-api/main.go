@@ -2,21 +2,63 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"time"
 
-	middleware "github.com/deepmap/oapi-codegen/pkg/chi-middleware"
-	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/makirill/sandbox-azure/internal/api"
+	"github.com/makirill/sandbox-azure/internal/azure"
 	"github.com/makirill/sandbox-azure/internal/log"
 	"github.com/makirill/sandbox-azure/internal/models"
 )
 
+// How long responses are kept for replay of requests with the Idempotency-Key
+const idempotencyTTL = 24 * time.Hour
+
+// How long the retries wait for the request in flight, it is considered lost afterwards
+const idempotencyLockTimeout = time.Minute
+
+// How often the scheduled sandboxes are checked
+const schedulerInterval = 30 * time.Second
+
+// How often the sandboxes are checked for activity
+const idleCheckInterval = time.Hour
+
+// How often the revoked tokens are reloaded, the tokens revoked by the other
+// instances are accepted until then
+const revocationsReloadInterval = 30 * time.Second
+
+// How often the idle rate limit buckets are forgotten
+const rateLimitCleanupInterval = 10 * time.Minute
+
+// The sandboxes of the owner provisioned at once, unless set by MAX_PENDING_CREATES
+const defaultMaxPendingCreates = 5
+
+// How long the owner has to react to the idle warning, unless set by IDLE_WARNING_PERIOD
+const defaultIdleWarningPeriod = 24 * time.Hour
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "mint-token" {
+		err := runMintToken(os.Args[2:])
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error minting token: %s\n", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	log.InitLoggers(true)
 
 	port := os.Getenv("PORT")
@@ -27,27 +69,24 @@ func main() {
 	//----------------------------------------
 	// JWT auth
 	//----------------------------------------
-	fa, err := api.NewFakeAuthenticator()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating fake authenticator: %s\n", err)
-		os.Exit(1)
-	}
+	authCtx, stopAuth := context.WithCancel(context.Background())
+	defer stopAuth()
 
-	readerJWS, err := fa.CreateJSWWithClaims([]string{})
+	jwsValidator, err := newJWSValidator(authCtx)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating reader JWS: %s\n", err)
+		fmt.Fprintf(os.Stderr, "Error creating JWS validator: %s\n", err)
 		os.Exit(1)
 	}
 
-	writerJWS, err := fa.CreateJSWWithClaims([]string{"sandbox:w"})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating writer JWS: %s\n", err)
-		os.Exit(1)
+	claimMapping := api.ClaimMapping{}
+	if path := os.Getenv("PERMISSION_MAPPING_FILE"); path != "" {
+		claimMapping, err = api.LoadClaimMapping(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading permission mapping: %s\n", err)
+			os.Exit(1)
+		}
 	}
 
-	log.Debug.Printf("DEBUG: Reader JWS:\n %s\n\n", readerJWS)
-	log.Debug.Printf("DEBUG: Writer JWS:\n %s\n\n", writerJWS)
-
 	//----------------------------------------
 	// Database
 	//----------------------------------------
@@ -78,24 +117,131 @@ func main() {
 
 	sandboxController := models.NewAzureSandbox(dbPool)
 
+	approvalPolicy, err := newApprovalPolicy(os.Getenv("APPROVAL_MAX_LIFETIME"), os.Getenv("APPROVAL_REQUEST_TTL"))
+	if err == nil {
+		err = setApprovalRules(&approvalPolicy)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading approval policy: %s\n", err)
+		os.Exit(1)
+	}
+	sandboxController.SetApprovalPolicy(approvalPolicy)
+
+	resources, err := newResourceProvider()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating resource provider: %s\n", err)
+		os.Exit(1)
+	}
+	sandboxController.SetResourceProvider(resources)
+
+	maxPendingCreates := defaultMaxPendingCreates
+	if limit := os.Getenv("MAX_PENDING_CREATES"); limit != "" {
+		maxPendingCreates, err = strconv.Atoi(limit)
+		if err != nil || maxPendingCreates < 0 {
+			fmt.Fprintf(os.Stderr, "Error reading MAX_PENDING_CREATES: %q is not a non-negative number\n", limit)
+			os.Exit(1)
+		}
+	}
+	sandboxController.SetMaxPendingCreates(maxPendingCreates)
+
+	if webhookURL := os.Getenv("APPROVAL_WEBHOOK_URL"); webhookURL != "" {
+		sandboxController.SetNotifier(models.NewWebhookNotifier(webhookURL))
+	}
+
+	// Provision the scheduled sandboxes once they are due
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	go sandboxController.RunScheduler(schedulerCtx, schedulerInterval)
+
+	// Expire the idle sandboxes early, only if the threshold is set
+	if threshold := os.Getenv("IDLE_THRESHOLD"); threshold != "" {
+		idleConfig, err := newIdleConfig(threshold, os.Getenv("IDLE_WARNING_PERIOD"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading idle detector config: %s\n", err)
+			os.Exit(1)
+		}
+
+		activity, err := newActivityProvider()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating activity provider: %s\n", err)
+			os.Exit(1)
+		}
+
+		go sandboxController.RunIdleDetector(schedulerCtx, activity, idleConfig)
+	}
+
+	// Personal access tokens, accepted along with the JWTs
+	apiTokens := models.NewAPITokens(models.NewAPITokensPostgres(dbPool))
+
+	// The revoked tokens are denied from the start
+	revocations := models.NewRevocations(models.NewRevocationsPostgres(dbPool))
+	if err := revocations.Load(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading revoked tokens: %s\n", err)
+		os.Exit(1)
+	}
+	go revocations.Run(schedulerCtx, revocationsReloadInterval)
+
+	rateLimiter, err := newRateLimiter(dbPool)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading rate limits: %s\n", err)
+		os.Exit(1)
+	}
+	go rateLimiter.Run(schedulerCtx, rateLimitCleanupInterval)
+
+	// The actions are recorded in the tamper-evident audit log
+	auditLog := models.NewAuditLog(models.NewAuditPostgres(dbPool))
+
 	// Create an instance fo handler which satisfies the generated interface
-	sandboxHandler := api.NewSandboxHandler(sandboxController)
+	sandboxHandler := api.NewSandboxHandler(sandboxController, apiTokens, revocations, auditLog)
 
-	sandboxStrictHandler := api.NewStrictHandler(sandboxHandler, nil)
+	sandboxStrictHandler := api.NewStrictHandlerWithOptions(sandboxHandler,
+		[]api.StrictMiddlewareFunc{api.RequestURL},
+		api.StrictHTTPServerOptions{
+			RequestErrorHandlerFunc:  api.RequestErrorHandler,
+			ResponseErrorHandlerFunc: api.ResponseErrorHandler,
+		},
+	)
 
 	r := chi.NewRouter()
 
+	// Tell the requests apart in the audit log
+	r.Use(api.RequestID)
+
+	// Make the authenticated principal available to the handlers
+	r.Use(api.PrincipalContext)
+
+	// Record the requests rejected by the authentication
+	r.Use(sandboxHandler.AuditRejected)
+
 	// Use validation middleware to validate requests against the OpenAPI schema
-	r.Use(middleware.OapiRequestValidatorWithOptions(swagger,
-		&middleware.Options{
-			Options: openapi3filter.Options{
-				AuthenticationFunc: api.NewAuthenticator(fa),
-			},
-		},
-	))
+	authenticator := &api.Authenticator{
+		Validator:     jwsValidator,
+		Mapping:       claimMapping,
+		APITokens:     apiTokens,
+		Revocations:   revocations,
+		SessionCookie: os.Getenv("SESSION_COOKIE"),
+	}
+
+	validator, err := api.NewRequestValidator(swagger, authenticator.Authenticate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating request validator: %s\n", err)
+		os.Exit(1)
+	}
+	r.Use(validator)
+
+	// Limit the requests of every principal, the rejected ones are not replayed
+	r.Use(api.NewRateLimitMiddleware(rateLimiter))
+
+	// Replay stored responses for retried requests
+	r.Use(api.NewIdempotencyMiddleware(models.NewIdempotencyPostgres(dbPool), idempotencyTTL, idempotencyLockTimeout))
+
+	// Record the mutations, the replayed ones were recorded the first time
+	r.Use(sandboxHandler.Audit)
 
 	// Register sandboxAzure as the handler for the interface
-	api.HandlerFromMux(sandboxStrictHandler, r)
+	api.HandlerWithOptions(sandboxStrictHandler, api.ChiServerOptions{
+		BaseRouter:       r,
+		ErrorHandlerFunc: api.RequestErrorHandler,
+	})
 
 	go func() {
 		log.Logger.Info("Listening on port " + port)
@@ -114,5 +260,224 @@ func main() {
 
 	log.Logger.Info("Got " + sig.String() + " signal. Shutting down...")
 
+	stopScheduler()
+
 	sandboxController.Wait()
 }
+
+func newIdleConfig(threshold string, warningPeriod string) (models.IdleConfig, error) {
+	config := models.IdleConfig{
+		Interval:      idleCheckInterval,
+		WarningPeriod: defaultIdleWarningPeriod,
+	}
+
+	var err error
+
+	config.Threshold, err = time.ParseDuration(threshold)
+	if err != nil {
+		return models.IdleConfig{}, fmt.Errorf("IDLE_THRESHOLD: %w", err)
+	}
+
+	if warningPeriod != "" {
+		config.WarningPeriod, err = time.ParseDuration(warningPeriod)
+		if err != nil {
+			return models.IdleConfig{}, fmt.Errorf("IDLE_WARNING_PERIOD: %w", err)
+		}
+	}
+
+	return config, nil
+}
+
+// The buckets are kept in the database, so the limits are shared by all the
+// instances, unless RATE_LIMIT_STORE=memory limits every instance on its own
+func newRateLimiter(dbPool *pgxpool.Pool) (*models.RateLimiter, error) {
+	var store models.RateLimitData
+
+	switch name := os.Getenv("RATE_LIMIT_STORE"); name {
+	case "", "postgres":
+		store = models.NewRateLimitsPostgres(dbPool)
+	case "memory":
+		store = models.NewRateLimitsMemory()
+	default:
+		return nil, fmt.Errorf("RATE_LIMIT_STORE: unknown store %q", name)
+	}
+
+	limits := map[string]models.RateLimit{}
+	for class, name := range map[string]string{
+		models.RateClassRead:  "RATE_LIMIT_READ",
+		models.RateClassWrite: "RATE_LIMIT_WRITE",
+		models.RateClassAdmin: "RATE_LIMIT_ADMIN",
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		limit, err := parseRateLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		limits[class] = limit
+	}
+
+	return models.NewRateLimiter(store, limits), nil
+}
+
+// parseRateLimit reads the limit as <requests>/<period>, e.g. 60/1m, the
+// single 0 turns the limit off
+func parseRateLimit(value string) (models.RateLimit, error) {
+	if value == "0" {
+		return models.RateLimit{}, nil
+	}
+
+	requests, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return models.RateLimit{}, fmt.Errorf("%q is not <requests>/<period>", value)
+	}
+
+	limit := models.RateLimit{}
+
+	var err error
+
+	limit.Requests, err = strconv.Atoi(requests)
+	if err != nil || limit.Requests <= 0 {
+		return models.RateLimit{}, fmt.Errorf("%q is not a positive number of requests", requests)
+	}
+
+	limit.Period, err = time.ParseDuration(period)
+	if err != nil || limit.Period <= 0 {
+		return models.RateLimit{}, fmt.Errorf("%q is not a positive period", period)
+	}
+
+	return limit, nil
+}
+
+func newApprovalPolicy(maxLifetime string, requestTTL string) (models.ApprovalPolicy, error) {
+	policy := models.ApprovalPolicy{
+		MaxLifetime:     models.DefaultMaxLifetime,
+		PrivilegedRoles: models.DefaultPrivilegedRoles,
+		RequestTTL:      models.DefaultApprovalTTL,
+	}
+
+	var err error
+
+	if maxLifetime != "" {
+		policy.MaxLifetime, err = time.ParseDuration(maxLifetime)
+		if err != nil {
+			return models.ApprovalPolicy{}, fmt.Errorf("APPROVAL_MAX_LIFETIME: %w", err)
+		}
+	}
+
+	if requestTTL != "" {
+		policy.RequestTTL, err = time.ParseDuration(requestTTL)
+		if err != nil {
+			return models.ApprovalPolicy{}, fmt.Errorf("APPROVAL_REQUEST_TTL: %w", err)
+		}
+	}
+
+	return policy, nil
+}
+
+// setApprovalRules reads the roles and the templates needing the approval.
+// The variable set to an empty value turns the rule off.
+func setApprovalRules(policy *models.ApprovalPolicy) error {
+	if roles, ok := os.LookupEnv("APPROVAL_PRIVILEGED_ROLES"); ok {
+		policy.PrivilegedRoles = splitList(roles)
+
+		for _, role := range policy.PrivilegedRoles {
+			switch role {
+			case models.RoleReader, models.RoleContributor, models.RoleOwner, models.RoleUserAccessAdministrator:
+			default:
+				return fmt.Errorf("APPROVAL_PRIVILEGED_ROLES: unknown role %q", role)
+			}
+		}
+	}
+
+	policy.LargeTemplates = splitList(os.Getenv("APPROVAL_LARGE_TEMPLATES"))
+
+	return nil
+}
+
+// Helper to split the comma separated list, the empty items are dropped
+func splitList(list string) []string {
+	items := []string{}
+
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// The resources are managed in Azure if the subscription is set, the local
+// runs only simulate the work
+func newResourceProvider() (models.ResourceProvider, error) {
+	subscriptionID := os.Getenv("AZURE_SUBSCRIPTION_ID")
+	if subscriptionID == "" {
+		return models.FakeResourceProvider{}, nil
+	}
+
+	return azure.NewResourceGroups(subscriptionID)
+}
+
+// The activity is read from Azure if the subscription is set, the local runs
+// don't provision anything, so they get the fake without any activity
+func newActivityProvider() (models.ActivityProvider, error) {
+	subscriptionID := os.Getenv("AZURE_SUBSCRIPTION_ID")
+	if subscriptionID == "" {
+		return models.NewFakeActivityProvider(), nil
+	}
+
+	ignoredCallers := []string{}
+	if callers := os.Getenv("AZURE_ACTIVITY_IGNORED_CALLERS"); callers != "" {
+		ignoredCallers = strings.Split(callers, ",")
+	}
+
+	return azure.NewActivityLog(subscriptionID, ignoredCallers)
+}
+
+// The tokens are validated against the OIDC issuers, the fake authenticator
+// and the tokens signed by its key are only used if asked for explicitly
+func newJWSValidator(ctx context.Context) (api.JWSValidator, error) {
+	switch mode := os.Getenv("AUTH_MODE"); mode {
+	case "fake":
+		return newFakeAuthenticator()
+	case "", "oidc":
+	default:
+		return nil, fmt.Errorf("AUTH_MODE: unknown mode %q", mode)
+	}
+
+	issuers := os.Getenv("OIDC_ISSUERS")
+	if issuers == "" {
+		return nil, errors.New("OIDC_ISSUERS is not set, set AUTH_MODE=fake to use the development tokens")
+	}
+
+	config := api.OIDCConfig{
+		Issuers:  strings.Split(issuers, ","),
+		Audience: os.Getenv("OIDC_AUDIENCE"),
+	}
+
+	if skew := os.Getenv("OIDC_CLOCK_SKEW"); skew != "" {
+		var err error
+
+		config.ClockSkew, err = time.ParseDuration(skew)
+		if err != nil {
+			return nil, fmt.Errorf("OIDC_CLOCK_SKEW: %w", err)
+		}
+	}
+
+	return api.NewOIDCValidator(ctx, config)
+}
+
+// The fake mode accepts the tokens anyone with the key can mint with the
+// mint-token command, so it is refused where the real resources are managed
+func newFakeAuthenticator() (*api.FakeAuthenticator, error) {
+	if os.Getenv("AZURE_SUBSCRIPTION_ID") != "" {
+		return nil, errors.New("AUTH_MODE=fake is not allowed with AZURE_SUBSCRIPTION_ID set")
+	}
+
+	return loadFakeAuthenticator(os.Getenv("FAKE_AUTH_KEY_FILE"))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/api"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+// How long the minted tokens are valid, unless set by -ttl
+const defaultMintTTL = 8 * time.Hour
+
+// runMintToken mints the development token accepted by the server running
+// with AUTH_MODE=fake and prints it to stdout, e.g.
+//
+//	sandbox-api mint-token -subject alice -scopes sandbox:w -ttl 8h
+func runMintToken(args []string) error {
+	flags := flag.NewFlagSet("mint-token", flag.ContinueOnError)
+
+	keyFile := flags.String("key", os.Getenv("FAKE_AUTH_KEY_FILE"), "PEM file of the ECDSA private key, required unless FAKE_AUTH_KEY_FILE is set")
+	subject := flags.String("subject", "", "subject of the token, it owns the sandboxes created with the token")
+	scopes := flags.String("scopes", models.PermissionRead, "comma separated permissions of the token")
+	ttl := flags.Duration("ttl", defaultMintTTL, "lifetime of the token")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *subject == "" {
+		return errors.New("-subject is required")
+	}
+
+	if *ttl <= 0 {
+		return errors.New("-ttl must be positive")
+	}
+
+	permissions := []string{}
+	for _, scope := range strings.Split(*scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			if !api.KnownPermission(scope) {
+				return fmt.Errorf("-scopes: unknown permission %q", scope)
+			}
+			permissions = append(permissions, scope)
+		}
+	}
+
+	fa, err := loadFakeAuthenticator(*keyFile)
+	if err != nil {
+		return err
+	}
+
+	jws, err := fa.CreateJSWWithClaims(*subject, permissions, *ttl)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(jws))
+
+	return nil
+}
+
+// The key has to be given, the one built into the sources is public, so the
+// tokens signed by it could be minted by anyone
+func loadFakeAuthenticator(keyFile string) (*api.FakeAuthenticator, error) {
+	if keyFile == "" {
+		return nil, errors.New("the development key is required, set FAKE_AUTH_KEY_FILE or -key")
+	}
+
+	return api.LoadFakeAuthenticator(keyFile)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+func toApproval(details models.ApprovalDetails) Approval {
+	approval := Approval{
+		Id:          details.UUID,
+		SandboxId:   details.SandboxID,
+		SandboxName: details.SandboxName,
+		Status:      ApprovalStatus(details.Status),
+		Reasons:     details.Reasons,
+		RequestedBy: details.RequestedBy,
+		RequestedAt: details.RequestedAt,
+		ExpiresAt:   details.ExpiresAt,
+		DecidedAt:   details.DecidedAt,
+	}
+
+	if approval.Reasons == nil {
+		approval.Reasons = []string{}
+	}
+
+	if details.DecidedBy != "" {
+		approval.DecidedBy = String(details.DecidedBy)
+	}
+
+	if details.Comment != "" {
+		approval.Comment = String(details.Comment)
+	}
+
+	return approval
+}
+
+// Helper to get the comment of the optional decision body
+func decisionComment(body *ApprovalDecision) string {
+	if body == nil || body.Comment == nil {
+		return ""
+	}
+
+	return *body.Comment
+}
+
+func (sh *SandboxHandler) ListApprovals(ctx context.Context, request ListApprovalsRequestObject) (ListApprovalsResponseObject, error) {
+	params := request.Params
+
+	limit, offset, status := 0, 0, ""
+	if params.Limit != nil {
+		limit = int(*params.Limit)
+	}
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if params.Status != nil {
+		status = string(*params.Status)
+	}
+
+	approvals, err := sh.instances.ListApprovals(status, limit, offset)
+	if err != nil {
+		problem := problemFromError(err)
+		return ListApprovalsdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	response := make(ListApprovals200JSONResponse, 0, len(approvals))
+	for _, approval := range approvals {
+		response = append(response, toApproval(approval))
+	}
+
+	return response, nil
+}
+
+func (sh *SandboxHandler) GetApproval(ctx context.Context, request GetApprovalRequestObject) (GetApprovalResponseObject, error) {
+	approval, err := sh.instances.GetApproval(request.Id)
+	if err != nil {
+		problem := problemFromError(err)
+		return GetApprovaldefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	return GetApproval200JSONResponse(toApproval(approval)), nil
+}
+
+func (sh *SandboxHandler) ApproveApproval(ctx context.Context, request ApproveApprovalRequestObject) (ApproveApprovalResponseObject, error) {
+	approval, err := sh.instances.Approve(request.Id, principalFromContext(ctx), decisionComment(request.Body))
+	if err != nil {
+		problem := problemFromError(err)
+		return ApproveApprovaldefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	log.Logger.Info("Sandbox approved", "approval", approval.UUID, "sandbox", approval.SandboxID)
+
+	return ApproveApproval200JSONResponse(toApproval(approval)), nil
+}
+
+func (sh *SandboxHandler) DenyApproval(ctx context.Context, request DenyApprovalRequestObject) (DenyApprovalResponseObject, error) {
+	approval, err := sh.instances.Deny(request.Id, principalFromContext(ctx), decisionComment(request.Body))
+	if err != nil {
+		problem := problemFromError(err)
+		return DenyApprovaldefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	log.Logger.Info("Sandbox denied", "approval", approval.UUID, "sandbox", approval.SandboxID)
+
+	return DenyApproval200JSONResponse(toApproval(approval)), nil
+}
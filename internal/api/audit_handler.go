@@ -0,0 +1,468 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+// Action of the requests rejected by the authentication
+const auditActionAuthRejected = "auth.rejected"
+
+// Kinds of the targets of the audited actions
+const (
+	auditTargetSandbox    = "sandbox"
+	auditTargetOperation  = "operation"
+	auditTargetApproval   = "approval"
+	auditTargetAPIToken   = "apiToken"
+	auditTargetRevocation = "revocation"
+	auditTargetCatalog    = "catalog"
+)
+
+type auditedOperation struct {
+	action     string
+	targetType string
+}
+
+// The mutating operations recorded in the audit log, by the operation id of
+// the embedded spec, the generator capitalizes them like the handlers. The
+// status transitions are recorded by the database.
+var auditedOperations = map[string]auditedOperation{
+	"CreateSandbox":         {"sandbox.create", auditTargetSandbox},
+	"BatchSandboxes":        {"sandbox.batch", auditTargetSandbox},
+	"UpdateSandbox":         {"sandbox.update", auditTargetSandbox},
+	"DeleteSandbox":         {"sandbox.delete", auditTargetSandbox},
+	"ForceDeleteSandbox":    {"sandbox.forceDelete", auditTargetSandbox},
+	"ReconcileSandboxes":    {"sandbox.reconcile", auditTargetOperation},
+	"StopSandbox":           {"sandbox.stop", auditTargetSandbox},
+	"StartSandbox":          {"sandbox.start", auditTargetSandbox},
+	"SetSandboxSchedule":    {"sandbox.schedule.set", auditTargetSandbox},
+	"DeleteSandboxSchedule": {"sandbox.schedule.delete", auditTargetSandbox},
+	"SkipSandboxSchedule":   {"sandbox.schedule.skip", auditTargetSandbox},
+	"SetCatalogSchedule":    {"catalog.schedule.set", auditTargetCatalog},
+	"DeleteCatalogSchedule": {"catalog.schedule.delete", auditTargetCatalog},
+	"CancelOperation":       {"operation.cancel", auditTargetOperation},
+	"ApproveApproval":       {"approval.approve", auditTargetApproval},
+	"DenyApproval":          {"approval.deny", auditTargetApproval},
+	"CreateApiToken":        {"apiToken.create", auditTargetAPIToken},
+	"RevokeApiToken":        {"apiToken.revoke", auditTargetAPIToken},
+	"CreateRevocation":      {"revocation.create", auditTargetRevocation},
+}
+
+// Audit records the mutating requests in the audit log, with the changes of
+// the target and the outcome. It has to be used after the idempotency
+// middleware, so the replayed responses are not recorded again. The failure
+// to record the event doesn't fail the request, it is done already.
+func (sh *SandboxHandler) Audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		holder, ok := r.Context().Value(principalContextKey).(*principalHolder)
+		if !ok || holder.principal == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		operation, ok := auditedOperations[holder.operationID]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		before := sh.snapshot(operation.targetType, holder.targetID)
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		statusCode := recorder.statusCode
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+
+		event := models.AuditEvent{
+			Actor:      holder.principal.Subject,
+			Action:     operation.action,
+			TargetType: operation.targetType,
+			TargetID:   holder.targetID,
+			RequestID:  RequestIDFromContext(r.Context()),
+			Outcome:    models.AuditOutcomeSuccess,
+			StatusCode: statusCode,
+		}
+
+		// The batches act on many targets, every item is recorded on its own
+		if statusCode < http.StatusBadRequest {
+			if events, ok := itemEvents(holder.operationID, event, recorder.body.Bytes()); ok {
+				sh.record(events...)
+				return
+			}
+		}
+
+		var body struct {
+			ID   string `json:"id"`
+			Code string `json:"code"`
+		}
+		_ = json.Unmarshal(recorder.body.Bytes(), &body)
+
+		if statusCode >= http.StatusBadRequest {
+			event.Outcome = models.AuditOutcomeFailure
+			if body.Code != "" {
+				event.After = map[string]interface{}{"code": body.Code}
+			}
+		} else {
+			// The new targets are known once they are created
+			if event.TargetID == "" {
+				event.TargetID = createdID(recorder.headers, body.ID)
+			}
+
+			after := sh.snapshot(operation.targetType, event.TargetID)
+			if before == nil {
+				event.After = after
+			} else {
+				event.Before, event.After = models.DiffValues(before, after)
+			}
+		}
+
+		sh.record(event)
+	})
+}
+
+// AuditRejected records the requests of any operation rejected by the
+// authentication, the callers without a valid token are recorded without
+// the actor. It has to be used between PrincipalContext and the OpenAPI
+// validation middleware, the rejected requests go no further.
+func (sh *SandboxHandler) AuditRejected(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		holder, ok := r.Context().Value(principalContextKey).(*principalHolder)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		if holder.authErr == nil || holder.principal != nil {
+			return
+		}
+
+		sh.record(models.AuditEvent{
+			Actor:      holder.rejectedSubject,
+			Action:     auditActionAuthRejected,
+			TargetType: auditedOperations[holder.operationID].targetType,
+			TargetID:   holder.targetID,
+			RequestID:  RequestIDFromContext(r.Context()),
+			Outcome:    models.AuditOutcomeFailure,
+			StatusCode: recorder.statusCode,
+			After:      map[string]interface{}{"operation": holder.operationID, "reason": holder.authErr.Error()},
+		})
+	})
+}
+
+// statusRecorder keeps the status code of the response only, unlike
+// responseRecorder the body is not copied, the exports are streamed through
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (sr *statusRecorder) WriteHeader(statusCode int) {
+	if sr.statusCode == 0 {
+		sr.statusCode = statusCode
+	}
+
+	sr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	if sr.statusCode == 0 {
+		sr.WriteHeader(http.StatusOK)
+	}
+
+	return sr.ResponseWriter.Write(b)
+}
+
+// Helper to record the events, the failure is only logged
+func (sh *SandboxHandler) record(events ...models.AuditEvent) {
+	for _, event := range events {
+		if _, err := sh.audit.Record(event); err != nil {
+			log.Logger.Error("Failed to record audit event", "action", event.Action, "target", event.TargetID, "err", err)
+		}
+	}
+}
+
+// itemEvents splits the event of the batch operation into one event per item
+// of the response, with the target and the outcome of the item. The dry runs
+// are recorded too. The event is kept as it is if there are no items.
+func itemEvents(operationID string, event models.AuditEvent, body []byte) ([]models.AuditEvent, bool) {
+	var events []models.AuditEvent
+
+	// Helper to derive the event of the item, the failed items carry the
+	// status and the code of their problem
+	itemEvent := func(targetID string, after map[string]interface{}, problem *Problem) models.AuditEvent {
+		item := event
+		item.TargetID = targetID
+		item.After = after
+
+		if problem != nil {
+			item.Outcome = models.AuditOutcomeFailure
+			item.StatusCode = int(problem.Status)
+			item.After["code"] = problem.Code
+		}
+
+		return item
+	}
+
+	switch operationID {
+	case "BatchSandboxes":
+		var response BatchResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, false
+		}
+
+		for _, result := range response.Results {
+			after := map[string]interface{}{"action": result.Action, "status": string(result.Status)}
+			events = append(events, itemEvent(stringValue(result.SandboxId), after, result.Error))
+		}
+	case "ReconcileSandboxes":
+		var response ReconcileResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, false
+		}
+
+		for _, result := range response.Results {
+			after := map[string]interface{}{"dryRun": response.DryRun}
+			if result.ReconciledStatus != nil {
+				after["reconciledStatus"] = *result.ReconciledStatus
+			}
+			events = append(events, itemEvent(result.Operation.Id, after, result.Error))
+		}
+	}
+
+	return events, len(events) != 0
+}
+
+// snapshot returns the target as the clients see it, nil if it can't be read.
+// The tokens and the revocations are not read back, the secrets are not
+// recorded either way.
+func (sh *SandboxHandler) snapshot(targetType string, id string) map[string]interface{} {
+	if id == "" {
+		return nil
+	}
+
+	var target interface{}
+
+	switch targetType {
+	case auditTargetSandbox:
+		details, err := sh.instances.GetByUUID(id)
+		if err != nil {
+			return nil
+		}
+		target = toSandbox(details)
+	case auditTargetOperation:
+		details, err := sh.instances.GetOperation(id)
+		if err != nil {
+			return nil
+		}
+		target = toOperation(details)
+	case auditTargetApproval:
+		details, err := sh.instances.GetApproval(id)
+		if err != nil {
+			return nil
+		}
+		target = toApproval(details)
+	case auditTargetCatalog:
+		schedule, err := sh.instances.GetCatalogSchedule(id)
+		if err != nil {
+			return nil
+		}
+		target = toCatalogSchedule(schedule)
+	default:
+		return nil
+	}
+
+	encoded, err := json.Marshal(target)
+	if err != nil {
+		return nil
+	}
+
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(encoded, &values); err != nil {
+		return nil
+	}
+
+	return values
+}
+
+// Helper to find the id of the created target, the Location header points to
+// the new sandbox, the other targets are returned in the body
+func createdID(headers http.Header, bodyID string) string {
+	if location := headers.Get("Location"); location != "" {
+		return path.Base(location)
+	}
+
+	return bodyID
+}
+
+func toAuditEvent(event models.AuditEvent) AuditEvent {
+	auditEvent := AuditEvent{
+		Id:         event.ID,
+		OccurredAt: event.OccurredAt,
+		Actor:      event.Actor,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetId:   event.TargetID,
+		RequestId:  event.RequestID,
+		Outcome:    AuditEventOutcome(event.Outcome),
+		PrevHash:   hex.EncodeToString(event.PrevHash),
+		Hash:       hex.EncodeToString(event.Hash),
+	}
+
+	if event.StatusCode != 0 {
+		auditEvent.StatusCode = &event.StatusCode
+	}
+
+	if event.Before != nil {
+		auditEvent.Before = &event.Before
+	}
+
+	if event.After != nil {
+		auditEvent.After = &event.After
+	}
+
+	return auditEvent
+}
+
+// Helper to map the query parameters to the models filter, the listing
+// parameters are converted to the export ones first
+func toAuditFilter(params ExportAuditEventsParams) models.AuditFilter {
+	filter := models.AuditFilter{
+		Actor:      stringValue(params.Actor),
+		Action:     stringValue(params.Action),
+		TargetType: stringValue(params.TargetType),
+		TargetID:   stringValue(params.TargetId),
+		RequestID:  stringValue(params.RequestId),
+		From:       params.From,
+		To:         params.To,
+	}
+
+	if params.Outcome != nil {
+		filter.Outcome = string(*params.Outcome)
+	}
+
+	if params.After != nil {
+		filter.AfterID = *params.After
+	}
+
+	return filter
+}
+
+// auditPageResponse writes the Link header only if there is a next page
+type auditPageResponse struct {
+	events []AuditEvent
+	next   string
+}
+
+// Make sure we conform to the listing response interface
+var _ ListAuditEventsResponseObject = auditPageResponse{}
+
+func (response auditPageResponse) VisitListAuditEventsResponse(w http.ResponseWriter) error {
+	if response.next != "" {
+		w.Header().Set("Link", "<"+response.next+`>; rel="next"`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	return json.NewEncoder(w).Encode(response.events)
+}
+
+func (sh *SandboxHandler) ListAuditEvents(ctx context.Context, request ListAuditEventsRequestObject) (ListAuditEventsResponseObject, error) {
+	params := request.Params
+
+	filter := toAuditFilter(ExportAuditEventsParams{
+		Actor:      params.Actor,
+		Action:     params.Action,
+		TargetType: params.TargetType,
+		TargetId:   params.TargetId,
+		RequestId:  params.RequestId,
+		Outcome:    (*ExportAuditEventsParamsOutcome)(params.Outcome),
+		From:       params.From,
+		To:         params.To,
+		After:      params.After,
+	})
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+
+	page, err := sh.audit.List(filter)
+	if err != nil {
+		problem := problemFromError(err)
+		return ListAuditEventsdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	response := auditPageResponse{events: make([]AuditEvent, 0, len(page.Events))}
+	for _, event := range page.Events {
+		response.events = append(response.events, toAuditEvent(event))
+	}
+
+	if page.Next != 0 {
+		u := requestURLFromContext(ctx)
+		query := u.Query()
+		query.Set("after", strconv.FormatInt(page.Next, 10))
+		response.next = u.Path + "?" + query.Encode()
+	}
+
+	return response, nil
+}
+
+// ExportAuditEvents streams the events as JSON Lines while they are read, the
+// failure in the middle of the export cuts the response short
+func (sh *SandboxHandler) ExportAuditEvents(ctx context.Context, request ExportAuditEventsRequestObject) (ExportAuditEventsResponseObject, error) {
+	filter := toAuditFilter(request.Params)
+
+	// The filter is checked before anything is sent
+	if err := models.ValidateAuditFilter(filter); err != nil {
+		problem := problemFromError(err)
+		return ExportAuditEventsdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		encoder := json.NewEncoder(writer)
+
+		err := sh.audit.Export(filter, func(event models.AuditEvent) error {
+			return encoder.Encode(toAuditEvent(event))
+		})
+		if err != nil {
+			log.Logger.Error("Failed to export audit events", "err", err)
+		}
+
+		writer.CloseWithError(err)
+	}()
+
+	return ExportAuditEvents200ApplicationxNdjsonResponse{Body: reader}, nil
+}
+
+func (sh *SandboxHandler) VerifyAuditLog(ctx context.Context, request VerifyAuditLogRequestObject) (VerifyAuditLogResponseObject, error) {
+	verification, err := sh.audit.Verify()
+	if err != nil {
+		problem := problemFromError(err)
+		return VerifyAuditLogdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	response := VerifyAuditLog200JSONResponse{
+		Valid:  verification.FirstInvalidID == 0,
+		Events: verification.Events,
+	}
+
+	if verification.FirstInvalidID != 0 {
+		response.FirstInvalidId = &verification.FirstInvalidID
+	}
+
+	return response, nil
+}
@@ -0,0 +1,328 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+// fakeSandboxes returns the single sandbox, the rest of the interface is not used
+type fakeSandboxes struct {
+	models.SandboxController
+	sandbox models.SandboxDetails
+}
+
+func (f *fakeSandboxes) GetByUUID(id string) (models.SandboxDetails, error) {
+	if id != f.sandbox.UUID {
+		return models.SandboxDetails{}, models.ErrSandboxNotFound
+	}
+
+	return f.sandbox, nil
+}
+
+// fakeAudit keeps the recorded events, the rest of the interface is not used
+type fakeAudit struct {
+	models.AuditController
+	events []models.AuditEvent
+}
+
+func (f *fakeAudit) Record(event models.AuditEvent) (models.AuditEvent, error) {
+	f.events = append(f.events, event)
+
+	return event, nil
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	log.InitLoggers(false)
+
+	swagger, err := GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	swagger.Servers = nil
+
+	fa, err := NewFakeAuthenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := fa.CreateJSWWithClaims("writer", []string{models.PermissionWrite}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &Authenticator{Validator: fa}
+	validator, err := NewRequestValidator(swagger, a.Authenticate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const id = "065293e2-238c-49ff-8f65-8036bce30174"
+
+	sandboxes := &fakeSandboxes{sandbox: models.SandboxDetails{UUID: id, Name: "sbx", Status: models.StatusRunning}}
+	audit := &fakeAudit{}
+	sh := NewSandboxHandler(sandboxes, nil, nil, audit)
+
+	// Stops the sandbox, or fails if asked to
+	handler := RequestID(PrincipalContext(validator(sh.Audit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			writeProblem(w, problemFromError(models.ErrWrongStatus))
+			return
+		}
+
+		sandboxes.sandbox.Status = models.StatusStopped
+		w.WriteHeader(http.StatusAccepted)
+	})))))
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		requestID string
+		want      *models.AuditEvent
+	}{
+		{
+			name:      "stopped",
+			method:    http.MethodPost,
+			path:      "/sandboxes/" + id + ":stop",
+			requestID: "req-1",
+			want: &models.AuditEvent{
+				Actor: "writer", Action: "sandbox.stop", TargetType: auditTargetSandbox, TargetID: id, RequestID: "req-1",
+				Outcome: models.AuditOutcomeSuccess, StatusCode: http.StatusAccepted,
+				Before: map[string]interface{}{"status": "RUNNING"},
+				After:  map[string]interface{}{"status": "STOPPED"},
+			},
+		},
+		{
+			name:      "failed",
+			method:    http.MethodPost,
+			path:      "/sandboxes/" + id + ":start?fail=1",
+			requestID: "req-2",
+			want: &models.AuditEvent{
+				Actor: "writer", Action: "sandbox.start", TargetType: auditTargetSandbox, TargetID: id, RequestID: "req-2",
+				Outcome: models.AuditOutcomeFailure, StatusCode: http.StatusConflict,
+				After: map[string]interface{}{"code": "WrongStatus"},
+			},
+		},
+		{
+			name:   "read",
+			method: http.MethodGet,
+			path:   "/sandboxes/" + id,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit.events = nil
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			r.Header.Set("Authorization", "Bearer "+string(writer))
+			if tt.requestID != "" {
+				r.Header.Set(RequestIDHeader, tt.requestID)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if tt.want == nil {
+				if len(audit.events) != 0 {
+					t.Errorf("recorded %+v, want nothing", audit.events)
+				}
+				return
+			}
+
+			if len(audit.events) != 1 {
+				t.Fatalf("recorded %d events, want 1: %d %s", len(audit.events), w.Code, w.Body.String())
+			}
+
+			if !reflect.DeepEqual(audit.events[0], *tt.want) {
+				t.Errorf("recorded %+v, want %+v", audit.events[0], *tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditRejected(t *testing.T) {
+	log.InitLoggers(false)
+
+	swagger, err := GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	swagger.Servers = nil
+
+	fa, err := NewFakeAuthenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := fa.CreateJSWWithClaims("reader", []string{models.PermissionRead}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := fa.CreateJSWWithClaims("writer", []string{models.PermissionWrite}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &Authenticator{Validator: fa}
+	validator, err := NewRequestValidator(swagger, a.Authenticate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const id = "065293e2-238c-49ff-8f65-8036bce30174"
+
+	audit := &fakeAudit{}
+	sh := NewSandboxHandler(&fakeSandboxes{}, nil, nil, audit)
+
+	handler := RequestID(PrincipalContext(sh.AuditRejected(validator(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})))))
+
+	tests := []struct {
+		name       string
+		token      string
+		wantActor  string
+		wantStatus int
+	}{
+		{"no token", "", "", http.StatusUnauthorized},
+		{"invalid token", "invalid", "", http.StatusUnauthorized},
+		{"insufficient scope", string(reader), "reader", http.StatusForbidden},
+		{"authenticated", string(writer), "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit.events = nil
+
+			r := httptest.NewRequest(http.MethodPost, "/sandboxes/"+id+":stop", nil)
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			r.Header.Set(RequestIDHeader, "req-1")
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if tt.wantStatus == 0 {
+				if len(audit.events) != 0 {
+					t.Errorf("recorded %+v, want nothing", audit.events)
+				}
+				return
+			}
+
+			if len(audit.events) != 1 {
+				t.Fatalf("recorded %d events, want 1: %d %s", len(audit.events), w.Code, w.Body.String())
+			}
+
+			event := audit.events[0]
+			if event.Actor != tt.wantActor || event.Action != auditActionAuthRejected || event.Outcome != models.AuditOutcomeFailure ||
+				event.StatusCode != tt.wantStatus || event.TargetType != auditTargetSandbox || event.TargetID != id || event.RequestID != "req-1" {
+				t.Errorf("recorded %+v", event)
+			}
+
+			if event.After["operation"] != "StopSandbox" || event.After["reason"] == "" {
+				t.Errorf("recorded after %+v", event.After)
+			}
+		})
+	}
+}
+
+func TestItemEvents(t *testing.T) {
+	event := models.AuditEvent{
+		Actor: "admin", Action: "sandbox.batch", TargetType: auditTargetSandbox, RequestID: "req-1",
+		Outcome: models.AuditOutcomeSuccess, StatusCode: http.StatusOK,
+	}
+
+	body := `{"dryRun": false, "results": [
+		{"index": 0, "action": "stop", "sandboxId": "a", "status": "ACCEPTED"},
+		{"index": 1, "action": "stop", "sandboxId": "b", "status": "FAILED", "error": {"status": 409, "code": "WrongStatus"}}
+	]}`
+
+	want := []models.AuditEvent{
+		{
+			Actor: "admin", Action: "sandbox.batch", TargetType: auditTargetSandbox, TargetID: "a", RequestID: "req-1",
+			Outcome: models.AuditOutcomeSuccess, StatusCode: http.StatusOK,
+			After: map[string]interface{}{"action": "stop", "status": "ACCEPTED"},
+		},
+		{
+			Actor: "admin", Action: "sandbox.batch", TargetType: auditTargetSandbox, TargetID: "b", RequestID: "req-1",
+			Outcome: models.AuditOutcomeFailure, StatusCode: http.StatusConflict,
+			After: map[string]interface{}{"action": "stop", "status": "FAILED", "code": "WrongStatus"},
+		},
+	}
+
+	events, ok := itemEvents("BatchSandboxes", event, []byte(body))
+	if !ok || !reflect.DeepEqual(events, want) {
+		t.Errorf("itemEvents() = %+v, %v, want %+v", events, ok, want)
+	}
+
+	if _, ok := itemEvents("BatchSandboxes", event, []byte(`{"dryRun": false, "results": []}`)); ok {
+		t.Error("itemEvents() split the batch without items")
+	}
+
+	if _, ok := itemEvents("StopSandbox", event, []byte(`{}`)); ok {
+		t.Error("itemEvents() split the single target operation")
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"kept", "0f8fad5b-d9cb-469f-a165-70867728950e", true},
+		{"generated", "", false},
+		{"not printable", "id with spaces", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = RequestIDFromContext(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/sandboxes", nil)
+			r.Header.Set(RequestIDHeader, tt.header)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if got == "" || (got == tt.header) != tt.keep {
+				t.Errorf("request id = %q, want kept %v of %q", got, tt.keep, tt.header)
+			}
+			if w.Header().Get(RequestIDHeader) != got {
+				t.Errorf("%s = %q, want %q", RequestIDHeader, w.Header().Get(RequestIDHeader), got)
+			}
+		})
+	}
+}
+
+func TestAuditedOperationsExist(t *testing.T) {
+	swagger, err := GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	operations := map[string]bool{}
+	for _, item := range swagger.Paths {
+		for _, operation := range item.Operations() {
+			operations[operation.OperationID] = true
+		}
+	}
+
+	for operationID := range auditedOperations {
+		if !operations[operationID] {
+			t.Errorf("audited operation %s is not in the spec", operationID)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+// Helper to convert the items listed in the request
+func toBatchItems(items []BatchItem) []models.BatchItem {
+	batchItems := make([]models.BatchItem, 0, len(items))
+
+	for _, item := range items {
+		batchItem := models.BatchItem{
+			Action:  string(item.Action),
+			StartAt: item.StartAt,
+			Options: toSandboxOptions(item.Role, item.Template),
+		}
+
+		if item.Id != nil {
+			batchItem.ID = *item.Id
+		}
+		if item.Name != nil {
+			batchItem.Name = *item.Name
+		}
+		if item.ExpiresAt != nil {
+			batchItem.ExpiresAt = *item.ExpiresAt
+		}
+		if item.Labels != nil {
+			batchItem.Labels = *item.Labels
+		}
+
+		batchItems = append(batchItems, batchItem)
+	}
+
+	return batchItems
+}
+
+// Helper to resolve the sandboxes matching the selector of the request
+func selectBatchItems(controller models.SandboxController, request BatchRequest) ([]models.BatchItem, error) {
+	filter := models.SandboxFilter{}
+
+	selector := request.Selector
+	if selector.Status != nil {
+		for _, status := range *selector.Status {
+			filter.Statuses = append(filter.Statuses, string(status))
+		}
+	}
+	if selector.Owner != nil {
+		filter.Owner = *selector.Owner
+	}
+	if selector.NamePrefix != nil {
+		filter.NamePrefix = *selector.NamePrefix
+	}
+	if selector.LabelSelector != nil {
+		labels, err := models.ParseLabelSelector(*selector.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		filter.Selector = labels
+	}
+
+	action := ""
+	if request.Action != nil {
+		action = string(*request.Action)
+	}
+
+	expiresAt := time.Time{}
+	if request.ExpiresAt != nil {
+		expiresAt = *request.ExpiresAt
+	}
+
+	return models.SelectBatchItems(controller, filter, action, expiresAt)
+}
+
+func toBatchResult(index int, result models.BatchResult, dryRun bool) BatchResult {
+	batchResult := BatchResult{
+		Index:  index,
+		Action: result.Item.Action,
+		Status: BatchResultStatusACCEPTED,
+	}
+
+	if result.SandboxID != "" {
+		batchResult.SandboxId = String(result.SandboxID)
+	}
+
+	switch {
+	case result.Err != nil:
+		problem := problemFromError(result.Err)
+		batchResult.Status = BatchResultStatusFAILED
+		batchResult.Error = &problem
+	case dryRun:
+		batchResult.Status = BatchResultStatusPLANNED
+	default:
+		operation := toOperation(result.Operation)
+		batchResult.Operation = &operation
+	}
+
+	return batchResult
+}
+
+func (sh *SandboxHandler) BatchSandboxes(ctx context.Context, request BatchSandboxesRequestObject) (BatchSandboxesResponseObject, error) {
+	body := request.Body
+
+	var items []models.BatchItem
+	var err error
+
+	switch {
+	case body.Items != nil && body.Selector != nil:
+		err = models.NewValidationError("either items or selector must be set, not both")
+	case body.Items != nil:
+		items = toBatchItems(*body.Items)
+	case body.Selector != nil:
+		items, err = selectBatchItems(sh.instances, *body)
+	default:
+		err = models.NewValidationError("either items or selector must be set")
+	}
+
+	if err != nil {
+		problem := problemFromError(err)
+		return BatchSandboxesdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	dryRun := body.DryRun != nil && *body.DryRun
+
+	results := models.RunBatch(sh.instances, items, principalFromContext(ctx), dryRun)
+
+	response := BatchResponse{
+		DryRun:  dryRun,
+		Results: make([]BatchResult, 0, len(results)),
+	}
+
+	failed := 0
+	for i, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+		response.Results = append(response.Results, toBatchResult(i, result, dryRun))
+	}
+
+	log.Logger.Info("Sandbox batch processed", "items", len(results), "failed", failed, "dryRun", dryRun)
+
+	return BatchSandboxes200JSONResponse(response), nil
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+var knownPermissions = map[string]bool{
+	models.PermissionRead:    true,
+	models.PermissionWrite:   true,
+	models.PermissionApprove: true,
+	models.PermissionAdmin:   true,
+}
+
+// KnownPermission tells the permissions the API checks from the rest
+func KnownPermission(permission string) bool {
+	return knownPermissions[permission]
+}
+
+// The permissions granted along with the permission, the approvals are kept
+// apart from the admins on purpose
+var impliedPermissions = map[string][]string{
+	models.PermissionAdmin: {models.PermissionWrite, models.PermissionRead},
+	models.PermissionWrite: {models.PermissionRead},
+}
+
+// Claims of the Entra ID tokens the permissions are mapped from
+const (
+	rolesClaim  = "roles"
+	groupsClaim = "groups"
+	scopesClaim = "scp"
+)
+
+// ClaimMapping grants the permissions to the tokens by the values of their
+// Entra ID claims: the app roles, the group object ids and the delegated
+// scopes. The values missing in the mapping grant nothing.
+type ClaimMapping struct {
+	Roles  map[string][]string `json:"roles"`
+	Groups map[string][]string `json:"groups"`
+	Scopes map[string][]string `json:"scopes"`
+}
+
+// LoadClaimMapping reads the mapping from the JSON file, e.g.
+//
+//	{"roles": {"Sandbox.Admin": ["sandbox:admin"]}, "groups": {"<group id>": ["sandbox:w"]}}
+func LoadClaimMapping(path string) (ClaimMapping, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ClaimMapping{}, err
+	}
+	defer file.Close()
+
+	mapping := ClaimMapping{}
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&mapping); err != nil {
+		return ClaimMapping{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for claim, values := range map[string]map[string][]string{rolesClaim: mapping.Roles, groupsClaim: mapping.Groups, scopesClaim: mapping.Scopes} {
+		for value, permissions := range values {
+			for _, permission := range permissions {
+				if !knownPermissions[permission] {
+					return ClaimMapping{}, fmt.Errorf("%s %q is mapped to unknown permission %q", claim, value, permission)
+				}
+			}
+		}
+	}
+
+	return mapping, nil
+}
+
+// Permissions returns the permissions of the token, the ones of its perm
+// claim and the ones mapped from its roles, groups and scopes, along with the
+// permissions they imply. The perm claim is read only from the tokens issued
+// by the service itself, the external issuers could set it to anything.
+func (m ClaimMapping) Permissions(t jwt.Token) ([]string, error) {
+	permissions := []string{}
+
+	if t.Issuer() == FakeIssuer {
+		var err error
+
+		permissions, err = GetClaimsFromToken(t)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for claim, values := range map[string]map[string][]string{rolesClaim: m.Roles, groupsClaim: m.Groups, scopesClaim: m.Scopes} {
+		if len(values) == 0 {
+			continue
+		}
+
+		tokenValues, err := stringsClaim(t, claim)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, value := range tokenValues {
+			permissions = append(permissions, values[value]...)
+		}
+	}
+
+	for _, permission := range permissions {
+		permissions = append(permissions, impliedPermissions[permission]...)
+	}
+
+	return models.UniqueScopes(permissions), nil
+}
+
+// stringsClaim returns the values of the list claim. The scp claim is a
+// single string of the space separated scopes instead.
+func stringsClaim(t jwt.Token, name string) ([]string, error) {
+	raw, found := t.Get(name)
+	if !found {
+		return []string{}, nil
+	}
+
+	if value, ok := raw.(string); ok {
+		return strings.Fields(value), nil
+	}
+
+	rawList, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'%s' claim is unexpected type", name)
+	}
+
+	values := make([]string, len(rawList))
+
+	for i, rawValue := range rawList {
+		var ok bool
+		values[i], ok = rawValue.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] is not a string", name, i)
+		}
+	}
+
+	return values, nil
+}
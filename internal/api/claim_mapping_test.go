@@ -0,0 +1,112 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+func TestClaimMappingPermissions(t *testing.T) {
+	mapping := ClaimMapping{
+		Roles:  map[string][]string{"Sandbox.Admin": {models.PermissionAdmin}, "Sandbox.Approver": {models.PermissionApprove}},
+		Groups: map[string][]string{"6f1b2c3d-0000-4000-8000-000000000001": {models.PermissionWrite}},
+		Scopes: map[string][]string{"Sandbox.Read": {models.PermissionRead}, "Sandbox.Write": {models.PermissionWrite}},
+	}
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		want   []string
+	}{
+		{"no claims", nil, []string{}},
+		{"perm claim", map[string]interface{}{jwt.IssuerKey: FakeIssuer, PermissionsClaim: []string{models.PermissionWrite}}, []string{models.PermissionWrite, models.PermissionRead}},
+		{"admin", map[string]interface{}{jwt.IssuerKey: FakeIssuer, PermissionsClaim: []string{models.PermissionAdmin}}, []string{models.PermissionAdmin, models.PermissionWrite, models.PermissionRead}},
+		{"perm claim of external issuer", map[string]interface{}{
+			jwt.IssuerKey:    "https://login.microsoftonline.com/tenant/v2.0",
+			PermissionsClaim: []string{models.PermissionAdmin},
+		}, []string{}},
+		{"app role", map[string]interface{}{rolesClaim: []string{"Sandbox.Approver", "Other.Role"}}, []string{models.PermissionApprove}},
+		{"group", map[string]interface{}{groupsClaim: []string{"6f1b2c3d-0000-4000-8000-000000000001"}}, []string{models.PermissionWrite, models.PermissionRead}},
+		{"space separated scopes", map[string]interface{}{scopesClaim: "Sandbox.Read Sandbox.Write"}, []string{models.PermissionRead, models.PermissionWrite}},
+		{"repeated permission", map[string]interface{}{
+			jwt.IssuerKey:    FakeIssuer,
+			PermissionsClaim: []string{models.PermissionWrite},
+			groupsClaim:      []string{"6f1b2c3d-0000-4000-8000-000000000001"},
+		}, []string{models.PermissionWrite, models.PermissionRead}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jwt.New()
+			for name, value := range tt.claims {
+				if err := token.Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// The tokens are parsed from JSON, like the validated ones
+			token = roundTrip(t, token)
+
+			got, err := mapping.Permissions(token)
+			if err != nil {
+				t.Fatalf("Permissions() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Permissions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadClaimMapping(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		valid bool
+	}{
+		{"valid", `{"roles": {"Sandbox.Admin": ["sandbox:admin"]}, "scopes": {"Sandbox.Read": ["sandbox:r"]}}`, true},
+		{"empty", `{}`, true},
+		{"unknown permission", `{"roles": {"Sandbox.Admin": ["sandbox:root"]}}`, false},
+		{"unknown claim", `{"wids": {"62e90394-69f5-4237-9190-012177145e10": ["sandbox:admin"]}}`, false},
+		{"not json", `roles: {}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "mapping.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadClaimMapping(path)
+			if tt.valid && err != nil {
+				t.Errorf("LoadClaimMapping() error = %v, want nil", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("LoadClaimMapping() error = nil, want the mapping rejected")
+			}
+		})
+	}
+}
+
+// Helper to get the token as it is after parsing
+func roundTrip(t *testing.T, token jwt.Token) jwt.Token {
+	t.Helper()
+
+	data, err := jwt.NewSerializer().Serialize(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := jwt.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed
+}
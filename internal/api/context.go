@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the id of the request, the audit events of the
+// request are recorded with it
+const RequestIDHeader = "X-Request-ID"
+
+// The request ids of the clients are kept only if they are short and printable
+var requestIDPattern = regexp.MustCompile(`^[\x21-\x7e]{1,128}$`)
+
+type contextKey int
+
+const (
+	principalContextKey contextKey = iota
+	requestURLContextKey
+	requestIDContextKey
+)
+
+// RequestID keeps the X-Request-ID of the client, or generates a new one, and
+// returns it in the response. It has to be used before the OpenAPI
+// validation middleware, so even the rejected requests have one.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := context.WithValue(r.Context(), requestIDContextKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext returns the id of the request set by RequestID
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+
+	return id
+}
+
+// RequestURL is a strict middleware making the request URL available to the
+// handlers, e.g. to build the links to the next pages
+func RequestURL(f StrictHandlerFunc, operationID string) StrictHandlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return f(context.WithValue(ctx, requestURLContextKey, r.URL), w, r, request)
+	}
+}
+
+// Helper to get the request URL stored by RequestURL
+func requestURLFromContext(ctx context.Context) *url.URL {
+	u, ok := ctx.Value(requestURLContextKey).(*url.URL)
+	if !ok {
+		return &url.URL{}
+	}
+
+	return u
+}
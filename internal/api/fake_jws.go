@@ -3,8 +3,11 @@ package api
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/ecdsafile"
+	"github.com/google/uuid"
 	"github.com/lestrrat-go/jwx/jwa"
 	"github.com/lestrrat-go/jwx/jwk"
 	"github.com/lestrrat-go/jwx/jws"
@@ -41,7 +44,24 @@ var _ JWSValidator = (*FakeAuthenticator)(nil)
 // NewFakeAuthenticator crates an authenticator example which uses a hard coded
 // ECDSA key to validate JWT's that it has signed itself.
 func NewFakeAuthenticator() (*FakeAuthenticator, error) {
-	privKey, err := ecdsafile.LoadEcdsaPrivateKey([]byte(PrivateKey))
+	return NewFakeAuthenticatorWithKey([]byte(PrivateKey))
+}
+
+// LoadFakeAuthenticator reads the PEM encoded ECDSA private key from the file,
+// so the development tokens can't be minted by anyone who has the sources
+func LoadFakeAuthenticator(path string) (*FakeAuthenticator, error) {
+	pemKey, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFakeAuthenticatorWithKey(pemKey)
+}
+
+// NewFakeAuthenticatorWithKey creates the authenticator signing and validating
+// the tokens with the PEM encoded ECDSA private key
+func NewFakeAuthenticatorWithKey(pemKey []byte) (*FakeAuthenticator, error) {
+	privKey, err := ecdsafile.LoadEcdsaPrivateKey(pemKey)
 	if err != nil {
 		return nil, fmt.Errorf("loading PEM private key: %w", err)
 	}
@@ -72,8 +92,8 @@ func NewFakeAuthenticator() (*FakeAuthenticator, error) {
 // ValidateJWS ensures that the crititcal JWT claims needed to ensure that
 // we trust the JWT are present and with the correct values.
 func (f *FakeAuthenticator) ValidateJWS(jwsString string) (jwt.Token, error) {
-	return jwt.Parse([]byte(jwsString), jwt.WithKeySet(f.KeySet),
-		jwt.WithAudience(FakeAudience), jwt.WithIssuer(FakeIssuer))
+	return jwt.Parse([]byte(jwsString), jwt.WithKeySet(f.KeySet), jwt.WithValidate(true),
+		jwt.WithRequiredClaim(jwt.ExpirationKey), jwt.WithAudience(FakeAudience), jwt.WithIssuer(FakeIssuer))
 }
 
 func (f *FakeAuthenticator) SignToken(t jwt.Token) ([]byte, error) {
@@ -93,20 +113,43 @@ func (f *FakeAuthenticator) SignToken(t jwt.Token) ([]byte, error) {
 	return jwt.Sign(t, jwa.ES256, f.PrivateKey, jwt.WithHeaders(hdr))
 }
 
-// CreateJWSWithClaims is a helper function to create JWT's with the specified
-// claims.
-func (f *FakeAuthenticator) CreateJSWWithClaims(claims []string) ([]byte, error) {
+// CreateJWSWithClaims is a helper function to create JWT's for the subject
+// with the specified claims. The token expires after the ttl and has its own
+// jti, so it can be revoked.
+func (f *FakeAuthenticator) CreateJSWWithClaims(subject string, claims []string, ttl time.Duration) ([]byte, error) {
+	now := time.Now()
+
 	t := jwt.New()
 	err := t.Set(jwt.IssuerKey, FakeIssuer)
 	if err != nil {
 		return nil, fmt.Errorf("setting issuer: %w", err)
 	}
 
+	err = t.Set(jwt.SubjectKey, subject)
+	if err != nil {
+		return nil, fmt.Errorf("setting subject: %w", err)
+	}
+
 	err = t.Set(jwt.AudienceKey, FakeAudience)
 	if err != nil {
 		return nil, fmt.Errorf("setting audience %w", err)
 	}
 
+	err = t.Set(jwt.IssuedAtKey, now)
+	if err != nil {
+		return nil, fmt.Errorf("setting issued at: %w", err)
+	}
+
+	err = t.Set(jwt.ExpirationKey, now.Add(ttl))
+	if err != nil {
+		return nil, fmt.Errorf("setting expiration: %w", err)
+	}
+
+	err = t.Set(jwt.JwtIDKey, uuid.NewString())
+	if err != nil {
+		return nil, fmt.Errorf("setting jti: %w", err)
+	}
+
 	err = t.Set(PermissionsClaim, claims)
 	if err != nil {
 		return nil, fmt.Errorf("setting permissions claim: %w", err)
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+func TestFakeAuthenticatorExpiration(t *testing.T) {
+	fa, err := NewFakeAuthenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := fa.CreateJSWWithClaims("writer", []string{models.PermissionWrite}, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fa.ValidateJWS(string(expired)); err == nil {
+		t.Error("ValidateJWS() error = nil for the expired token")
+	}
+
+	// The token without exp never expires, so it is not accepted
+	forever := jwt.New()
+	_ = forever.Set(jwt.IssuerKey, FakeIssuer)
+	_ = forever.Set(jwt.AudienceKey, FakeAudience)
+	_ = forever.Set(jwt.SubjectKey, "writer")
+
+	signed, err := fa.SignToken(forever)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fa.ValidateJWS(string(signed)); err == nil {
+		t.Error("ValidateJWS() error = nil for the token without exp")
+	}
+}
+
+func TestLoadFakeAuthenticator(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "dev-key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fa, err := LoadFakeAuthenticator(path)
+	if err != nil {
+		t.Fatalf("LoadFakeAuthenticator() error = %v", err)
+	}
+
+	minted, err := fa.CreateJSWWithClaims("alice", []string{models.PermissionWrite}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fa.ValidateJWS(string(minted)); err != nil {
+		t.Errorf("ValidateJWS() error = %v for the token minted with the key", err)
+	}
+
+	// The tokens of the built-in key are not accepted with the own key
+	builtIn, err := NewFakeAuthenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := builtIn.CreateJSWWithClaims("alice", []string{models.PermissionWrite}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fa.ValidateJWS(string(other)); err == nil {
+		t.Error("ValidateJWS() error = nil for the token of the built-in key")
+	}
+}
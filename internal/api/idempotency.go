@@ -0,0 +1,173 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	// Attempts to reserve the key expiring or released meanwhile
+	maxIdempotencyAttempts = 3
+
+	codeIdempotencyKeyReused = "IdempotencyKeyReused"
+	codeIdempotencyKeyInUse  = "IdempotencyKeyInUse"
+)
+
+// responseRecorder passes the response through to the client and keeps a copy
+// of it, so it can be replayed later.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	headers    http.Header
+	body       bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	if rr.statusCode == 0 {
+		rr.statusCode = statusCode
+		rr.headers = rr.ResponseWriter.Header().Clone()
+	}
+
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.statusCode == 0 {
+		rr.WriteHeader(http.StatusOK)
+	}
+
+	rr.body.Write(b)
+
+	return rr.ResponseWriter.Write(b)
+}
+
+// requestHash fingerprints the request, so a key reused for a different
+// request can be detected.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + "\n" + r.URL.Path + "\n" + r.URL.RawQuery + "\n"))
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NewIdempotencyMiddleware makes POST, PATCH and DELETE requests carrying an
+// Idempotency-Key header safe to retry. The first response for a key is stored
+// for the ttl and replayed for the retries of the same caller. Reusing the key
+// for a different request results in 409 Conflict, as does the retry while the
+// request is in flight, up to the lockTimeout. It has to be used after the
+// OpenAPI validation middleware, which authenticates the caller.
+func NewIdempotencyMiddleware(store models.IdempotencyData, ttl time.Duration, lockTimeout time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || (r.Method != http.MethodPost && r.Method != http.MethodPatch && r.Method != http.MethodDelete) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				writeProblem(w, newProblem(http.StatusBadRequest, codeValidationFailed, "Idempotency-Key is too long"))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeProblem(w, newProblem(http.StatusBadRequest, codeBadRequest, "failed to read request body"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := requestHash(r, body)
+
+			// The keys of the anonymous requests are shared
+			owner := principalFromContext(r.Context()).Subject
+
+			// The key expiring or released between the reservation and the read
+			// is taken again, as if it was never used
+			for attempt := 1; ; attempt++ {
+				reserved, err := store.Reserve(owner, key, hash, ttl, lockTimeout)
+				if err != nil {
+					log.Logger.Error("Failed to reserve idempotency key", "err", err)
+					writeProblem(w, newProblem(http.StatusInternalServerError, codeInternal, ""))
+					return
+				}
+
+				if reserved {
+					break
+				}
+
+				record, err := store.Get(owner, key)
+				if errors.Is(err, models.ErrIdempotencyNotFound) && attempt < maxIdempotencyAttempts {
+					continue
+				}
+				if errors.Is(err, models.ErrIdempotencyNotFound) {
+					writeProblem(w, newProblem(http.StatusConflict, codeIdempotencyKeyInUse, "request with the same Idempotency-Key is in progress"))
+					return
+				}
+				if err != nil {
+					log.Logger.Error("Failed to get idempotent response", "err", err)
+					writeProblem(w, newProblem(http.StatusInternalServerError, codeInternal, ""))
+					return
+				}
+
+				replay(w, record, hash)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w}
+
+			defer func() {
+				// Server errors are not stored, so the client can retry with the same key
+				if recorder.statusCode == 0 || recorder.statusCode >= http.StatusInternalServerError {
+					if _, err := store.Release(owner, key); err != nil {
+						log.Logger.Error("Failed to release idempotency key", "err", err)
+					}
+					return
+				}
+
+				if _, err := store.Complete(owner, key, recorder.statusCode, recorder.headers, recorder.body.Bytes()); err != nil {
+					log.Logger.Error("Failed to store idempotent response", "err", err)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+// replay writes the stored response. The request id is the one of the retry,
+// so the retry can still be correlated with the logs.
+func replay(w http.ResponseWriter, record models.IdempotencyRecord, hash string) {
+	if record.RequestHash != hash {
+		writeProblem(w, newProblem(http.StatusConflict, codeIdempotencyKeyReused, "Idempotency-Key was already used for a different request"))
+		return
+	}
+
+	if record.StatusCode == 0 {
+		writeProblem(w, newProblem(http.StatusConflict, codeIdempotencyKeyInUse, "request with the same Idempotency-Key is in progress"))
+		return
+	}
+
+	for name, values := range record.Headers {
+		if http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(RequestIDHeader) {
+			continue
+		}
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+
+	_, _ = w.Write(record.Body)
+}
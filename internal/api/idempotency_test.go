@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+// fakeIdempotencyStore holds a single key. The key can expire right after the
+// reservation fails, as if its ttl ran out in between.
+type fakeIdempotencyStore struct {
+	record      *models.IdempotencyRecord
+	expireOnGet bool
+	completed   int
+}
+
+func (f *fakeIdempotencyStore) Reserve(owner string, key string, requestHash string, ttl time.Duration, lockTimeout time.Duration) (bool, error) {
+	if f.record != nil {
+		return false, nil
+	}
+
+	f.record = &models.IdempotencyRecord{Key: key, RequestHash: requestHash}
+
+	return true, nil
+}
+
+func (f *fakeIdempotencyStore) Get(owner string, key string) (models.IdempotencyRecord, error) {
+	if f.expireOnGet {
+		f.record = nil
+		f.expireOnGet = false
+	}
+
+	if f.record == nil {
+		return models.IdempotencyRecord{}, models.ErrIdempotencyNotFound
+	}
+
+	return *f.record, nil
+}
+
+func (f *fakeIdempotencyStore) Complete(owner string, key string, statusCode int, headers map[string][]string, body []byte) (bool, error) {
+	f.record.StatusCode = statusCode
+	f.record.Headers = headers
+	f.record.Body = body
+	f.completed++
+
+	return true, nil
+}
+
+func (f *fakeIdempotencyStore) Release(owner string, key string) (bool, error) {
+	f.record = nil
+
+	return true, nil
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	store := &fakeIdempotencyStore{}
+
+	calls := 0
+	handler := RequestID(NewIdempotencyMiddleware(store, time.Hour, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusAccepted)
+	})))
+
+	send := func(requestID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/sandboxes", nil)
+		r.Header.Set(IdempotencyKeyHeader, "key-1")
+		r.Header.Set(RequestIDHeader, requestID)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w
+	}
+
+	send("first")
+
+	t.Run("replay keeps the request id", func(t *testing.T) {
+		w := send("second")
+
+		if calls != 1 {
+			t.Fatalf("handler called %d times, want 1", calls)
+		}
+		if w.Header().Get(IdempotentReplayedHeader) != "true" || w.Code != http.StatusAccepted {
+			t.Fatalf("retry got %d, replayed %q, want the replayed 202", w.Code, w.Header().Get(IdempotentReplayedHeader))
+		}
+		if got := w.Header().Get(RequestIDHeader); got != "second" {
+			t.Errorf("retry got %s = %q, want %q", RequestIDHeader, got, "second")
+		}
+	})
+
+	t.Run("key expired before the replay", func(t *testing.T) {
+		store.expireOnGet = true
+
+		w := send("third")
+
+		if calls != 2 {
+			t.Fatalf("handler called %d times, want 2", calls)
+		}
+		if w.Code != http.StatusAccepted || w.Header().Get(IdempotentReplayedHeader) != "" {
+			t.Errorf("retry got %d, replayed %q, want a new 202", w.Code, w.Header().Get(IdempotentReplayedHeader))
+		}
+		if store.completed != 2 {
+			t.Errorf("responses stored %d times, want 2", store.completed)
+		}
+	})
+}
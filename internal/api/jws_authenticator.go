@@ -2,110 +2,333 @@ package api
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/makirill/sandbox-azure/internal/models"
 )
 
-//const JWTClaimsContextKey = "jwt_claims"
+// Claims of the token the principal is described by, as issued by Entra ID
+const (
+	nameClaim              = "name"
+	preferredUsernameClaim = "preferred_username"
+	tenantClaim            = "tid"
+)
+
+// principalHolder is put into the request context before the request
+// validation, so Authenticate can hand the principal of the validated token
+// over to the handlers
+type principalHolder struct {
+	principal *models.Principal
+	// rateClass is the class of the route the principal is limited by
+	rateClass string
+	// operationID and targetID tell the audit log what the request is about
+	operationID string
+	targetID    string
+	// authErr is why the authentication failed, rejectedSubject is the
+	// subject of the valid token lacking the permissions
+	authErr         error
+	rejectedSubject string
+}
+
+// PrincipalContext prepares the request context for the principal, it has to
+// be used before the OpenAPI validation middleware
+func PrincipalContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), principalContextKey, &principalHolder{})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// PrincipalFromContext returns the authenticated principal of the request, if any
+func PrincipalFromContext(ctx context.Context) (models.Principal, bool) {
+	holder, ok := ctx.Value(principalContextKey).(*principalHolder)
+	if !ok || holder.principal == nil {
+		return models.Principal{}, false
+	}
+
+	return *holder.principal, true
+}
+
+// Helper to describe the caller by the validated token
+func principalFromToken(t jwt.Token, permissions []string) models.Principal {
+	principal := models.Principal{
+		Subject:     t.Subject(),
+		Permissions: permissions,
+	}
+
+	if name, ok := stringClaim(t, nameClaim); ok {
+		principal.Name = name
+	} else if name, ok := stringClaim(t, preferredUsernameClaim); ok {
+		principal.Name = name
+	}
+
+	principal.Tenant, _ = stringClaim(t, tenantClaim)
+
+	return principal
+}
+
+// Helper to describe the caller by the personal access token, it acts as its
+// owner with the scopes of the token only
+func principalFromAPIToken(token models.APITokenDetails) models.Principal {
+	return models.Principal{
+		Subject:     token.Owner,
+		Permissions: token.Scopes,
+		APITokenID:  token.UUID,
+	}
+}
+
+// Helper to get the value of the string claim, if the token has it
+func stringClaim(t jwt.Token, name string) (string, bool) {
+	raw, found := t.Get(name)
+	if !found {
+		return "", false
+	}
+
+	value, ok := raw.(string)
+
+	return value, ok && value != ""
+}
+
+// ErrInsufficientScope is returned for the valid token which lacks the
+// permissions required by the operation, unlike the missing or invalid token
+var ErrInsufficientScope = errors.New("provided claims do not match expected scopes")
+
+// ErrTokenRevoked is returned for the valid token on the deny-list
+var ErrTokenRevoked = errors.New("token is revoked")
 
 // JWSValidator is used to validate JWS payloads and return a JWT if they're valid.
 type JWSValidator interface {
 	ValidateJWS(jws string) (jwt.Token, error)
 }
 
-// GetJWSFromRequest extracts a JWS string from an Authorization: Bearer <jws> header.
+// Errors of the credentials of the request, they are told apart in the
+// WWW-Authenticate challenge
+var (
+	ErrNoCredentials          = errors.New("no Authorization header or session cookie found")
+	ErrUnsupportedScheme      = errors.New("authorization scheme is not Bearer")
+	ErrMalformedAuthorization = errors.New("authorization header is malformed")
+	ErrCSRFTokenMismatch      = errors.New("CSRF token is missing or doesn't match the session")
+)
+
+// The b64token of RFC 6750, the JWTs and the API tokens are made of these
+var bearerTokenPattern = regexp.MustCompile(`^[A-Za-z0-9\-._~+/]+=*$`)
+
+// CSRFHeader has to repeat the value of the CSRF cookie in the unsafe requests
+// authenticated by the session cookie
+const CSRFHeader = "X-CSRF-Token"
+
+// The CSRF cookie is named after the session cookie
+const csrfCookieSuffix = "_csrf"
+
+// The methods which don't change anything, they are not guarded against CSRF
+var safeMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+}
+
+// GetJWSFromRequest extracts the token from the Authorization: Bearer <token>
+// header as defined by RFC 6750, the scheme is case-insensitive.
 func GetJWSFromRequest(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
-		return "", errors.New("no Authorization header found")
+		return "", ErrNoCredentials
+	}
+
+	scheme, token, found := strings.Cut(authHeader, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", ErrUnsupportedScheme
 	}
 
-	prefix := "BearerAuth "
-	if !strings.HasPrefix(authHeader, prefix) {
-		return "", errors.New("authorization header is malformed")
+	token = strings.TrimLeft(token, " ")
+	if !found || !bearerTokenPattern.MatchString(token) {
+		return "", ErrMalformedAuthorization
 	}
 
-	return strings.TrimPrefix(authHeader, prefix), nil
+	return token, nil
 }
 
-func NewAuthenticator(v JWSValidator) openapi3filter.AuthenticationFunc {
-	return func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
-		return Authenticate(v, ctx, input)
-	}
+// insufficientScopeError tells the scopes the operation requires, for the
+// WWW-Authenticate challenge
+type insufficientScopeError struct {
+	scopes []string
+}
+
+func (e *insufficientScopeError) Error() string {
+	return ErrInsufficientScope.Error() + ": " + strings.Join(e.scopes, " ")
+}
+
+func (e *insufficientScopeError) Unwrap() error {
+	return ErrInsufficientScope
+}
+
+// APITokenValidator returns the active personal access token by its secret,
+// see models.APITokens
+type APITokenValidator interface {
+	Validate(secret string) (models.APITokenDetails, error)
+}
+
+// RevocationChecker tells if the token is on the deny-list, by its id or by
+// its subject and the time it was issued at, see models.Revocations
+type RevocationChecker interface {
+	IsRevoked(tokenID string, subject string, issuedAt time.Time) bool
+}
+
+// Authenticator checks the credentials of the requests, the JWTs of the
+// identity provider and the personal access tokens
+type Authenticator struct {
+	Validator JWSValidator
+	Mapping   ClaimMapping
+	// APITokens validates the personal access tokens, they are rejected if nil
+	APITokens APITokenValidator
+	// Revocations denies the revoked tokens, none are denied if nil
+	Revocations RevocationChecker
+	// SessionCookie is the name of the cookie holding the token of the
+	// browsers, the cookies are ignored if empty
+	SessionCookie string
 }
 
 // Authenticate uses the specified validator to ensure a JWT is valid, then makes
-// sure that the claims provided by the JWT match the scopes as required by the API.
-func Authenticate(v JWSValidator, ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+// sure that the permissions of the JWT, see ClaimMapping, match the scopes as
+// required by the API. The personal access tokens, told apart by their
+// prefix, are checked against their own scopes instead.
+func (a *Authenticator) Authenticate(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
 	// Verify security scheme name
 	if input.SecuritySchemeName != "BearerAuth" {
 		return fmt.Errorf("security scheme %s != 'BearerAuth'", input.SecuritySchemeName)
 	}
 
-	// Validate JWS
-	jws, err := GetJWSFromRequest(input.RequestValidationInput.Request)
-	if err != nil {
-		return fmt.Errorf("getting jws: %w", err)
+	// The validator doesn't pass the request context down, so the principal
+	// is stored into the holder prepared by PrincipalContext. The operation
+	// is stored even if the authentication fails, for the audit log.
+	holder, ok := input.RequestValidationInput.Request.Context().Value(principalContextKey).(*principalHolder)
+	if !ok {
+		holder = &principalHolder{}
 	}
 
-	token, err := v.ValidateJWS(jws)
+	if route := input.RequestValidationInput.Route; route != nil && route.Operation != nil {
+		holder.operationID = route.Operation.OperationID
+		holder.targetID = input.RequestValidationInput.PathParams["id"]
+		if catalog, ok := input.RequestValidationInput.PathParams["catalog"]; ok {
+			holder.targetID = catalog
+		}
+	}
+
+	credential, err := a.credentialFromRequest(input.RequestValidationInput.Request)
 	if err != nil {
-		return fmt.Errorf("validating jws: %w", err)
+		holder.authErr = err
+		return fmt.Errorf("getting credentials: %w", err)
 	}
 
-	// Verify claims
-	err = CheckTokenClaims(input.Scopes, token)
+	var principal models.Principal
+
+	if strings.HasPrefix(credential, models.APITokenPrefix) {
+		principal, err = a.validateAPIToken(credential)
+	} else {
+		principal, err = a.validateJWS(credential)
+	}
 	if err != nil {
-		return fmt.Errorf("token claims don't match: %w", err)
+		holder.authErr = err
+		return err
 	}
 
-	// TODO: Store token in context, so handlers can use it
-	//ctx = context.WithValue(ctx, JWTClaimsContextKey, token) ????
+	if CheckTokenClaims(input.Scopes, principal.Permissions) != nil {
+		holder.authErr = ErrInsufficientScope
+		holder.rejectedSubject = principal.Subject
+		return &insufficientScopeError{scopes: input.Scopes}
+	}
+
+	holder.principal = &principal
+	holder.rateClass = rateClass(input.RequestValidationInput.Request.Method, input.Scopes)
 
 	return nil
 }
 
-// GetClaimsFromToken returns a list of claims from the token. We store these
-// as a list under the "perms" claim, short for permissions, to keep the token
-// shorter.
-func GetClaimsFromToken(t jwt.Token) ([]string, error) {
-	rawPerms, found := t.Get(PermissionsClaim)
-	if !found {
-		// The "perms" aren't present in the token, so this means that the token
-		// has none. The token is still valid since it passed signature validation.
-		return make([]string, 0), nil
+// credentialFromRequest returns the token of the Authorization header, or the
+// one of the session cookie if there is no header. The browsers send the
+// cookie along with the requests of any site, so the unsafe requests
+// authenticated by the cookie have to repeat the value of the CSRF cookie in
+// the CSRFHeader, the other sites can't read it.
+func (a *Authenticator) credentialFromRequest(r *http.Request) (string, error) {
+	credential, err := GetJWSFromRequest(r)
+	if !errors.Is(err, ErrNoCredentials) || a.SessionCookie == "" {
+		return credential, err
 	}
 
-	// rawPerms is an untyped JSON list, so it have to be converted to a list of strings
-	rawList, ok := rawPerms.([]interface{}) // TODO: not sure what is it?
-	if !ok {
-		return nil, fmt.Errorf("'%s' claim is unexpected type'", PermissionsClaim)
+	session, err := r.Cookie(a.SessionCookie)
+	if err != nil || session.Value == "" {
+		return "", ErrNoCredentials
 	}
 
-	claims := make([]string, len(rawList))
-
-	for i, rawClaim := range rawList {
-		var ok bool
-		claims[i], ok = rawClaim.(string)
-		if !ok {
-			return nil, fmt.Errorf("%s[%d] is not a string", PermissionsClaim, i)
+	if !safeMethods[r.Method] {
+		csrf, err := r.Cookie(a.SessionCookie + csrfCookieSuffix)
+		if err != nil || csrf.Value == "" || subtle.ConstantTimeCompare([]byte(csrf.Value), []byte(r.Header.Get(CSRFHeader))) != 1 {
+			return "", ErrCSRFTokenMismatch
 		}
 	}
 
-	return claims, nil
+	return session.Value, nil
+}
+
+func (a *Authenticator) validateJWS(jws string) (models.Principal, error) {
+	token, err := a.Validator.ValidateJWS(jws)
+	if err != nil {
+		return models.Principal{}, fmt.Errorf("validating jws: %w", err)
+	}
+
+	if a.isRevoked(token.JwtID(), token.Subject(), token.IssuedAt()) {
+		return models.Principal{}, ErrTokenRevoked
+	}
+
+	permissions, err := a.Mapping.Permissions(token)
+	if err != nil {
+		return models.Principal{}, fmt.Errorf("getting permissions from token: %w", err)
+	}
+
+	return principalFromToken(token, permissions), nil
 }
 
-func CheckTokenClaims(expectedClaims []string, t jwt.Token) error {
-	claims, err := GetClaimsFromToken(t)
+func (a *Authenticator) validateAPIToken(secret string) (models.Principal, error) {
+	if a.APITokens == nil {
+		return models.Principal{}, errors.New("API tokens are not accepted")
+	}
+
+	token, err := a.APITokens.Validate(secret)
 	if err != nil {
-		return fmt.Errorf("getting claims from token: %w", err)
+		return models.Principal{}, fmt.Errorf("validating API token: %w", err)
 	}
 
+	// The API token is revoked by its id too, and along with the other tokens
+	// of its owner
+	if a.isRevoked(token.UUID, token.Owner, token.CreatedAt) {
+		return models.Principal{}, ErrTokenRevoked
+	}
+
+	return principalFromAPIToken(token), nil
+}
+
+func (a *Authenticator) isRevoked(tokenID string, subject string, issuedAt time.Time) bool {
+	return a.Revocations != nil && a.Revocations.IsRevoked(tokenID, subject, issuedAt)
+}
+
+// GetClaimsFromToken returns a list of claims from the token. We store these
+// as a list under the "perms" claim, short for permissions, to keep the token
+// shorter. The token without the claim has none, it is still valid since it
+// passed signature validation.
+func GetClaimsFromToken(t jwt.Token) ([]string, error) {
+	return stringsClaim(t, PermissionsClaim)
+}
+
+// CheckTokenClaims makes sure the permissions include every expected scope
+func CheckTokenClaims(expectedClaims []string, claims []string) error {
 	claimsMap := make(map[string]bool, len(claims))
 	for _, claim := range claims {
 		claimsMap[claim] = true
@@ -113,7 +336,7 @@ func CheckTokenClaims(expectedClaims []string, t jwt.Token) error {
 
 	for _, expected := range expectedClaims {
 		if !claimsMap[expected] {
-			return errors.New("provided claims do not match expected scopes")
+			return ErrInsufficientScope
 		}
 	}
 
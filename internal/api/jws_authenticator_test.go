@@ -0,0 +1,347 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+func TestAuthenticatePrincipal(t *testing.T) {
+	fa, err := NewFakeAuthenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := fa.CreateJSWWithClaims("writer", []string{models.PermissionWrite}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		scopes    []string
+		principal *models.Principal
+		err       error
+	}{
+		{
+			name:   "granted",
+			scopes: []string{models.PermissionWrite},
+			principal: &models.Principal{
+				Subject:     "writer",
+				Permissions: []string{models.PermissionWrite, models.PermissionRead},
+			},
+		},
+		{
+			name:   "implied",
+			scopes: []string{models.PermissionRead},
+			principal: &models.Principal{
+				Subject:     "writer",
+				Permissions: []string{models.PermissionWrite, models.PermissionRead},
+			},
+		},
+		{"insufficient scope", []string{models.PermissionAdmin}, nil, ErrInsufficientScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/sandboxes", nil)
+			r.Header.Set("Authorization", "Bearer "+string(writer))
+
+			// The middleware prepares the context the principal ends up in
+			PrincipalContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				a := &Authenticator{Validator: fa}
+				err := a.Authenticate(r.Context(), &openapi3filter.AuthenticationInput{
+					RequestValidationInput: &openapi3filter.RequestValidationInput{Request: r},
+					SecuritySchemeName:     "BearerAuth",
+					Scopes:                 tt.scopes,
+				})
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Authenticate() error = %v, want %v", err, tt.err)
+				}
+
+				principal, ok := PrincipalFromContext(r.Context())
+				if ok != (tt.principal != nil) {
+					t.Fatalf("PrincipalFromContext() ok = %v", ok)
+				}
+				if ok && !reflect.DeepEqual(principal, *tt.principal) {
+					t.Errorf("PrincipalFromContext() = %+v, want %+v", principal, *tt.principal)
+				}
+			})).ServeHTTP(httptest.NewRecorder(), r)
+		})
+	}
+}
+
+// fakeAPITokens accepts the known secrets
+type fakeAPITokens map[string]models.APITokenDetails
+
+func (f fakeAPITokens) Validate(secret string) (models.APITokenDetails, error) {
+	token, ok := f[secret]
+	if !ok {
+		return models.APITokenDetails{}, models.ErrInvalidAPIToken
+	}
+
+	return token, nil
+}
+
+func TestAuthenticateAPIToken(t *testing.T) {
+	fa, err := NewFakeAuthenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := models.Principal{Subject: "user", Permissions: []string{models.PermissionRead}, APITokenID: "token-id"}
+	tokens := fakeAPITokens{models.APITokenPrefix + "reader": {UUID: "token-id", Owner: "user", Scopes: []string{models.PermissionRead}}}
+
+	tests := []struct {
+		name      string
+		apiTokens APITokenValidator
+		secret    string
+		scopes    []string
+		principal *models.Principal
+		err       error
+	}{
+		{"granted", tokens, models.APITokenPrefix + "reader", []string{models.PermissionRead}, &reader, nil},
+		{"insufficient scope", tokens, models.APITokenPrefix + "reader", []string{models.PermissionWrite}, nil, ErrInsufficientScope},
+		{"unknown", tokens, models.APITokenPrefix + "unknown", []string{models.PermissionRead}, nil, models.ErrInvalidAPIToken},
+		{"not accepted", nil, models.APITokenPrefix + "reader", []string{models.PermissionRead}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/sandboxes", nil)
+			r.Header.Set("Authorization", "Bearer "+tt.secret)
+
+			PrincipalContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				a := &Authenticator{Validator: fa, APITokens: tt.apiTokens}
+				err := a.Authenticate(r.Context(), &openapi3filter.AuthenticationInput{
+					RequestValidationInput: &openapi3filter.RequestValidationInput{Request: r},
+					SecuritySchemeName:     "BearerAuth",
+					Scopes:                 tt.scopes,
+				})
+				if (tt.principal == nil) != (err != nil) {
+					t.Fatalf("Authenticate() error = %v", err)
+				}
+				if tt.err != nil && !errors.Is(err, tt.err) {
+					t.Fatalf("Authenticate() error = %v, want %v", err, tt.err)
+				}
+
+				principal, ok := PrincipalFromContext(r.Context())
+				if ok != (tt.principal != nil) {
+					t.Fatalf("PrincipalFromContext() ok = %v", ok)
+				}
+				if ok && !reflect.DeepEqual(principal, *tt.principal) {
+					t.Errorf("PrincipalFromContext() = %+v, want %+v", principal, *tt.principal)
+				}
+			})).ServeHTTP(httptest.NewRecorder(), r)
+		})
+	}
+}
+
+// fakeRevocations denies the tokens by the id
+type fakeRevocations map[string]bool
+
+func (f fakeRevocations) IsRevoked(tokenID string, subject string, issuedAt time.Time) bool {
+	return f[tokenID] || f[subject]
+}
+
+func TestAuthenticateRevoked(t *testing.T) {
+	fa, err := NewFakeAuthenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := fa.CreateJSWWithClaims("writer", []string{models.PermissionWrite}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := fa.ValidateJWS(string(writer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := fakeAPITokens{models.APITokenPrefix + "token": {UUID: "token-id", Owner: "owner", Scopes: []string{models.PermissionRead}}}
+
+	tests := []struct {
+		name        string
+		credential  string
+		revocations RevocationChecker
+		revoked     bool
+	}{
+		{"not revoked", string(writer), fakeRevocations{"other": true}, false},
+		{"no deny-list", string(writer), nil, false},
+		{"revoked jti", string(writer), fakeRevocations{token.JwtID(): true}, true},
+		{"revoked subject", string(writer), fakeRevocations{"writer": true}, true},
+		{"revoked API token", models.APITokenPrefix + "token", fakeRevocations{"token-id": true}, true},
+		{"revoked API token owner", models.APITokenPrefix + "token", fakeRevocations{"owner": true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/sandboxes", nil)
+			r.Header.Set("Authorization", "Bearer "+tt.credential)
+
+			a := &Authenticator{Validator: fa, APITokens: tokens, Revocations: tt.revocations}
+			err := a.Authenticate(r.Context(), &openapi3filter.AuthenticationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{Request: r},
+				SecuritySchemeName:     "BearerAuth",
+				Scopes:                 []string{models.PermissionRead},
+			})
+
+			if tt.revoked && !errors.Is(err, ErrTokenRevoked) {
+				t.Errorf("Authenticate() error = %v, want %v", err, ErrTokenRevoked)
+			}
+			if !tt.revoked && err != nil {
+				t.Errorf("Authenticate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestGetJWSFromRequest(t *testing.T) {
+	tests := []struct {
+		header string
+		token  string
+		err    error
+	}{
+		{"Bearer abc.def-ghi_jkl", "abc.def-ghi_jkl", nil},
+		{"bearer abc", "abc", nil},
+		{"BEARER abc", "abc", nil},
+		{"Bearer  abc", "abc", nil},
+		{"Bearer sbx_abc~+/==", "sbx_abc~+/==", nil},
+		{"", "", ErrNoCredentials},
+		{"BearerAuth abc", "", ErrUnsupportedScheme},
+		{"Basic dXNlcjpwYXNz", "", ErrUnsupportedScheme},
+		{"Bearer", "", ErrMalformedAuthorization},
+		{"Bearer ", "", ErrMalformedAuthorization},
+		{"Bearer abc def", "", ErrMalformedAuthorization},
+		{"Bearer a=bc", "", ErrMalformedAuthorization},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/sandboxes", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+
+			token, err := GetJWSFromRequest(r)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("GetJWSFromRequest() error = %v, want %v", err, tt.err)
+			}
+			if token != tt.token {
+				t.Errorf("GetJWSFromRequest() = %q, want %q", token, tt.token)
+			}
+		})
+	}
+}
+
+func TestCredentialFromRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		sessionCookie string
+		method        string
+		header        string
+		cookies       map[string]string
+		csrfHeader    string
+		credential    string
+		err           error
+	}{
+		{"header", "session", http.MethodPost, "Bearer header-token", map[string]string{"session": "cookie-token"}, "", "header-token", nil},
+		{"cookie", "session", http.MethodGet, "", map[string]string{"session": "cookie-token"}, "", "cookie-token", nil},
+		{"cookies disabled", "", http.MethodGet, "", map[string]string{"session": "cookie-token"}, "", "", ErrNoCredentials},
+		{"no cookie", "session", http.MethodGet, "", nil, "", "", ErrNoCredentials},
+		{"unsafe with CSRF token", "session", http.MethodPost, "", map[string]string{"session": "cookie-token", "session_csrf": "csrf"}, "csrf", "cookie-token", nil},
+		{"unsafe without CSRF header", "session", http.MethodDelete, "", map[string]string{"session": "cookie-token", "session_csrf": "csrf"}, "", "", ErrCSRFTokenMismatch},
+		{"unsafe without CSRF cookie", "session", http.MethodPost, "", map[string]string{"session": "cookie-token"}, "csrf", "", ErrCSRFTokenMismatch},
+		{"unsafe with wrong CSRF token", "session", http.MethodPatch, "", map[string]string{"session": "cookie-token", "session_csrf": "csrf"}, "other", "", ErrCSRFTokenMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/sandboxes", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			if tt.csrfHeader != "" {
+				r.Header.Set(CSRFHeader, tt.csrfHeader)
+			}
+			for name, value := range tt.cookies {
+				r.AddCookie(&http.Cookie{Name: name, Value: value})
+			}
+
+			a := &Authenticator{SessionCookie: tt.sessionCookie}
+
+			credential, err := a.credentialFromRequest(r)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("credentialFromRequest() error = %v, want %v", err, tt.err)
+			}
+			if credential != tt.credential {
+				t.Errorf("credentialFromRequest() = %q, want %q", credential, tt.credential)
+			}
+		})
+	}
+}
+
+func TestPrincipalFromToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		want   models.Principal
+	}{
+		{
+			name: "entra user",
+			claims: map[string]interface{}{
+				jwt.SubjectKey:         "AAAAAAAAAAAAAAAAAAAAAIkzqFVrSaSaFHy782bbtaQ",
+				nameClaim:              "Alice Example",
+				preferredUsernameClaim: "alice@example.com",
+				tenantClaim:            "9188040d-6c67-4c5b-b112-36a304b66dad",
+			},
+			want: models.Principal{
+				Subject: "AAAAAAAAAAAAAAAAAAAAAIkzqFVrSaSaFHy782bbtaQ",
+				Name:    "Alice Example",
+				Tenant:  "9188040d-6c67-4c5b-b112-36a304b66dad",
+			},
+		},
+		{
+			name:   "preferred username",
+			claims: map[string]interface{}{jwt.SubjectKey: "user", preferredUsernameClaim: "alice@example.com"},
+			want:   models.Principal{Subject: "user", Name: "alice@example.com"},
+		},
+		{
+			name:   "subject only",
+			claims: map[string]interface{}{jwt.SubjectKey: "user"},
+			want:   models.Principal{Subject: "user"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jwt.New()
+			for name, value := range tt.claims {
+				if err := token.Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := principalFromToken(roundTrip(t, token), nil); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("principalFromToken() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// The context without the holder, e.g. of the background work, has no principal
+func TestPrincipalFromContextWithoutHolder(t *testing.T) {
+	if _, ok := PrincipalFromContext(context.Background()); ok {
+		t.Error("PrincipalFromContext() ok = true for the context without the principal")
+	}
+}
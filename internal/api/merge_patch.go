@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// SandboxMergePatch is the JSON merge patch (RFC 7396) of the sandbox. The
+// generated structs can't tell null, which clears the field, from the missing
+// field, so the body is decoded field by field. The unknown members and null
+// of the fields which can't be cleared are rejected, so a misspelled field
+// doesn't pass as a patch changing nothing.
+type SandboxMergePatch struct {
+	models.SandboxPatch
+}
+
+func (p *SandboxMergePatch) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	invalid := []models.FieldError{}
+
+	for name, value := range fields {
+		var err error
+
+		switch name {
+		case "expiresAt":
+			if string(value) == "null" {
+				invalid = append(invalid, models.FieldError{Field: name, Message: "can not be cleared"})
+				continue
+			}
+			var expiresAt time.Time
+			err = json.Unmarshal(value, &expiresAt)
+			p.ExpiresAt = &expiresAt
+		case "description":
+			p.Description, err = patchString(value)
+		case "costCenter":
+			p.CostCenter, err = patchString(value)
+		case "notes":
+			p.Notes, err = patchString(value)
+		case "keepWhenIdle":
+			var keep *bool
+			err = json.Unmarshal(value, &keep)
+			// null resets the opt-out
+			if keep == nil {
+				keep = new(bool)
+			}
+			p.KeepWhenIdle = keep
+		case "labels":
+			err = json.Unmarshal(value, &p.Labels)
+			// null removes all the labels, the same as if every label was set to null
+			p.ClearLabels = err == nil && p.Labels == nil
+		default:
+			invalid = append(invalid, models.FieldError{Field: name, Message: "is not a field of the sandbox"})
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(invalid) > 0 {
+		return &models.Error{
+			Kind:    models.KindValidation,
+			Code:    "ValidationFailed",
+			Message: "patch has invalid fields",
+			Fields:  invalid,
+		}
+	}
+
+	return nil
+}
+
+// Helper to decode the text field of the patch, null clears the field
+func patchString(value json.RawMessage) (*string, error) {
+	var text *string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return nil, err
+	}
+
+	if text == nil {
+		text = new(string)
+	}
+
+	return text, nil
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+func TestSandboxMergePatch(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		body   string
+		check  func(p models.SandboxPatch) bool
+		fields []string
+	}{
+		{
+			name:  "expiresAt",
+			body:  `{"expiresAt": "2030-01-02T03:04:05Z"}`,
+			check: func(p models.SandboxPatch) bool { return p.ExpiresAt != nil && p.ExpiresAt.Equal(expiresAt) },
+		},
+		{
+			name:  "null clears the text",
+			body:  `{"description": null}`,
+			check: func(p models.SandboxPatch) bool { return p.Description != nil && *p.Description == "" },
+		},
+		{
+			name:  "null resets keepWhenIdle",
+			body:  `{"keepWhenIdle": null}`,
+			check: func(p models.SandboxPatch) bool { return p.KeepWhenIdle != nil && !*p.KeepWhenIdle },
+		},
+		{
+			name: "labels are merged",
+			body: `{"labels": {"team": "payments", "env": null}}`,
+			check: func(p models.SandboxPatch) bool {
+				return !p.ClearLabels && len(p.Labels) == 2 && *p.Labels["team"] == "payments" && p.Labels["env"] == nil
+			},
+		},
+		{
+			name:  "null removes all labels",
+			body:  `{"labels": null}`,
+			check: func(p models.SandboxPatch) bool { return p.ClearLabels },
+		},
+		{
+			name:  "missing fields are left",
+			body:  `{}`,
+			check: func(p models.SandboxPatch) bool { return p.Empty() },
+		},
+		{
+			name:   "null expiresAt",
+			body:   `{"expiresAt": null}`,
+			fields: []string{"expiresAt"},
+		},
+		{
+			name:   "unknown field",
+			body:   `{"descripton": "typo"}`,
+			fields: []string{"descripton"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch := SandboxMergePatch{}
+			err := json.Unmarshal([]byte(tt.body), &patch)
+
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("Unmarshal() error = %v", err)
+				}
+				if !tt.check(patch.SandboxPatch) {
+					t.Errorf("Unmarshal() = %+v", patch.SandboxPatch)
+				}
+				return
+			}
+
+			var domainErr *models.Error
+			if !errors.As(err, &domainErr) || domainErr.Kind != models.KindValidation {
+				t.Fatalf("Unmarshal() error = %v, want validation error", err)
+			}
+
+			if len(domainErr.Fields) != len(tt.fields) || domainErr.Fields[0].Field != tt.fields[0] {
+				t.Errorf("Unmarshal() fields = %v, want %v", domainErr.Fields, tt.fields)
+			}
+		})
+	}
+}
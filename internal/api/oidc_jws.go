@@ -0,0 +1,275 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/lestrrat-go/jwx/jwt"
+)
+
+const (
+	// DefaultClockSkew is the clock difference to the issuer tolerated in exp and nbf
+	DefaultClockSkew = 2 * time.Minute
+	// DefaultJWKSRefreshInterval is how often the key sets are refreshed in the
+	// background, unless the issuer tells otherwise with the cache headers
+	DefaultJWKSRefreshInterval = time.Hour
+)
+
+// The key set of the issuer is refreshed for an unknown key id at most this
+// often, so the tokens with the made up key ids don't flood the issuer
+const unknownKeyRefreshInterval = time.Minute
+
+// How long the issuer gets to answer the discovery and the key set requests
+const oidcRequestTimeout = 10 * time.Second
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+var (
+	ErrUntrustedIssuer = errors.New("token issuer is not trusted")
+	ErrUnknownKey      = errors.New("token is signed with unknown key")
+)
+
+// OIDCConfig configures the validation of the tokens of the OpenID Connect
+// issuers, e.g. https://login.microsoftonline.com/<tenant>/v2.0 for Entra ID
+type OIDCConfig struct {
+	// Issuers are the trusted issuers, the iss claim must match one of them
+	Issuers []string
+	// Audience the aud claim must contain, e.g. the client id of the app
+	Audience string
+	// ClockSkew is tolerated in the exp and nbf claims, DefaultClockSkew if 0
+	ClockSkew time.Duration
+	// RefreshInterval is the least time between the background refreshes of
+	// the key sets, DefaultJWKSRefreshInterval if 0
+	RefreshInterval time.Duration
+	// HTTPClient for the discovery and the key set requests, http.DefaultClient if nil
+	HTTPClient *http.Client
+}
+
+// Make sure we conform to the JWSValidator interface
+var _ JWSValidator = (*OIDCValidator)(nil)
+
+// OIDCValidator validates the tokens of the configured issuers against their
+// key sets found by the OpenID Connect discovery. The key sets are cached and
+// refreshed in the background, and on the unknown key id to pick up the
+// rotated keys early.
+type OIDCValidator struct {
+	audience  string
+	clockSkew time.Duration
+	keySets   *jwk.AutoRefresh
+	// Key set URL by issuer
+	issuers map[string]string
+
+	lock sync.Mutex
+	// Last refresh for the unknown key id by key set URL
+	refreshedAt map[string]time.Time
+}
+
+type discoveryDocument struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// NewOIDCValidator discovers the key sets of the issuers and fetches them
+// once, so the misconfiguration fails at the start. The background refresh
+// stops when the context is done.
+func NewOIDCValidator(ctx context.Context, config OIDCConfig) (*OIDCValidator, error) {
+	if len(config.Issuers) == 0 {
+		return nil, errors.New("no issuers configured")
+	}
+
+	if config.Audience == "" {
+		return nil, errors.New("no audience configured")
+	}
+
+	if config.ClockSkew == 0 {
+		config.ClockSkew = DefaultClockSkew
+	}
+
+	if config.RefreshInterval == 0 {
+		config.RefreshInterval = DefaultJWKSRefreshInterval
+	}
+
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+
+	v := &OIDCValidator{
+		audience:    config.Audience,
+		clockSkew:   config.ClockSkew,
+		keySets:     jwk.NewAutoRefresh(ctx),
+		issuers:     make(map[string]string, len(config.Issuers)),
+		refreshedAt: make(map[string]time.Time),
+	}
+
+	for _, issuer := range config.Issuers {
+		issuer = strings.TrimSpace(issuer)
+
+		jwksURI, err := discover(ctx, config.HTTPClient, issuer)
+		if err != nil {
+			return nil, fmt.Errorf("discovery of %s: %w", issuer, err)
+		}
+
+		v.keySets.Configure(jwksURI,
+			jwk.WithHTTPClient(config.HTTPClient),
+			jwk.WithMinRefreshInterval(config.RefreshInterval))
+
+		fetchCtx, cancel := context.WithTimeout(ctx, oidcRequestTimeout)
+		_, err = v.keySets.Refresh(fetchCtx, jwksURI)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("key set of %s: %w", issuer, err)
+		}
+
+		v.issuers[issuer] = jwksURI
+	}
+
+	return v, nil
+}
+
+// discover returns the key set URL from the discovery document of the issuer
+func discover(ctx context.Context, client *http.Client, issuer string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, oidcRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(issuer, "/")+discoveryPath, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("discovery responded with %s", resp.Status)
+	}
+
+	doc := discoveryDocument{}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return "", err
+	}
+
+	// The multi-tenant documents, like the common one of Entra ID, have a
+	// template in place of the tenant, which no token matches
+	if doc.Issuer != issuer {
+		return "", fmt.Errorf("discovery document is for issuer %q", doc.Issuer)
+	}
+
+	if doc.JWKSURI == "" {
+		return "", errors.New("discovery document has no jwks_uri")
+	}
+
+	return doc.JWKSURI, nil
+}
+
+// ValidateJWS verifies the signature of the token with the key set of its
+// issuer and validates the issuer, the audience and the lifetime claims
+func (v *OIDCValidator) ValidateJWS(jwsString string) (jwt.Token, error) {
+	// Not verified yet, only to find the key set
+	unverified, err := jwt.Parse([]byte(jwsString))
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := unverified.Issuer()
+	jwksURI, ok := v.issuers[issuer]
+	if !ok {
+		return nil, ErrUntrustedIssuer
+	}
+
+	keyID, err := tokenKeyID(jwsString)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcRequestTimeout)
+	defer cancel()
+
+	keySet, err := v.keySet(ctx, jwksURI, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The keys of Entra ID have no alg, it is taken from the key type then
+	return jwt.Parse([]byte(jwsString),
+		jwt.WithKeySet(keySet),
+		jwt.InferAlgorithmFromKey(true),
+		jwt.WithValidate(true),
+		jwt.WithRequiredClaim(jwt.ExpirationKey),
+		jwt.WithAcceptableSkew(v.clockSkew),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(v.audience))
+}
+
+// keySet returns the cached key set with the key. The key set is refreshed if
+// the key is not there, it might be rotated since the last refresh.
+func (v *OIDCValidator) keySet(ctx context.Context, jwksURI string, keyID string) (jwk.Set, error) {
+	keySet, err := v.keySets.Fetch(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := keySet.LookupKeyID(keyID); ok {
+		return keySet, nil
+	}
+
+	if !v.allowRefresh(jwksURI) {
+		return nil, ErrUnknownKey
+	}
+
+	keySet, err = v.keySets.Refresh(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := keySet.LookupKeyID(keyID); !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return keySet, nil
+}
+
+// allowRefresh limits the refreshes for the unknown key ids
+func (v *OIDCValidator) allowRefresh(jwksURI string) bool {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	now := time.Now()
+	if now.Sub(v.refreshedAt[jwksURI]) < unknownKeyRefreshInterval {
+		return false
+	}
+
+	v.refreshedAt[jwksURI] = now
+
+	return true
+}
+
+// Helper to read the key id from the protected header of the token
+func tokenKeyID(jwsString string) (string, error) {
+	msg, err := jws.Parse([]byte(jwsString))
+	if err != nil {
+		return "", err
+	}
+
+	signatures := msg.Signatures()
+	if len(signatures) != 1 {
+		return "", errors.New("token must have exactly one signature")
+	}
+
+	keyID := signatures[0].ProtectedHeaders().KeyID()
+	if keyID == "" {
+		return "", errors.New("token has no key id")
+	}
+
+	return keyID, nil
+}
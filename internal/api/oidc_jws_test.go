@@ -0,0 +1,290 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/lestrrat-go/jwx/jwt"
+)
+
+const testAudience = "api://sandbox"
+
+// testIssuer serves the discovery document and the key set, like Entra ID
+// the keys have no alg
+type testIssuer struct {
+	server *httptest.Server
+
+	lock      sync.Mutex
+	keys      map[string]*rsa.PrivateKey
+	keySetHit int
+}
+
+func newTestIssuer(t *testing.T, keyIDs ...string) *testIssuer {
+	t.Helper()
+
+	issuer := &testIssuer{keys: make(map[string]*rsa.PrivateKey)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(discoveryDocument{
+			Issuer:  issuer.server.URL,
+			JWKSURI: issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", issuer.serveKeySet)
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	for _, keyID := range keyIDs {
+		issuer.addKey(t, keyID)
+	}
+
+	return issuer
+}
+
+func (i *testIssuer) serveKeySet(w http.ResponseWriter, r *http.Request) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.keySetHit++
+
+	set := jwk.NewSet()
+	for keyID, key := range i.keys {
+		public, err := jwk.New(&key.PublicKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_ = public.Set(jwk.KeyIDKey, keyID)
+		set.Add(public)
+	}
+
+	_ = json.NewEncoder(w).Encode(set)
+}
+
+func (i *testIssuer) addKey(t *testing.T, keyID string) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.keys[keyID] = key
+
+	return key
+}
+
+func (i *testIssuer) removeKey(keyID string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	delete(i.keys, keyID)
+}
+
+func (i *testIssuer) hits() int {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return i.keySetHit
+}
+
+func (i *testIssuer) validator(t *testing.T) *OIDCValidator {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	v, err := NewOIDCValidator(ctx, OIDCConfig{
+		Issuers:    []string{i.server.URL},
+		Audience:   testAudience,
+		HTTPClient: i.server.Client(),
+	})
+	if err != nil {
+		t.Fatalf("NewOIDCValidator() error = %v", err)
+	}
+
+	return v
+}
+
+// Helper to sign the token with the key of the issuer, the claims override
+// the valid defaults and the nil values remove them
+func (i *testIssuer) sign(t *testing.T, keyID string, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	t.Helper()
+
+	now := time.Now()
+	values := map[string]interface{}{
+		jwt.IssuerKey:     i.server.URL,
+		jwt.AudienceKey:   testAudience,
+		jwt.SubjectKey:    "user",
+		jwt.IssuedAtKey:   now,
+		jwt.ExpirationKey: now.Add(time.Hour),
+	}
+	for name, value := range claims {
+		values[name] = value
+	}
+
+	token := jwt.New()
+	for name, value := range values {
+		if value == nil {
+			continue
+		}
+		if err := token.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	headers := jws.NewHeaders()
+	_ = headers.Set(jws.KeyIDKey, keyID)
+
+	signed, err := jwt.Sign(token, jwa.RS256, key, jwt.WithHeaders(headers))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(signed)
+}
+
+func TestOIDCValidatorClaims(t *testing.T) {
+	issuer := newTestIssuer(t)
+	key := issuer.addKey(t, "key-1")
+	validator := issuer.validator(t)
+
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		valid  bool
+	}{
+		{"valid", nil, true},
+		{"expired within the skew", map[string]interface{}{jwt.ExpirationKey: now.Add(-time.Minute)}, true},
+		{"expired", map[string]interface{}{jwt.ExpirationKey: now.Add(-5 * time.Minute)}, false},
+		{"no expiration", map[string]interface{}{jwt.ExpirationKey: nil}, false},
+		{"not before within the skew", map[string]interface{}{jwt.NotBeforeKey: now.Add(time.Minute)}, true},
+		{"not yet valid", map[string]interface{}{jwt.NotBeforeKey: now.Add(5 * time.Minute)}, false},
+		{"wrong audience", map[string]interface{}{jwt.AudienceKey: "api://other"}, false},
+		{"no audience", map[string]interface{}{jwt.AudienceKey: nil}, false},
+		{"wrong issuer", map[string]interface{}{jwt.IssuerKey: "https://login.example.com/other"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := validator.ValidateJWS(issuer.sign(t, "key-1", key, tt.claims))
+
+			if tt.valid && err != nil {
+				t.Errorf("ValidateJWS() error = %v, want nil", err)
+			}
+			if tt.valid && err == nil && token.Subject() != "user" {
+				t.Errorf("ValidateJWS() subject = %q, want user", token.Subject())
+			}
+			if !tt.valid && err == nil {
+				t.Error("ValidateJWS() error = nil, want the token rejected")
+			}
+		})
+	}
+}
+
+func TestOIDCValidatorUntrustedIssuer(t *testing.T) {
+	trusted := newTestIssuer(t, "key-1")
+	other := newTestIssuer(t)
+	key := other.addKey(t, "key-1")
+
+	_, err := trusted.validator(t).ValidateJWS(other.sign(t, "key-1", key, nil))
+	if !errors.Is(err, ErrUntrustedIssuer) {
+		t.Errorf("ValidateJWS() error = %v, want %v", err, ErrUntrustedIssuer)
+	}
+}
+
+func TestOIDCValidatorWrongKey(t *testing.T) {
+	issuer := newTestIssuer(t, "key-1")
+	validator := issuer.validator(t)
+
+	// Signed with a key the issuer doesn't publish under the known key id
+	other := newTestIssuer(t)
+	key := other.addKey(t, "key-1")
+
+	if _, err := validator.ValidateJWS(issuer.sign(t, "key-1", key, nil)); err == nil {
+		t.Error("ValidateJWS() error = nil, want the signature rejected")
+	}
+}
+
+func TestOIDCValidatorUnknownKey(t *testing.T) {
+	issuer := newTestIssuer(t, "key-1")
+	validator := issuer.validator(t)
+
+	// The key is added after the key set is fetched
+	key := issuer.addKey(t, "key-2")
+	hits := issuer.hits()
+
+	if _, err := validator.ValidateJWS(issuer.sign(t, "key-2", key, nil)); err != nil {
+		t.Fatalf("ValidateJWS() error = %v, want nil", err)
+	}
+	if issuer.hits() != hits+1 {
+		t.Errorf("key set fetched %d times, want once", issuer.hits()-hits)
+	}
+
+	// Another unknown key id doesn't refresh the key set again right away
+	unknown := issuer.sign(t, "key-3", key, nil)
+	hits = issuer.hits()
+
+	for n := 0; n < 3; n++ {
+		if _, err := validator.ValidateJWS(unknown); !errors.Is(err, ErrUnknownKey) {
+			t.Errorf("ValidateJWS() error = %v, want %v", err, ErrUnknownKey)
+		}
+	}
+	if issuer.hits() != hits {
+		t.Errorf("key set fetched %d times, want none", issuer.hits()-hits)
+	}
+}
+
+func TestOIDCValidatorKeyRotation(t *testing.T) {
+	issuer := newTestIssuer(t)
+	oldKey := issuer.addKey(t, "key-1")
+	validator := issuer.validator(t)
+
+	if _, err := validator.ValidateJWS(issuer.sign(t, "key-1", oldKey, nil)); err != nil {
+		t.Fatalf("ValidateJWS() with the old key error = %v, want nil", err)
+	}
+
+	// The issuer rolls over to the new key and retires the old one
+	newKey := issuer.addKey(t, "key-2")
+	issuer.removeKey("key-1")
+
+	if _, err := validator.ValidateJWS(issuer.sign(t, "key-2", newKey, nil)); err != nil {
+		t.Fatalf("ValidateJWS() with the new key error = %v, want nil", err)
+	}
+
+	if _, err := validator.ValidateJWS(issuer.sign(t, "key-1", oldKey, nil)); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("ValidateJWS() with the retired key error = %v, want %v", err, ErrUnknownKey)
+	}
+}
+
+func TestNewOIDCValidatorIssuerMismatch(t *testing.T) {
+	issuer := newTestIssuer(t, "key-1")
+
+	// The discovery document is for the issuer without the trailing slash
+	_, err := NewOIDCValidator(context.Background(), OIDCConfig{
+		Issuers:    []string{issuer.server.URL + "/"},
+		Audience:   testAudience,
+		HTTPClient: issuer.server.Client(),
+	})
+	if err == nil {
+		t.Error("NewOIDCValidator() error = nil, want the discovery rejected")
+	}
+}
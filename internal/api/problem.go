@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+const problemContentType = "application/problem+json"
+
+// Stable codes of the problems raised outside of the models layer
+const (
+	codeInternal         = "InternalError"
+	codeBadRequest       = "BadRequest"
+	codeValidationFailed = "ValidationFailed"
+	codeUnauthorized     = "Unauthorized"
+	codeForbidden        = "Forbidden"
+	codeNotFound         = "NotFound"
+	codeMethodNotAllowed = "MethodNotAllowed"
+)
+
+// Helper to map the kind of the domain error to the HTTP status code
+func statusFromErrorKind(kind models.ErrorKind) int {
+	var statusMap = map[models.ErrorKind]int{
+		models.KindNotFound:           http.StatusNotFound,
+		models.KindConflict:           http.StatusConflict,
+		models.KindValidation:         http.StatusBadRequest,
+		models.KindQuotaExceeded:      http.StatusTooManyRequests,
+		models.KindUnauthorized:       http.StatusForbidden,
+		models.KindPreconditionFailed: http.StatusPreconditionFailed,
+	}
+
+	status, ok := statusMap[kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	return status
+}
+
+// newProblem builds RFC 7807 problem details with a generic type, the status
+// code and the code tell the clients what went wrong
+func newProblem(status int, code string, detail string) Problem {
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: int32(status),
+		Code:   code,
+	}
+
+	if detail != "" {
+		problem.Detail = &detail
+	}
+
+	return problem
+}
+
+// problemFromError converts an error of the models layer to problem details.
+// Anything but the domain errors is logged and reported as an internal error,
+// so database and Azure messages don't leak to the clients.
+func problemFromError(err error) Problem {
+	var domainErr *models.Error
+	if !errors.As(err, &domainErr) || domainErr.Kind == models.KindInternal {
+		log.Logger.Error("Internal error", "err", err)
+		return newProblem(http.StatusInternalServerError, codeInternal, "")
+	}
+
+	problem := newProblem(statusFromErrorKind(domainErr.Kind), domainErr.Code, domainErr.Message)
+	if len(domainErr.Fields) > 0 {
+		problem.Errors = toFieldErrors(domainErr.Fields)
+	}
+
+	return problem
+}
+
+func toFieldErrors(fields []models.FieldError) *[]FieldError {
+	fieldErrors := make([]FieldError, 0, len(fields))
+	for _, field := range fields {
+		fieldErrors = append(fieldErrors, FieldError{Field: field.Field, Message: field.Message})
+	}
+
+	return &fieldErrors
+}
+
+func writeProblem(w http.ResponseWriter, problem Problem) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(int(problem.Status))
+
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// RequestErrorHandler reports the requests which can't be decoded as problem
+// details, the invalid fields found by the decoders are listed
+func RequestErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var domainErr *models.Error
+	if errors.As(err, &domainErr) && domainErr.Kind == models.KindValidation {
+		writeProblem(w, problemFromError(domainErr))
+		return
+	}
+
+	writeProblem(w, newProblem(http.StatusBadRequest, codeBadRequest, err.Error()))
+}
+
+// ResponseErrorHandler reports failures of the handlers as problem details
+func ResponseErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	writeProblem(w, problemFromError(err))
+}
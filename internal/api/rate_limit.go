@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+// Headers of the rate limits, as in the IETF draft of the RateLimit header
+// fields for HTTP
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+	RetryAfterHeader         = "Retry-After"
+
+	codeTooManyRequests = "TooManyRequests"
+)
+
+// RateLimiter takes a request of the principal from the bucket of the class
+type RateLimiter interface {
+	Allow(principal models.Principal, class string) (models.RateLimitResult, error)
+	Limit(class string) models.RateLimit
+}
+
+// NewRateLimitMiddleware limits the requests of every principal by the class
+// of the route, the limits are reported in the RateLimit-* headers. The
+// requests over the limit get 429 Too Many Requests with Retry-After. The
+// failure of the store doesn't fail the requests. It has to be used after the
+// OpenAPI validation middleware, which authenticates the caller.
+func NewRateLimitMiddleware(limiter RateLimiter) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			holder, ok := r.Context().Value(principalContextKey).(*principalHolder)
+			if !ok || holder.principal == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := limiter.Allow(*holder.principal, holder.rateClass)
+			if err != nil {
+				log.Logger.Error("Failed to check rate limit", "err", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if result.Limit > 0 {
+				limit := limiter.Limit(holder.rateClass)
+
+				w.Header().Set(RateLimitLimitHeader, strconv.Itoa(result.Limit))
+				w.Header().Set(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+				w.Header().Set(RateLimitResetHeader, formatSeconds(result.ResetAfter))
+				w.Header().Set(RateLimitPolicyHeader, strconv.Itoa(limit.Requests)+";w="+formatSeconds(limit.Period))
+			}
+
+			if !result.Allowed {
+				w.Header().Set(RetryAfterHeader, formatSeconds(result.RetryAfter))
+				writeProblem(w, newProblem(http.StatusTooManyRequests, codeTooManyRequests,
+					"rate limit of the "+holder.rateClass+" requests exceeded"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateClass tells the class of the route by the scopes it requires. The
+// approvers are limited along with the admins, and the unsafe methods
+// requiring only the read scope, e.g. creating the API tokens, are writes.
+func rateClass(method string, scopes []string) string {
+	class := models.RateClassRead
+	if !safeMethods[method] {
+		class = models.RateClassWrite
+	}
+
+	for _, scope := range scopes {
+		switch scope {
+		case models.PermissionAdmin, models.PermissionApprove:
+			return models.RateClassAdmin
+		case models.PermissionWrite:
+			class = models.RateClassWrite
+		}
+	}
+
+	return class
+}
+
+// Helper to format the duration in whole seconds, rounded up
+func formatSeconds(d time.Duration) string {
+	seconds := d / time.Second
+	if d%time.Second != 0 {
+		seconds++
+	}
+
+	return strconv.FormatInt(int64(seconds), 10)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+func TestRateClass(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		scopes []string
+		want   string
+	}{
+		{"read", http.MethodGet, []string{models.PermissionRead}, models.RateClassRead},
+		{"write", http.MethodDelete, []string{models.PermissionWrite}, models.RateClassWrite},
+		{"unsafe method with read scope", http.MethodPost, []string{models.PermissionRead}, models.RateClassWrite},
+		{"admin", http.MethodGet, []string{models.PermissionAdmin}, models.RateClassAdmin},
+		{"approve", http.MethodPost, []string{models.PermissionApprove}, models.RateClassAdmin},
+		{"no scopes", http.MethodGet, nil, models.RateClassRead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rateClass(tt.method, tt.scopes); got != tt.want {
+				t.Errorf("rateClass() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	swagger, err := GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	swagger.Servers = nil
+
+	fa, err := NewFakeAuthenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := fa.CreateJSWWithClaims("writer", []string{models.PermissionWrite}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &Authenticator{Validator: fa}
+	validator, err := NewRequestValidator(swagger, a.Authenticate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limiter := models.NewRateLimiter(models.NewRateLimitsMemory(), map[string]models.RateLimit{
+		models.RateClassRead:  {Requests: 2, Period: time.Minute},
+		models.RateClassWrite: {Requests: 1, Period: time.Hour},
+	})
+
+	handler := PrincipalContext(validator(NewRateLimitMiddleware(limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))))
+
+	tests := []struct {
+		name       string
+		method     string
+		status     int
+		remaining  string
+		reset      string
+		policy     string
+		retryAfter string
+	}{
+		{"write", http.MethodDelete, http.StatusNoContent, "0", "3600", "1;w=3600", ""},
+		{"write over the limit", http.MethodDelete, http.StatusTooManyRequests, "0", "3600", "1;w=3600", "3600"},
+		{"read of its own bucket", http.MethodGet, http.StatusNoContent, "1", "30", "2;w=60", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/sandboxes/065293e2-238c-49ff-8f65-8036bce30174", nil)
+			r.Header.Set("Authorization", "Bearer "+string(writer))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+
+			for header, want := range map[string]string{
+				RateLimitRemainingHeader: tt.remaining,
+				RateLimitResetHeader:     tt.reset,
+				RateLimitPolicyHeader:    tt.policy,
+				RetryAfterHeader:         tt.retryAfter,
+			} {
+				if got := w.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+		})
+	}
+
+	// The requests failing the authentication are not limited
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/sandboxes/065293e2-238c-49ff-8f65-8036bce30174", nil))
+	if w.Code != http.StatusUnauthorized || w.Header().Get(RateLimitLimitHeader) != "" {
+		t.Errorf("anonymous request = %d with %s %q, want %d without it", w.Code, RateLimitLimitHeader, w.Header().Get(RateLimitLimitHeader), http.StatusUnauthorized)
+	}
+}
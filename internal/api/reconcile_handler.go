@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+// Helper to convert the reconciled operation
+func toReconcileResult(result models.ReconcileResult) ReconcileResult {
+	reconcileResult := ReconcileResult{Operation: toOperation(result.Operation)}
+
+	if result.Status != "" {
+		reconcileResult.SandboxStatus = String(result.Status)
+	}
+
+	if result.ReconciledStatus != "" {
+		reconcileResult.ReconciledStatus = String(result.ReconciledStatus)
+	}
+
+	if result.Err != nil {
+		problem := problemFromError(result.Err)
+		reconcileResult.Error = &problem
+	}
+
+	return reconcileResult
+}
+
+func (sh *SandboxHandler) ReconcileSandboxes(ctx context.Context, request ReconcileSandboxesRequestObject) (ReconcileSandboxesResponseObject, error) {
+	dryRun := request.Params.DryRun != nil && *request.Params.DryRun
+
+	results, err := sh.instances.Reconcile(principalFromContext(ctx), dryRun)
+	if err != nil {
+		problem := problemFromError(err)
+		return ReconcileSandboxesdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	response := ReconcileResponse{
+		DryRun:  dryRun,
+		Results: make([]ReconcileResult, 0, len(results)),
+	}
+
+	for _, result := range results {
+		response.Results = append(response.Results, toReconcileResult(result))
+	}
+
+	log.Logger.Info("Lost operations reconciled", "operations", len(results), "dryRun", dryRun)
+
+	return ReconcileSandboxes200JSONResponse(response), nil
+}
+
+func (sh *SandboxHandler) ForceDeleteSandbox(ctx context.Context, request ForceDeleteSandboxRequestObject) (ForceDeleteSandboxResponseObject, error) {
+	operation, err := sh.instances.ForceRemove(request.Id, principalFromContext(ctx))
+	if err != nil {
+		problem := problemFromError(err)
+		return ForceDeleteSandboxdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	log.Logger.Info("Sandbox force delete started", "id", request.Id, "operation", operation.UUID)
+
+	return ForceDeleteSandbox202JSONResponse{
+		Body: toOperation(operation),
+		Headers: ForceDeleteSandbox202ResponseHeaders{
+			OperationLocation: operationLocation(operation.UUID),
+		},
+	}, nil
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+func toRevocation(details models.RevocationDetails) Revocation {
+	revocation := Revocation{
+		Id:        details.UUID,
+		RevokedBy: details.RevokedBy,
+		RevokedAt: details.RevokedAt,
+		ExpiresAt: details.ExpiresAt,
+	}
+
+	if details.TokenID != "" {
+		revocation.Jti = String(details.TokenID)
+	}
+
+	if details.Subject != "" {
+		revocation.Subject = String(details.Subject)
+	}
+
+	if details.Reason != "" {
+		revocation.Reason = String(details.Reason)
+	}
+
+	return revocation
+}
+
+// Helper to get the value of the optional string field
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func (sh *SandboxHandler) ListRevocations(ctx context.Context, request ListRevocationsRequestObject) (ListRevocationsResponseObject, error) {
+	revocations, err := sh.revocations.List()
+	if err != nil {
+		problem := problemFromError(err)
+		return ListRevocationsdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	response := make(ListRevocations200JSONResponse, 0, len(revocations))
+	for _, revocation := range revocations {
+		response = append(response, toRevocation(revocation))
+	}
+
+	return response, nil
+}
+
+func (sh *SandboxHandler) CreateRevocation(ctx context.Context, request CreateRevocationRequestObject) (CreateRevocationResponseObject, error) {
+	body := request.Body
+
+	revocation, err := sh.revocations.Revoke(principalFromContext(ctx), stringValue(body.Jti), stringValue(body.Subject), stringValue(body.Reason), body.ExpiresAt)
+	if err != nil {
+		problem := problemFromError(err)
+		return CreateRevocationdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	return CreateRevocation201JSONResponse(toRevocation(revocation)), nil
+}
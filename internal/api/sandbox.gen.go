@@ -10,6 +10,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
@@ -25,15 +26,109 @@ const (
 	BearerAuthScopes = "BearerAuth.Scopes"
 )
 
+// Defines values for ApiTokenCreateScopes.
+const (
+	SandboxR ApiTokenCreateScopes = "sandbox:r"
+	SandboxW ApiTokenCreateScopes = "sandbox:w"
+)
+
+// Defines values for ApprovalStatus.
+const (
+	ApprovalStatusAPPROVED ApprovalStatus = "APPROVED"
+	ApprovalStatusCANCELED ApprovalStatus = "CANCELED"
+	ApprovalStatusDENIED   ApprovalStatus = "DENIED"
+	ApprovalStatusEXPIRED  ApprovalStatus = "EXPIRED"
+	ApprovalStatusPENDING  ApprovalStatus = "PENDING"
+)
+
+// Defines values for AuditEventOutcome.
+const (
+	AuditEventOutcomeFailure AuditEventOutcome = "failure"
+	AuditEventOutcomeSuccess AuditEventOutcome = "success"
+)
+
+// Defines values for BatchItemAction.
+const (
+	BatchItemActionCreate BatchItemAction = "create"
+	BatchItemActionDelete BatchItemAction = "delete"
+	BatchItemActionExtend BatchItemAction = "extend"
+)
+
+// Defines values for BatchRequestAction.
+const (
+	BatchRequestActionDelete BatchRequestAction = "delete"
+	BatchRequestActionExtend BatchRequestAction = "extend"
+)
+
+// Defines values for BatchResultStatus.
+const (
+	BatchResultStatusACCEPTED BatchResultStatus = "ACCEPTED"
+	BatchResultStatusFAILED   BatchResultStatus = "FAILED"
+	BatchResultStatusPLANNED  BatchResultStatus = "PLANNED"
+)
+
+// Defines values for BatchSelectorStatus.
+const (
+	BatchSelectorStatusDELETING        BatchSelectorStatus = "DELETING"
+	BatchSelectorStatusEXPIRED         BatchSelectorStatus = "EXPIRED"
+	BatchSelectorStatusFAILED          BatchSelectorStatus = "FAILED"
+	BatchSelectorStatusPENDING         BatchSelectorStatus = "PENDING"
+	BatchSelectorStatusPENDINGAPPROVAL BatchSelectorStatus = "PENDING_APPROVAL"
+	BatchSelectorStatusRUNNING         BatchSelectorStatus = "RUNNING"
+	BatchSelectorStatusSCHEDULED       BatchSelectorStatus = "SCHEDULED"
+	BatchSelectorStatusSTARTING        BatchSelectorStatus = "STARTING"
+	BatchSelectorStatusSTOPPED         BatchSelectorStatus = "STOPPED"
+	BatchSelectorStatusSTOPPING        BatchSelectorStatus = "STOPPING"
+)
+
+// Defines values for OperationKind.
+const (
+	CREATE OperationKind = "CREATE"
+	DELETE OperationKind = "DELETE"
+	EXPIRE OperationKind = "EXPIRE"
+	EXTEND OperationKind = "EXTEND"
+	START  OperationKind = "START"
+	STOP   OperationKind = "STOP"
+	UPDATE OperationKind = "UPDATE"
+)
+
+// Defines values for OperationStatus.
+const (
+	OperationStatusCANCELED   OperationStatus = "CANCELED"
+	OperationStatusFAILED     OperationStatus = "FAILED"
+	OperationStatusNOTSTARTED OperationStatus = "NOT_STARTED"
+	OperationStatusRUNNING    OperationStatus = "RUNNING"
+	OperationStatusSUCCEEDED  OperationStatus = "SUCCEEDED"
+)
+
 // Defines values for SandboxStatus.
 const (
-	DELETED SandboxStatus = "DELETED"
-	EXPIRED SandboxStatus = "EXPIRED"
-	FAILED  SandboxStatus = "FAILED"
-	PENDING SandboxStatus = "PENDING"
-	RUNNING SandboxStatus = "RUNNING"
-	STOPPED SandboxStatus = "STOPPED"
-	UNKNOWN SandboxStatus = "UNKNOWN"
+	SandboxStatusDELETED         SandboxStatus = "DELETED"
+	SandboxStatusDELETING        SandboxStatus = "DELETING"
+	SandboxStatusEXPIRED         SandboxStatus = "EXPIRED"
+	SandboxStatusFAILED          SandboxStatus = "FAILED"
+	SandboxStatusPENDING         SandboxStatus = "PENDING"
+	SandboxStatusPENDINGAPPROVAL SandboxStatus = "PENDING_APPROVAL"
+	SandboxStatusRUNNING         SandboxStatus = "RUNNING"
+	SandboxStatusSCHEDULED       SandboxStatus = "SCHEDULED"
+	SandboxStatusSTARTING        SandboxStatus = "STARTING"
+	SandboxStatusSTOPPED         SandboxStatus = "STOPPED"
+	SandboxStatusSTOPPING        SandboxStatus = "STOPPING"
+	SandboxStatusUNKNOWN         SandboxStatus = "UNKNOWN"
+)
+
+// Defines values for SandboxRole.
+const (
+	Contributor             SandboxRole = "Contributor"
+	Owner                   SandboxRole = "Owner"
+	Reader                  SandboxRole = "Reader"
+	UserAccessAdministrator SandboxRole = "User Access Administrator"
+)
+
+// Defines values for ScheduleSkipAction.
+const (
+	Start ScheduleSkipAction = "start"
+	Stop  ScheduleSkipAction = "stop"
 )
 
 // Defines values for StatusStatus.
@@ -42,702 +137,4685 @@ const (
 	OK    StatusStatus = "OK"
 )
 
-// Error defines model for Error.
-type Error struct {
-	// Code Error code
-	Code int32 `json:"code"`
+// Defines values for AuditOutcome.
+const (
+	AuditOutcomeFailure AuditOutcome = "failure"
+	AuditOutcomeSuccess AuditOutcome = "success"
+)
 
-	// Message Error message
-	Message string `json:"message"`
-}
+// Defines values for ListAuditEventsParamsOutcome.
+const (
+	ListAuditEventsParamsOutcomeFailure ListAuditEventsParamsOutcome = "failure"
+	ListAuditEventsParamsOutcomeSuccess ListAuditEventsParamsOutcome = "success"
+)
 
-// Sandbox defines model for Sandbox.
-type Sandbox struct {
-	CreatedAt time.Time     `json:"createdAt"`
-	ExpiresAt time.Time     `json:"expiresAt"`
-	Id        string        `json:"id"`
-	Name      string        `json:"name"`
-	Status    SandboxStatus `json:"status"`
-	UpdatedAt time.Time     `json:"updatedAt"`
-}
+// Defines values for ExportAuditEventsParamsOutcome.
+const (
+	ExportAuditEventsParamsOutcomeFailure ExportAuditEventsParamsOutcome = "failure"
+	ExportAuditEventsParamsOutcomeSuccess ExportAuditEventsParamsOutcome = "success"
+)
 
-// SandboxStatus defines model for Sandbox.Status.
-type SandboxStatus string
+// Defines values for ListSandboxesParamsStatus.
+const (
+	ListSandboxesParamsStatusDELETED         ListSandboxesParamsStatus = "DELETED"
+	ListSandboxesParamsStatusDELETING        ListSandboxesParamsStatus = "DELETING"
+	ListSandboxesParamsStatusEXPIRED         ListSandboxesParamsStatus = "EXPIRED"
+	ListSandboxesParamsStatusFAILED          ListSandboxesParamsStatus = "FAILED"
+	ListSandboxesParamsStatusPENDING         ListSandboxesParamsStatus = "PENDING"
+	ListSandboxesParamsStatusPENDINGAPPROVAL ListSandboxesParamsStatus = "PENDING_APPROVAL"
+	ListSandboxesParamsStatusRUNNING         ListSandboxesParamsStatus = "RUNNING"
+	ListSandboxesParamsStatusSCHEDULED       ListSandboxesParamsStatus = "SCHEDULED"
+	ListSandboxesParamsStatusSTARTING        ListSandboxesParamsStatus = "STARTING"
+	ListSandboxesParamsStatusSTOPPED         ListSandboxesParamsStatus = "STOPPED"
+	ListSandboxesParamsStatusSTOPPING        ListSandboxesParamsStatus = "STOPPING"
+)
 
-// SandboxCreate defines model for SandboxCreate.
-type SandboxCreate struct {
+// Defines values for ListSandboxesParamsSort.
+const (
+	CreatedAt ListSandboxesParamsSort = "createdAt"
+	ExpiresAt ListSandboxesParamsSort = "expiresAt"
+	Name      ListSandboxesParamsSort = "name"
+	UpdatedAt ListSandboxesParamsSort = "updatedAt"
+)
+
+// Defines values for ListSandboxesParamsOrder.
+const (
+	Asc  ListSandboxesParamsOrder = "asc"
+	Desc ListSandboxesParamsOrder = "desc"
+)
+
+// ApiToken Personal access token, it acts on the sandboxes of its owner only
+type ApiToken struct {
+	CreatedAt time.Time `json:"createdAt"`
 	ExpiresAt time.Time `json:"expiresAt"`
-	Name      string    `json:"name"`
+	Id        string    `json:"id"`
+
+	// LastUsedAt Time of the last use of the token, recorded at most once a minute
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	Scopes     []string   `json:"scopes"`
 }
 
-// SandboxUpdate defines model for SandboxUpdate.
-type SandboxUpdate struct {
+// ApiTokenCreate defines model for ApiTokenCreate.
+type ApiTokenCreate struct {
+	// ExpiresAt Expiration of the token, at most a year ahead
 	ExpiresAt time.Time `json:"expiresAt"`
-}
+	Name      string    `json:"name"`
 
-// Status defines model for Status.
-type Status struct {
-	Message *string       `json:"message,omitempty"`
-	Status  *StatusStatus `json:"status,omitempty"`
+	// Scopes Scopes of the token, the caller has to have them too
+	Scopes []ApiTokenCreateScopes `json:"scopes"`
 }
 
-// StatusStatus defines model for Status.Status.
-type StatusStatus string
+// ApiTokenCreateScopes defines model for ApiTokenCreate.Scopes.
+type ApiTokenCreateScopes string
 
-// ListSandboxesParams defines parameters for ListSandboxes.
-type ListSandboxesParams struct {
-	// Limit The number of items to return
-	Limit int `form:"limit" json:"limit"`
+// ApiTokenCreated defines model for ApiTokenCreated.
+type ApiTokenCreated struct {
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Id        string    `json:"id"`
 
-	// Offset The number of items to skip before starting to collect the result set
-	Offset int `form:"offset" json:"offset"`
-}
+	// LastUsedAt Time of the last use of the token, recorded at most once a minute
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	Scopes     []string   `json:"scopes"`
 
-// CreateSandboxJSONRequestBody defines body for CreateSandbox for application/json ContentType.
-type CreateSandboxJSONRequestBody = SandboxCreate
+	// Token The token itself, it is shown only once
+	Token string `json:"token"`
+}
 
-// UpdateSandboxJSONRequestBody defines body for UpdateSandbox for application/json ContentType.
-type UpdateSandboxJSONRequestBody = SandboxUpdate
+// Approval Request to create the sandbox exceeding the policy
+type Approval struct {
+	Comment   *string    `json:"comment,omitempty"`
+	DecidedAt *time.Time `json:"decidedAt,omitempty"`
+	DecidedBy *string    `json:"decidedBy,omitempty"`
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Health check
-	// (GET /health)
-	Health(w http.ResponseWriter, r *http.Request)
-	// List sandboxes
-	// (GET /sandboxes)
-	ListSandboxes(w http.ResponseWriter, r *http.Request, params ListSandboxesParams)
-	// Create a sandbox
-	// (POST /sandboxes)
-	CreateSandbox(w http.ResponseWriter, r *http.Request)
-	// Get a sandbox by name
-	// (GET /sandboxes/name/{name})
-	GetSandboxByName(w http.ResponseWriter, r *http.Request, name string)
-	// Delete a sandbox
-	// (DELETE /sandboxes/{id})
-	DeleteSandbox(w http.ResponseWriter, r *http.Request, id string)
-	// Get a sandbox
-	// (GET /sandboxes/{id})
-	GetSandbox(w http.ResponseWriter, r *http.Request, id string)
-	// Update a sandbox
-	// (PATCH /sandboxes/{id})
-	UpdateSandbox(w http.ResponseWriter, r *http.Request, id string)
+	// ExpiresAt Time the pending request expires at, the sandbox is deleted then
+	ExpiresAt time.Time `json:"expiresAt"`
+	Id        string    `json:"id"`
+
+	// Reasons Rules of the policy the sandbox exceeds
+	Reasons     []string       `json:"reasons"`
+	RequestedAt time.Time      `json:"requestedAt"`
+	RequestedBy string         `json:"requestedBy"`
+	SandboxId   string         `json:"sandboxId"`
+	SandboxName string         `json:"sandboxName"`
+	Status      ApprovalStatus `json:"status"`
 }
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+// ApprovalDecision defines model for ApprovalDecision.
+type ApprovalDecision struct {
+	Comment *string `json:"comment,omitempty"`
 }
 
-type MiddlewareFunc func(http.Handler) http.Handler
-
-// Health operation middleware
-func (siw *ServerInterfaceWrapper) Health(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// ApprovalStatus defines model for ApprovalStatus.
+type ApprovalStatus string
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+// AuditEvent Entry of the audit log. The changes hold only the changed fields, before and after the action. The hash covers the fields of the event and the hash of the previous event.
+type AuditEvent struct {
+	// Action What was done, e.g. sandbox.create or sandbox.status, auth.rejected for the requests rejected by the authentication
+	Action string `json:"action"`
 
-	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.Health(w, r)
-	})
+	// Actor Subject of the caller, or system for the changes made by the service, empty for the requests without a valid token
+	Actor  string                  `json:"actor"`
+	After  *map[string]interface{} `json:"after,omitempty"`
+	Before *map[string]interface{} `json:"before,omitempty"`
 
-	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
-		handler = siw.HandlerMiddlewares[i](handler)
-	}
+	// Hash Hex encoded SHA-256 hash of the event
+	Hash string `json:"hash"`
+	Id   int64  `json:"id"`
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
-}
+	// OccurredAt UTC time of the event
+	OccurredAt time.Time         `json:"occurredAt"`
+	Outcome    AuditEventOutcome `json:"outcome"`
 
-// ListSandboxes operation middleware
-func (siw *ServerInterfaceWrapper) ListSandboxes(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	// PrevHash Hex encoded SHA-256 hash of the previous event
+	PrevHash string `json:"prevHash"`
 
-	var err error
+	// RequestId X-Request-ID of the request, if the event was caused by one
+	RequestId string `json:"requestId"`
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	// StatusCode HTTP status of the response, if the event was caused by a request
+	StatusCode *int   `json:"statusCode,omitempty"`
+	TargetId   string `json:"targetId"`
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListSandboxesParams
+	// TargetType Kind of the target, e.g. sandbox or apiToken
+	TargetType string `json:"targetType"`
+}
 
-	// ------------- Required query parameter "limit" -------------
+// AuditEventOutcome defines model for AuditEvent.Outcome.
+type AuditEventOutcome string
 
-	if paramValue := r.URL.Query().Get("limit"); paramValue != "" {
+// AuditVerification defines model for AuditVerification.
+type AuditVerification struct {
+	// Events Number of the events checked
+	Events int64 `json:"events"`
 
-	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "limit"})
-		return
-	}
+	// FirstInvalidId First event not matching the chain, missing if the chain is intact
+	FirstInvalidId *int64 `json:"firstInvalidId,omitempty"`
+	Valid          bool   `json:"valid"`
+}
 
-	err = runtime.BindQueryParameter("form", true, true, "limit", r.URL.Query(), &params.Limit)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
-		return
-	}
+// BatchItem defines model for BatchItem.
+type BatchItem struct {
+	Action BatchItemAction `json:"action"`
 
-	// ------------- Required query parameter "offset" -------------
+	// ExpiresAt Expiration for the create and extend actions
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 
-	if paramValue := r.URL.Query().Get("offset"); paramValue != "" {
+	// Id Sandbox ID for the extend and delete actions
+	Id *string `json:"id,omitempty"`
 
-	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "offset"})
-		return
-	}
+	// Labels Free-form key/value labels, synced to the tags of the resource group
+	Labels *Labels `json:"labels,omitempty"`
 
-	err = runtime.BindQueryParameter("form", true, true, "offset", r.URL.Query(), &params.Offset)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
-		return
-	}
+	// Name Name of the sandbox, it is used as the Azure resource group name and in the application identifier URI. Letters, digits, underscores, hyphens and periods, up to 90 characters, must not end with a period.
+	Name *SandboxName `json:"name,omitempty"`
 
-	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListSandboxes(w, r, params)
-	})
+	// Role Azure built-in role assigned to the application of the sandbox on its resource group, Contributor by default. The privileged roles wait for the approval.
+	Role *SandboxRole `json:"role,omitempty"`
 
-	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
-		handler = siw.HandlerMiddlewares[i](handler)
-	}
+	// StartAt Schedule of the create action
+	StartAt *time.Time `json:"startAt,omitempty"`
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	// Template Template the resources of the sandbox are deployed from. The large templates wait for the approval.
+	Template *SandboxTemplate `json:"template,omitempty"`
 }
 
-// CreateSandbox operation middleware
-func (siw *ServerInterfaceWrapper) CreateSandbox(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// BatchItemAction defines model for BatchItem.Action.
+type BatchItemAction string
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+// BatchRequest Either the list of items or the selector with the action. The selected sandboxes are resolved when the request is received.
+type BatchRequest struct {
+	// Action Action applied to every selected sandbox
+	Action *BatchRequestAction `json:"action,omitempty"`
 
-	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateSandbox(w, r)
-	})
+	// DryRun Check the items and report what would be done, without doing it
+	DryRun *bool `json:"dryRun,omitempty"`
 
-	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
-		handler = siw.HandlerMiddlewares[i](handler)
-	}
+	// ExpiresAt New expiration of the selected sandboxes for the extend action
+	ExpiresAt *time.Time   `json:"expiresAt,omitempty"`
+	Items     *[]BatchItem `json:"items,omitempty"`
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	// Selector Selects the sandboxes the same way as the listing filters, at least one filter must be set
+	Selector *BatchSelector `json:"selector,omitempty"`
 }
 
-// GetSandboxByName operation middleware
-func (siw *ServerInterfaceWrapper) GetSandboxByName(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// BatchRequestAction Action applied to every selected sandbox
+type BatchRequestAction string
 
-	var err error
+// BatchResponse defines model for BatchResponse.
+type BatchResponse struct {
+	DryRun  bool          `json:"dryRun"`
+	Results []BatchResult `json:"results"`
+}
 
-	// ------------- Path parameter "name" -------------
-	var name string
+// BatchResult defines model for BatchResult.
+type BatchResult struct {
+	Action string `json:"action"`
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, chi.URLParam(r, "name"), &name)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "name", Err: err})
-		return
-	}
+	// Error Error details as defined by RFC 7807
+	Error *Problem `json:"error,omitempty"`
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+	// Index Position of the item in the request or in the selected sandboxes
+	Index     int        `json:"index"`
+	Operation *Operation `json:"operation,omitempty"`
+	SandboxId *string    `json:"sandboxId,omitempty"`
 
-	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetSandboxByName(w, r, name)
-	})
+	// Status PLANNED for the dry run, ACCEPTED once the operation is started
+	Status BatchResultStatus `json:"status"`
+}
 
-	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
-		handler = siw.HandlerMiddlewares[i](handler)
-	}
+// BatchResultStatus PLANNED for the dry run, ACCEPTED once the operation is started
+type BatchResultStatus string
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+// BatchSelector Selects the sandboxes the same way as the listing filters, at least one filter must be set
+type BatchSelector struct {
+	LabelSelector *string                `json:"labelSelector,omitempty"`
+	NamePrefix    *string                `json:"namePrefix,omitempty"`
+	Owner         *string                `json:"owner,omitempty"`
+	Status        *[]BatchSelectorStatus `json:"status,omitempty"`
 }
 
-// DeleteSandbox operation middleware
-func (siw *ServerInterfaceWrapper) DeleteSandbox(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var err error
+// BatchSelectorStatus defines model for BatchSelector.Status.
+type BatchSelectorStatus string
 
-	// ------------- Path parameter "id" -------------
-	var id string
+// CatalogSchedule Schedule shared by the sandboxes of the catalog, every sandbox without
+// its own schedule gets a copy of it with the next runs from the time it
+// joins the catalog.
+type CatalogSchedule struct {
+	Catalog string `json:"catalog"`
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
-		return
-	}
+	// StartCron Cron expression of the starts, empty if the sandboxes are only stopped
+	StartCron string `json:"startCron"`
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+	// StopCron Standard cron expression of the stops, e.g. 0 19 * * 1-5
+	StopCron string `json:"stopCron"`
 
-	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteSandbox(w, r, id)
-	})
+	// Timezone IANA timezone the expressions are evaluated in
+	Timezone string `json:"timezone"`
+}
 
-	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
-		handler = siw.HandlerMiddlewares[i](handler)
-	}
+// FieldError defines model for FieldError.
+type FieldError struct {
+	// Field Name of the parameter or dot separated path of the body field
+	Field string `json:"field"`
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	// Message What is wrong with the field
+	Message string `json:"message"`
 }
 
-// GetSandbox operation middleware
-func (siw *ServerInterfaceWrapper) GetSandbox(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// Labels Free-form key/value labels, synced to the tags of the resource group
+type Labels map[string]string
 
-	var err error
+// Operation defines model for Operation.
+type Operation struct {
+	CreatedAt time.Time       `json:"createdAt"`
+	Error     *OperationError `json:"error,omitempty"`
+	Id        string          `json:"id"`
+	Kind      OperationKind   `json:"kind"`
 
-	// ------------- Path parameter "id" -------------
-	var id string
+	// PercentComplete Progress of the operation, from 0 to 100
+	PercentComplete int32           `json:"percentComplete"`
+	SandboxId       string          `json:"sandboxId"`
+	Status          OperationStatus `json:"status"`
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
-		return
-	}
+	// Step Step the operation is currently executing
+	Step      string    `json:"step"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+// OperationKind defines model for Operation.Kind.
+type OperationKind string
 
-	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetSandbox(w, r, id)
-	})
+// OperationStatus defines model for Operation.Status.
+type OperationStatus string
 
-	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
-		handler = siw.HandlerMiddlewares[i](handler)
-	}
+// OperationError defines model for OperationError.
+type OperationError struct {
+	// Code Error code
+	Code string `json:"code"`
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	// Message Error message
+	Message string `json:"message"`
 }
 
-// UpdateSandbox operation middleware
-func (siw *ServerInterfaceWrapper) UpdateSandbox(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var err error
+// Problem Error details as defined by RFC 7807
+type Problem struct {
+	// Code Stable machine readable error code
+	Code string `json:"code"`
 
-	// ------------- Path parameter "id" -------------
-	var id string
+	// Detail Explanation specific to this occurrence of the problem
+	Detail *string `json:"detail,omitempty"`
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
-		return
-	}
+	// Errors Invalid fields of the request
+	Errors *[]FieldError `json:"errors,omitempty"`
 
-	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+	// Instance URI reference of the request that caused the problem
+	Instance *string `json:"instance,omitempty"`
 
-	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UpdateSandbox(w, r, id)
-	})
+	// Status HTTP status code
+	Status int32 `json:"status"`
 
-	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
-		handler = siw.HandlerMiddlewares[i](handler)
-	}
+	// Title Short summary of the problem type
+	Title string `json:"title"`
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	// Type URI reference identifying the problem type
+	Type string `json:"type"`
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
+// ReconcileResponse defines model for ReconcileResponse.
+type ReconcileResponse struct {
+	DryRun  bool              `json:"dryRun"`
+	Results []ReconcileResult `json:"results"`
 }
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+// ReconcileResult defines model for ReconcileResult.
+type ReconcileResult struct {
+	// Error Error details as defined by RFC 7807
+	Error     *Problem  `json:"error,omitempty"`
+	Operation Operation `json:"operation"`
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	// ReconciledStatus Status the sandbox is moved to, missing if it is left as it is
+	ReconciledStatus *string `json:"reconciledStatus,omitempty"`
 
-type UnmarshallingParamError struct {
-	ParamName string
-	Err       error
+	// SandboxStatus Status of the sandbox before the reconciliation, missing if the sandbox is gone
+	SandboxStatus *string `json:"sandboxStatus,omitempty"`
 }
 
-func (e *UnmarshallingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshalling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+// Revocation Revoked token, either the single token by its jti, or the id of the API token, or every token of the subject issued before revokedAt
+type Revocation struct {
+	// ExpiresAt Expiry of the revoked token, the revocation is dropped afterwards
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Id        string     `json:"id"`
+	Jti       *string    `json:"jti,omitempty"`
+	Reason    *string    `json:"reason,omitempty"`
+	RevokedAt time.Time  `json:"revokedAt"`
+	RevokedBy string     `json:"revokedBy"`
+	Subject   *string    `json:"subject,omitempty"`
 }
 
-func (e *UnmarshallingParamError) Unwrap() error {
-	return e.Err
+// RevocationCreate Either jti or subject has to be set
+type RevocationCreate struct {
+	// ExpiresAt Expiry of the token revoked by its jti, the exp claim of the JWT. The token is denied for a year if not set.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Jti The jti of the token to revoke, or the id of the API token
+	Jti    *string `json:"jti,omitempty"`
+	Reason *string `json:"reason,omitempty"`
+
+	// Subject The subject to revoke every token issued until now of
+	Subject *string `json:"subject,omitempty"`
 }
 
-type RequiredParamError struct {
-	ParamName string
-}
+// Sandbox defines model for Sandbox.
+type Sandbox struct {
+	CostCenter  *string   `json:"costCenter,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Description *string   `json:"description,omitempty"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	Id          string    `json:"id"`
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
-}
+	// IdleWarnedAt Time the owner was warned the sandbox is idle. Unless the sandbox is used or kept, it expires once the warning period is over.
+	IdleWarnedAt *time.Time `json:"idleWarnedAt,omitempty"`
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
-}
+	// KeepWhenIdle The owner opted out of the early expiry of the idle sandbox
+	KeepWhenIdle *bool `json:"keepWhenIdle,omitempty"`
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
-}
+	// Labels Free-form key/value labels, synced to the tags of the resource group
+	Labels *Labels `json:"labels,omitempty"`
+	Name   string  `json:"name"`
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
-}
+	// Notes Notes of the owner
+	Notes *string `json:"notes,omitempty"`
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
+	// Owner Subject of the token the sandbox was created with
+	Owner *string `json:"owner,omitempty"`
+
+	// Role Azure built-in role assigned to the application of the sandbox on its resource group, Contributor by default. The privileged roles wait for the approval.
+	Role *SandboxRole `json:"role,omitempty"`
+
+	// StartAt Time the scheduled sandbox is provisioned at
+	StartAt *time.Time    `json:"startAt,omitempty"`
+	Status  SandboxStatus `json:"status"`
+
+	// Template Template the resources of the sandbox are deployed from. The large templates wait for the approval.
+	Template  *SandboxTemplate `json:"template,omitempty"`
+	UpdatedAt time.Time        `json:"updatedAt"`
 }
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+// SandboxStatus defines model for Sandbox.Status.
+type SandboxStatus string
+
+// SandboxCreate defines model for SandboxCreate.
+type SandboxCreate struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// Labels Free-form key/value labels, synced to the tags of the resource group
+	Labels *Labels `json:"labels,omitempty"`
+
+	// Name Name of the sandbox, it is used as the Azure resource group name and in the application identifier URI. Letters, digits, underscores, hyphens and periods, up to 90 characters, must not end with a period.
+	Name SandboxName `json:"name"`
+
+	// Role Azure built-in role assigned to the application of the sandbox on its resource group, Contributor by default. The privileged roles wait for the approval.
+	Role *SandboxRole `json:"role,omitempty"`
+
+	// StartAt Provision the sandbox at this time instead of right away, it must be before expiresAt. The sandbox is SCHEDULED until then.
+	StartAt *time.Time `json:"startAt,omitempty"`
+
+	// Template Template the resources of the sandbox are deployed from. The large templates wait for the approval.
+	Template *SandboxTemplate `json:"template,omitempty"`
 }
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
+// SandboxName Name of the sandbox, it is used as the Azure resource group name and in the application identifier URI. Letters, digits, underscores, hyphens and periods, up to 90 characters, must not end with a period.
+type SandboxName = string
+
+// SandboxPatch JSON merge patch (RFC 7396) of the sandbox. Missing fields are left as they are, null clears the field. Labels are merged, the labels set to null are removed. Unknown fields are rejected.
+type SandboxPatch = SandboxMergePatch
+
+// SandboxRole Azure built-in role assigned to the application of the sandbox on its resource group, Contributor by default. The privileged roles wait for the approval.
+type SandboxRole string
+
+// SandboxTemplate Template the resources of the sandbox are deployed from. The large templates wait for the approval.
+type SandboxTemplate = string
+
+// Schedule Stops the sandbox and starts it again on the cron expressions. Missed
+// runs are not caught up, if both the stop and the start are due only the
+// latest one is run. The sandboxes without their own schedule inherit the
+// schedule of their catalog, the value of the catalog label, the ones
+// without the label are in the default catalog.
+type Schedule struct {
+	// Catalog Catalog the schedule is inherited from, missing if the schedule is set for the sandbox
+	Catalog     *string    `json:"catalog,omitempty"`
+	NextStartAt *time.Time `json:"nextStartAt,omitempty"`
+	NextStopAt  time.Time  `json:"nextStopAt"`
+	SandboxId   string     `json:"sandboxId"`
+
+	// StartCron Cron expression of the starts, empty if the sandbox is only stopped
+	StartCron string `json:"startCron"`
+
+	// StopCron Standard cron expression of the stops, e.g. 0 19 * * 1-5
+	StopCron string `json:"stopCron"`
+
+	// Timezone IANA timezone the expressions are evaluated in
+	Timezone string `json:"timezone"`
 }
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
+// ScheduleSkip defines model for ScheduleSkip.
+type ScheduleSkip struct {
+	// Action Action of the schedule to skip the next run of
+	Action ScheduleSkipAction `json:"action"`
 }
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+// ScheduleSkipAction Action of the schedule to skip the next run of
+type ScheduleSkipAction string
+
+// ScheduleUpdate defines model for ScheduleUpdate.
+type ScheduleUpdate struct {
+	StartCron *string `json:"startCron,omitempty"`
+	StopCron  string  `json:"stopCron"`
+	Timezone  *string `json:"timezone,omitempty"`
 }
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{})
+// Status defines model for Status.
+type Status struct {
+	Message *string       `json:"message,omitempty"`
+	Status  *StatusStatus `json:"status,omitempty"`
 }
 
-type ChiServerOptions struct {
-	BaseURL          string
-	BaseRouter       chi.Router
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+// StatusStatus defines model for Status.Status.
+type StatusStatus string
+
+// AuditAction defines model for AuditAction.
+type AuditAction = string
+
+// AuditActor defines model for AuditActor.
+type AuditActor = string
+
+// AuditAfter defines model for AuditAfter.
+type AuditAfter = int64
+
+// AuditFrom defines model for AuditFrom.
+type AuditFrom = time.Time
+
+// AuditOutcome defines model for AuditOutcome.
+type AuditOutcome string
+
+// AuditRequestId defines model for AuditRequestId.
+type AuditRequestId = string
+
+// AuditTargetId defines model for AuditTargetId.
+type AuditTargetId = string
+
+// AuditTargetType defines model for AuditTargetType.
+type AuditTargetType = string
+
+// AuditTo defines model for AuditTo.
+type AuditTo = time.Time
+
+// Cursor defines model for Cursor.
+type Cursor = string
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// IfNoneMatch defines model for IfNoneMatch.
+type IfNoneMatch = string
+
+// IncludeTotal defines model for IncludeTotal.
+type IncludeTotal = bool
+
+// Limit defines model for Limit.
+type Limit = int
+
+// ListApprovalsParams defines parameters for ListApprovals.
+type ListApprovalsParams struct {
+	// Limit The number of items to return
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset The number of items to skip before starting to collect the result set
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Status Return only the requests in the status, all of them if not set
+	Status *ApprovalStatus `form:"status,omitempty" json:"status,omitempty"`
 }
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseRouter: r,
-	})
+// ApproveApprovalParams defines parameters for ApproveApproval.
+type ApproveApprovalParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: r,
-	})
+// DenyApprovalParams defines parameters for DenyApproval.
+type DenyApprovalParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
-	r := options.BaseRouter
+// ListAuditEventsParams defines parameters for ListAuditEvents.
+type ListAuditEventsParams struct {
+	Actor      *AuditActor                   `form:"actor,omitempty" json:"actor,omitempty"`
+	Action     *AuditAction                  `form:"action,omitempty" json:"action,omitempty"`
+	TargetType *AuditTargetType              `form:"targetType,omitempty" json:"targetType,omitempty"`
+	TargetId   *AuditTargetId                `form:"targetId,omitempty" json:"targetId,omitempty"`
+	RequestId  *AuditRequestId               `form:"requestId,omitempty" json:"requestId,omitempty"`
+	Outcome    *ListAuditEventsParamsOutcome `form:"outcome,omitempty" json:"outcome,omitempty"`
 
-	if r == nil {
-		r = chi.NewRouter()
-	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
-	}
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
-	}
+	// From Events which occurred at the time or later
+	From *AuditFrom `form:"from,omitempty" json:"from,omitempty"`
 
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/health", wrapper.Health)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/sandboxes", wrapper.ListSandboxes)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/sandboxes", wrapper.CreateSandbox)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/sandboxes/name/{name}", wrapper.GetSandboxByName)
-	})
-	r.Group(func(r chi.Router) {
-		r.Delete(options.BaseURL+"/sandboxes/{id}", wrapper.DeleteSandbox)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/sandboxes/{id}", wrapper.GetSandbox)
-	})
-	r.Group(func(r chi.Router) {
-		r.Patch(options.BaseURL+"/sandboxes/{id}", wrapper.UpdateSandbox)
-	})
+	// To Events which occurred before the time
+	To *AuditTo `form:"to,omitempty" json:"to,omitempty"`
 
-	return r
-}
+	// After Id of the event to continue the listing after
+	After *AuditAfter `form:"after,omitempty" json:"after,omitempty"`
 
-type HealthRequestObject struct {
+	// Limit The number of items to return
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
-type HealthResponseObject interface {
-	VisitHealthResponse(w http.ResponseWriter) error
-}
+// ListAuditEventsParamsOutcome defines parameters for ListAuditEvents.
+type ListAuditEventsParamsOutcome string
 
-type Health200JSONResponse Status
+// ExportAuditEventsParams defines parameters for ExportAuditEvents.
+type ExportAuditEventsParams struct {
+	Actor      *AuditActor                     `form:"actor,omitempty" json:"actor,omitempty"`
+	Action     *AuditAction                    `form:"action,omitempty" json:"action,omitempty"`
+	TargetType *AuditTargetType                `form:"targetType,omitempty" json:"targetType,omitempty"`
+	TargetId   *AuditTargetId                  `form:"targetId,omitempty" json:"targetId,omitempty"`
+	RequestId  *AuditRequestId                 `form:"requestId,omitempty" json:"requestId,omitempty"`
+	Outcome    *ExportAuditEventsParamsOutcome `form:"outcome,omitempty" json:"outcome,omitempty"`
 
-func (response Health200JSONResponse) VisitHealthResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	// From Events which occurred at the time or later
+	From *AuditFrom `form:"from,omitempty" json:"from,omitempty"`
 
-	return json.NewEncoder(w).Encode(response)
+	// To Events which occurred before the time
+	To *AuditTo `form:"to,omitempty" json:"to,omitempty"`
+
+	// After Id of the event to continue the listing after
+	After *AuditAfter `form:"after,omitempty" json:"after,omitempty"`
 }
 
-type ListSandboxesRequestObject struct {
-	Params ListSandboxesParams
+// ExportAuditEventsParamsOutcome defines parameters for ExportAuditEvents.
+type ExportAuditEventsParamsOutcome string
+
+// CancelOperationParams defines parameters for CancelOperation.
+type CancelOperationParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
-type ListSandboxesResponseObject interface {
-	VisitListSandboxesResponse(w http.ResponseWriter) error
+// CreateRevocationParams defines parameters for CreateRevocation.
+type CreateRevocationParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
-type ListSandboxes200JSONResponse []Sandbox
+// ListSandboxesParams defines parameters for ListSandboxes.
+type ListSandboxesParams struct {
+	// Limit The number of items to return
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
 
-func (response ListSandboxes200JSONResponse) VisitListSandboxesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	// Offset The number of items to skip before starting to collect the result set
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
 
-	return json.NewEncoder(w).Encode(response)
-}
+	// Status Return only the sandboxes in any of the statuses
+	Status *[]ListSandboxesParamsStatus `form:"status,omitempty" json:"status,omitempty"`
 
-type ListSandboxesdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
+	// Owner Return only the sandboxes of the owner
+	Owner *string `form:"owner,omitempty" json:"owner,omitempty"`
+
+	// NamePrefix Return only the sandboxes with the name starting with the prefix
+	NamePrefix *string `form:"namePrefix,omitempty" json:"namePrefix,omitempty"`
+
+	// LabelSelector Return only the sandboxes matching the Kubernetes style label selector, e.g. `team=payments,env!=prod,tier in (web,api),!legacy`
+	LabelSelector *string    `form:"labelSelector,omitempty" json:"labelSelector,omitempty"`
+	CreatedAfter  *time.Time `form:"createdAfter,omitempty" json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `form:"createdBefore,omitempty" json:"createdBefore,omitempty"`
+	ExpiresAfter  *time.Time `form:"expiresAfter,omitempty" json:"expiresAfter,omitempty"`
+	ExpiresBefore *time.Time `form:"expiresBefore,omitempty" json:"expiresBefore,omitempty"`
+
+	// ExpiringWithin Return only the sandboxes expiring within the duration from now, e.g. 168h
+	ExpiringWithin *string `form:"expiringWithin,omitempty" json:"expiringWithin,omitempty"`
+
+	// Sort Field to sort the sandboxes by
+	Sort *ListSandboxesParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order Sort order
+	Order *ListSandboxesParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// Cursor Opaque token to continue the listing from, taken from the Link header of the previous page
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// IncludeTotal Return the number of all the matching items in the X-Total-Count header
+	IncludeTotal *IncludeTotal `form:"includeTotal,omitempty" json:"includeTotal,omitempty"`
 }
 
-func (response ListSandboxesdefaultJSONResponse) VisitListSandboxesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+// ListSandboxesParamsStatus defines parameters for ListSandboxes.
+type ListSandboxesParamsStatus string
 
-	return json.NewEncoder(w).Encode(response.Body)
+// ListSandboxesParamsSort defines parameters for ListSandboxes.
+type ListSandboxesParamsSort string
+
+// ListSandboxesParamsOrder defines parameters for ListSandboxes.
+type ListSandboxesParamsOrder string
+
+// CreateSandboxParams defines parameters for CreateSandbox.
+type CreateSandboxParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
-type CreateSandboxRequestObject struct {
-	Body *CreateSandboxJSONRequestBody
+// GetSandboxByNameParams defines parameters for GetSandboxByName.
+type GetSandboxByNameParams struct {
+	// Limit The number of items to return
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque token to continue the listing from, taken from the Link header of the previous page
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// IncludeTotal Return the number of all the matching items in the X-Total-Count header
+	IncludeTotal *IncludeTotal `form:"includeTotal,omitempty" json:"includeTotal,omitempty"`
 }
 
-type CreateSandboxResponseObject interface {
-	VisitCreateSandboxResponse(w http.ResponseWriter) error
+// DeleteSandboxParams defines parameters for DeleteSandbox.
+type DeleteSandboxParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+
+	// IfMatch ETag of the sandbox, the request fails if the sandbox was modified since
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
 }
 
-type CreateSandbox201ResponseHeaders struct {
-	Location string
+// GetSandboxParams defines parameters for GetSandbox.
+type GetSandboxParams struct {
+	// IfNoneMatch ETag of the sandbox, nothing is returned if the sandbox wasn't modified since
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
 }
 
-type CreateSandbox201JSONResponse struct {
-	Body    Sandbox
-	Headers CreateSandbox201ResponseHeaders
+// UpdateSandboxParams defines parameters for UpdateSandbox.
+type UpdateSandboxParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+
+	// IfMatch ETag of the sandbox, the request fails if the sandbox was modified since
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
 }
 
-func (response CreateSandbox201JSONResponse) VisitCreateSandboxResponse(w http.ResponseWriter) error {
-	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(201)
+// ForceDeleteSandboxParams defines parameters for ForceDeleteSandbox.
+type ForceDeleteSandboxParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
 
-	return json.NewEncoder(w).Encode(response.Body)
+// StartSandboxParams defines parameters for StartSandbox.
+type StartSandboxParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
-type CreateSandboxdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
+// StopSandboxParams defines parameters for StopSandbox.
+type StopSandboxParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
-func (response CreateSandboxdefaultJSONResponse) VisitCreateSandboxResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+// BatchSandboxesParams defines parameters for BatchSandboxes.
+type BatchSandboxesParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
 
-	return json.NewEncoder(w).Encode(response.Body)
+// ReconcileSandboxesParams defines parameters for ReconcileSandboxes.
+type ReconcileSandboxesParams struct {
+	// DryRun Report the lost operations without changing anything
+	DryRun *bool `form:"dryRun,omitempty" json:"dryRun,omitempty"`
 }
 
-type GetSandboxByNameRequestObject struct {
-	Name string `json:"name"`
+// ResolveSandboxParams defines parameters for ResolveSandbox.
+type ResolveSandboxParams struct {
+	// Name Sandbox name, the pattern is not enforced, so the sandboxes created before it was introduced can still be resolved
+	Name string `form:"name" json:"name"`
 }
 
-type GetSandboxByNameResponseObject interface {
-	VisitGetSandboxByNameResponse(w http.ResponseWriter) error
+// CreateApiTokenParams defines parameters for CreateApiToken.
+type CreateApiTokenParams struct {
+	// IdempotencyKey Unique key of the request. Retrying the request with the same key returns the original response instead of repeating the mutation.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
-type GetSandboxByName200JSONResponse []Sandbox
+// ApproveApprovalJSONRequestBody defines body for ApproveApproval for application/json ContentType.
+type ApproveApprovalJSONRequestBody = ApprovalDecision
 
-func (response GetSandboxByName200JSONResponse) VisitGetSandboxByNameResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+// DenyApprovalJSONRequestBody defines body for DenyApproval for application/json ContentType.
+type DenyApprovalJSONRequestBody = ApprovalDecision
 
-	return json.NewEncoder(w).Encode(response)
-}
+// SetCatalogScheduleJSONRequestBody defines body for SetCatalogSchedule for application/json ContentType.
+type SetCatalogScheduleJSONRequestBody = ScheduleUpdate
 
-type GetSandboxByNamedefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+// CreateRevocationJSONRequestBody defines body for CreateRevocation for application/json ContentType.
+type CreateRevocationJSONRequestBody = RevocationCreate
 
-func (response GetSandboxByNamedefaultJSONResponse) VisitGetSandboxByNameResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+// CreateSandboxJSONRequestBody defines body for CreateSandbox for application/json ContentType.
+type CreateSandboxJSONRequestBody = SandboxCreate
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+// UpdateSandboxJSONRequestBody defines body for UpdateSandbox for application/merge-patch+json ContentType.
+type UpdateSandboxJSONRequestBody = SandboxPatch
 
-type DeleteSandboxRequestObject struct {
-	Id string `json:"id"`
+// SetSandboxScheduleJSONRequestBody defines body for SetSandboxSchedule for application/json ContentType.
+type SetSandboxScheduleJSONRequestBody = ScheduleUpdate
+
+// SkipSandboxScheduleJSONRequestBody defines body for SkipSandboxSchedule for application/json ContentType.
+type SkipSandboxScheduleJSONRequestBody = ScheduleSkip
+
+// BatchSandboxesJSONRequestBody defines body for BatchSandboxes for application/json ContentType.
+type BatchSandboxesJSONRequestBody = BatchRequest
+
+// CreateApiTokenJSONRequestBody defines body for CreateApiToken for application/json ContentType.
+type CreateApiTokenJSONRequestBody = ApiTokenCreate
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// List approval requests
+	// (GET /approvals)
+	ListApprovals(w http.ResponseWriter, r *http.Request, params ListApprovalsParams)
+	// Get an approval request
+	// (GET /approvals/{id})
+	GetApproval(w http.ResponseWriter, r *http.Request, id string)
+	// Approve an approval request
+	// (POST /approvals/{id}:approve)
+	ApproveApproval(w http.ResponseWriter, r *http.Request, id string, params ApproveApprovalParams)
+	// Deny an approval request
+	// (POST /approvals/{id}:deny)
+	DenyApproval(w http.ResponseWriter, r *http.Request, id string, params DenyApprovalParams)
+	// List audit events
+	// (GET /audit)
+	ListAuditEvents(w http.ResponseWriter, r *http.Request, params ListAuditEventsParams)
+	// Export audit events
+	// (GET /audit/export)
+	ExportAuditEvents(w http.ResponseWriter, r *http.Request, params ExportAuditEventsParams)
+	// Verify the audit log
+	// (GET /audit/verify)
+	VerifyAuditLog(w http.ResponseWriter, r *http.Request)
+	// Delete the schedule of a catalog
+	// (DELETE /catalogs/{catalog}/schedule)
+	DeleteCatalogSchedule(w http.ResponseWriter, r *http.Request, catalog string)
+	// Get the schedule of a catalog
+	// (GET /catalogs/{catalog}/schedule)
+	GetCatalogSchedule(w http.ResponseWriter, r *http.Request, catalog string)
+	// Set the schedule of a catalog
+	// (PUT /catalogs/{catalog}/schedule)
+	SetCatalogSchedule(w http.ResponseWriter, r *http.Request, catalog string)
+	// Health check
+	// (GET /health)
+	Health(w http.ResponseWriter, r *http.Request)
+	// Get an operation
+	// (GET /operations/{id})
+	GetOperation(w http.ResponseWriter, r *http.Request, id string)
+	// Cancel an operation
+	// (POST /operations/{id}:cancel)
+	CancelOperation(w http.ResponseWriter, r *http.Request, id string, params CancelOperationParams)
+	// List revoked tokens
+	// (GET /revocations)
+	ListRevocations(w http.ResponseWriter, r *http.Request)
+	// Revoke tokens
+	// (POST /revocations)
+	CreateRevocation(w http.ResponseWriter, r *http.Request, params CreateRevocationParams)
+	// List sandboxes
+	// (GET /sandboxes)
+	ListSandboxes(w http.ResponseWriter, r *http.Request, params ListSandboxesParams)
+	// Create a sandbox
+	// (POST /sandboxes)
+	CreateSandbox(w http.ResponseWriter, r *http.Request, params CreateSandboxParams)
+	// Get a sandbox by name
+	// (GET /sandboxes/name/{name})
+	GetSandboxByName(w http.ResponseWriter, r *http.Request, name string, params GetSandboxByNameParams)
+	// Delete a sandbox
+	// (DELETE /sandboxes/{id})
+	DeleteSandbox(w http.ResponseWriter, r *http.Request, id string, params DeleteSandboxParams)
+	// Get a sandbox
+	// (GET /sandboxes/{id})
+	GetSandbox(w http.ResponseWriter, r *http.Request, id string, params GetSandboxParams)
+	// Update a sandbox
+	// (PATCH /sandboxes/{id})
+	UpdateSandbox(w http.ResponseWriter, r *http.Request, id string, params UpdateSandboxParams)
+	// Delete the schedule of a sandbox
+	// (DELETE /sandboxes/{id}/schedule)
+	DeleteSandboxSchedule(w http.ResponseWriter, r *http.Request, id string)
+	// Get the schedule of a sandbox
+	// (GET /sandboxes/{id}/schedule)
+	GetSandboxSchedule(w http.ResponseWriter, r *http.Request, id string)
+	// Set the schedule of a sandbox
+	// (PUT /sandboxes/{id}/schedule)
+	SetSandboxSchedule(w http.ResponseWriter, r *http.Request, id string)
+	// Skip the next run of the schedule
+	// (POST /sandboxes/{id}/schedule:skip)
+	SkipSandboxSchedule(w http.ResponseWriter, r *http.Request, id string)
+	// Force delete a sandbox
+	// (POST /sandboxes/{id}:forceDelete)
+	ForceDeleteSandbox(w http.ResponseWriter, r *http.Request, id string, params ForceDeleteSandboxParams)
+	// Start a stopped sandbox
+	// (POST /sandboxes/{id}:start)
+	StartSandbox(w http.ResponseWriter, r *http.Request, id string, params StartSandboxParams)
+	// Stop a sandbox
+	// (POST /sandboxes/{id}:stop)
+	StopSandbox(w http.ResponseWriter, r *http.Request, id string, params StopSandboxParams)
+	// Run operations on many sandboxes
+	// (POST /sandboxes:batch)
+	BatchSandboxes(w http.ResponseWriter, r *http.Request, params BatchSandboxesParams)
+	// Reconcile the lost operations
+	// (POST /sandboxes:reconcile)
+	ReconcileSandboxes(w http.ResponseWriter, r *http.Request, params ReconcileSandboxesParams)
+	// Resolve a sandbox by name
+	// (GET /sandboxes:resolve)
+	ResolveSandbox(w http.ResponseWriter, r *http.Request, params ResolveSandboxParams)
+	// List API tokens
+	// (GET /tokens)
+	ListApiTokens(w http.ResponseWriter, r *http.Request)
+	// Create an API token
+	// (POST /tokens)
+	CreateApiToken(w http.ResponseWriter, r *http.Request, params CreateApiTokenParams)
+	// Revoke an API token
+	// (DELETE /tokens/{id})
+	RevokeApiToken(w http.ResponseWriter, r *http.Request, id string)
 }
 
-type DeleteSandboxResponseObject interface {
-	VisitDeleteSandboxResponse(w http.ResponseWriter) error
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
 }
 
-type DeleteSandbox204Response struct {
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// ListApprovals operation middleware
+func (siw *ServerInterfaceWrapper) ListApprovals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:approve"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListApprovalsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListApprovals(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetApproval operation middleware
+func (siw *ServerInterfaceWrapper) GetApproval(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:approve"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetApproval(w, r, id)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ApproveApproval operation middleware
+func (siw *ServerInterfaceWrapper) ApproveApproval(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:approve"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ApproveApprovalParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ApproveApproval(w, r, id, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DenyApproval operation middleware
+func (siw *ServerInterfaceWrapper) DenyApproval(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:approve"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DenyApprovalParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DenyApproval(w, r, id, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListAuditEvents operation middleware
+func (siw *ServerInterfaceWrapper) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuditEventsParams
+
+	// ------------- Optional query parameter "actor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor", r.URL.Query(), &params.Actor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "actor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "action" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "action", r.URL.Query(), &params.Action)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "action", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "targetType" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "targetType", r.URL.Query(), &params.TargetType)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "targetType", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "targetId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "targetId", r.URL.Query(), &params.TargetId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "targetId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "requestId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "requestId", r.URL.Query(), &params.RequestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "requestId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "outcome" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "outcome", r.URL.Query(), &params.Outcome)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "outcome", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAuditEvents(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ExportAuditEvents operation middleware
+func (siw *ServerInterfaceWrapper) ExportAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportAuditEventsParams
+
+	// ------------- Optional query parameter "actor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor", r.URL.Query(), &params.Actor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "actor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "action" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "action", r.URL.Query(), &params.Action)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "action", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "targetType" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "targetType", r.URL.Query(), &params.TargetType)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "targetType", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "targetId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "targetId", r.URL.Query(), &params.TargetId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "targetId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "requestId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "requestId", r.URL.Query(), &params.RequestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "requestId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "outcome" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "outcome", r.URL.Query(), &params.Outcome)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "outcome", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportAuditEvents(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// VerifyAuditLog operation middleware
+func (siw *ServerInterfaceWrapper) VerifyAuditLog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.VerifyAuditLog(w, r)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteCatalogSchedule operation middleware
+func (siw *ServerInterfaceWrapper) DeleteCatalogSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "catalog" -------------
+	var catalog string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "catalog", runtime.ParamLocationPath, chi.URLParam(r, "catalog"), &catalog)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "catalog", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteCatalogSchedule(w, r, catalog)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetCatalogSchedule operation middleware
+func (siw *ServerInterfaceWrapper) GetCatalogSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "catalog" -------------
+	var catalog string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "catalog", runtime.ParamLocationPath, chi.URLParam(r, "catalog"), &catalog)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "catalog", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:r"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCatalogSchedule(w, r, catalog)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SetCatalogSchedule operation middleware
+func (siw *ServerInterfaceWrapper) SetCatalogSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "catalog" -------------
+	var catalog string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "catalog", runtime.ParamLocationPath, chi.URLParam(r, "catalog"), &catalog)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "catalog", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetCatalogSchedule(w, r, catalog)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Health operation middleware
+func (siw *ServerInterfaceWrapper) Health(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Health(w, r)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetOperation operation middleware
+func (siw *ServerInterfaceWrapper) GetOperation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:r"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetOperation(w, r, id)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CancelOperation operation middleware
+func (siw *ServerInterfaceWrapper) CancelOperation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CancelOperationParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CancelOperation(w, r, id, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListRevocations operation middleware
+func (siw *ServerInterfaceWrapper) ListRevocations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:admin"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListRevocations(w, r)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateRevocation operation middleware
+func (siw *ServerInterfaceWrapper) CreateRevocation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateRevocationParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateRevocation(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListSandboxes operation middleware
+func (siw *ServerInterfaceWrapper) ListSandboxes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:r"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListSandboxesParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "owner" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "owner", r.URL.Query(), &params.Owner)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "owner", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "namePrefix" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "namePrefix", r.URL.Query(), &params.NamePrefix)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "namePrefix", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "labelSelector" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "labelSelector", r.URL.Query(), &params.LabelSelector)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "labelSelector", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "createdAfter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdAfter", r.URL.Query(), &params.CreatedAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "createdAfter", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "createdBefore" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdBefore", r.URL.Query(), &params.CreatedBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "createdBefore", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "expiresAfter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "expiresAfter", r.URL.Query(), &params.ExpiresAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "expiresAfter", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "expiresBefore" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "expiresBefore", r.URL.Query(), &params.ExpiresBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "expiresBefore", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "expiringWithin" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "expiringWithin", r.URL.Query(), &params.ExpiringWithin)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "expiringWithin", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", r.URL.Query(), &params.Order)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "order", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "includeTotal" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeTotal", r.URL.Query(), &params.IncludeTotal)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "includeTotal", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListSandboxes(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateSandbox operation middleware
+func (siw *ServerInterfaceWrapper) CreateSandbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateSandboxParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateSandbox(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetSandboxByName operation middleware
+func (siw *ServerInterfaceWrapper) GetSandboxByName(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, chi.URLParam(r, "name"), &name)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "name", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:r"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSandboxByNameParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "includeTotal" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeTotal", r.URL.Query(), &params.IncludeTotal)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "includeTotal", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSandboxByName(w, r, name, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteSandbox operation middleware
+func (siw *ServerInterfaceWrapper) DeleteSandbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteSandboxParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteSandbox(w, r, id, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetSandbox operation middleware
+func (siw *ServerInterfaceWrapper) GetSandbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:r"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSandboxParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, valueList[0], &IfNoneMatch)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSandbox(w, r, id, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateSandbox operation middleware
+func (siw *ServerInterfaceWrapper) UpdateSandbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateSandboxParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateSandbox(w, r, id, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteSandboxSchedule operation middleware
+func (siw *ServerInterfaceWrapper) DeleteSandboxSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteSandboxSchedule(w, r, id)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetSandboxSchedule operation middleware
+func (siw *ServerInterfaceWrapper) GetSandboxSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:r"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSandboxSchedule(w, r, id)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SetSandboxSchedule operation middleware
+func (siw *ServerInterfaceWrapper) SetSandboxSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetSandboxSchedule(w, r, id)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SkipSandboxSchedule operation middleware
+func (siw *ServerInterfaceWrapper) SkipSandboxSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SkipSandboxSchedule(w, r, id)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ForceDeleteSandbox operation middleware
+func (siw *ServerInterfaceWrapper) ForceDeleteSandbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ForceDeleteSandboxParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ForceDeleteSandbox(w, r, id, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// StartSandbox operation middleware
+func (siw *ServerInterfaceWrapper) StartSandbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StartSandboxParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StartSandbox(w, r, id, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// StopSandbox operation middleware
+func (siw *ServerInterfaceWrapper) StopSandbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:w"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StopSandboxParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StopSandbox(w, r, id, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// BatchSandboxes operation middleware
+func (siw *ServerInterfaceWrapper) BatchSandboxes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params BatchSandboxesParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.BatchSandboxes(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ReconcileSandboxes operation middleware
+func (siw *ServerInterfaceWrapper) ReconcileSandboxes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ReconcileSandboxesParams
+
+	// ------------- Optional query parameter "dryRun" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dryRun", r.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dryRun", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReconcileSandboxes(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ResolveSandbox operation middleware
+func (siw *ServerInterfaceWrapper) ResolveSandbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:r"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ResolveSandboxParams
+
+	// ------------- Required query parameter "name" -------------
+
+	if paramValue := r.URL.Query().Get("name"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "name"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "name", r.URL.Query(), &params.Name)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "name", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ResolveSandbox(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListApiTokens operation middleware
+func (siw *ServerInterfaceWrapper) ListApiTokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:r"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListApiTokens(w, r)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateApiToken operation middleware
+func (siw *ServerInterfaceWrapper) CreateApiToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:r"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateApiTokenParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateApiToken(w, r, params)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RevokeApiToken operation middleware
+func (siw *ServerInterfaceWrapper) RevokeApiToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"sandbox:r"})
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeApiToken(w, r, id)
+	})
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshallingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshallingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshalling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshallingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/approvals", wrapper.ListApprovals)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/approvals/{id}", wrapper.GetApproval)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/approvals/{id}:approve", wrapper.ApproveApproval)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/approvals/{id}:deny", wrapper.DenyApproval)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/audit", wrapper.ListAuditEvents)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/audit/export", wrapper.ExportAuditEvents)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/audit/verify", wrapper.VerifyAuditLog)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/catalogs/{catalog}/schedule", wrapper.DeleteCatalogSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/catalogs/{catalog}/schedule", wrapper.GetCatalogSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/catalogs/{catalog}/schedule", wrapper.SetCatalogSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/health", wrapper.Health)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/operations/{id}", wrapper.GetOperation)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/operations/{id}:cancel", wrapper.CancelOperation)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/revocations", wrapper.ListRevocations)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/revocations", wrapper.CreateRevocation)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sandboxes", wrapper.ListSandboxes)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sandboxes", wrapper.CreateSandbox)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sandboxes/name/{name}", wrapper.GetSandboxByName)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/sandboxes/{id}", wrapper.DeleteSandbox)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sandboxes/{id}", wrapper.GetSandbox)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/sandboxes/{id}", wrapper.UpdateSandbox)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/sandboxes/{id}/schedule", wrapper.DeleteSandboxSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sandboxes/{id}/schedule", wrapper.GetSandboxSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/sandboxes/{id}/schedule", wrapper.SetSandboxSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sandboxes/{id}/schedule:skip", wrapper.SkipSandboxSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sandboxes/{id}:forceDelete", wrapper.ForceDeleteSandbox)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sandboxes/{id}:start", wrapper.StartSandbox)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sandboxes/{id}:stop", wrapper.StopSandbox)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sandboxes:batch", wrapper.BatchSandboxes)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sandboxes:reconcile", wrapper.ReconcileSandboxes)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sandboxes:resolve", wrapper.ResolveSandbox)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/tokens", wrapper.ListApiTokens)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/tokens", wrapper.CreateApiToken)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/tokens/{id}", wrapper.RevokeApiToken)
+	})
+
+	return r
+}
+
+type ListApprovalsRequestObject struct {
+	Params ListApprovalsParams
+}
+
+type ListApprovalsResponseObject interface {
+	VisitListApprovalsResponse(w http.ResponseWriter) error
+}
+
+type ListApprovals200JSONResponse []Approval
+
+func (response ListApprovals200JSONResponse) VisitListApprovalsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListApprovalsdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ListApprovalsdefaultJSONResponse) VisitListApprovalsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetApprovalRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetApprovalResponseObject interface {
+	VisitGetApprovalResponse(w http.ResponseWriter) error
+}
+
+type GetApproval200JSONResponse Approval
+
+func (response GetApproval200JSONResponse) VisitGetApprovalResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetApprovaldefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response GetApprovaldefaultJSONResponse) VisitGetApprovalResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ApproveApprovalRequestObject struct {
+	Id     string `json:"id"`
+	Params ApproveApprovalParams
+	Body   *ApproveApprovalJSONRequestBody
+}
+
+type ApproveApprovalResponseObject interface {
+	VisitApproveApprovalResponse(w http.ResponseWriter) error
+}
+
+type ApproveApproval200JSONResponse Approval
+
+func (response ApproveApproval200JSONResponse) VisitApproveApprovalResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ApproveApprovaldefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ApproveApprovaldefaultJSONResponse) VisitApproveApprovalResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DenyApprovalRequestObject struct {
+	Id     string `json:"id"`
+	Params DenyApprovalParams
+	Body   *DenyApprovalJSONRequestBody
+}
+
+type DenyApprovalResponseObject interface {
+	VisitDenyApprovalResponse(w http.ResponseWriter) error
+}
+
+type DenyApproval200JSONResponse Approval
+
+func (response DenyApproval200JSONResponse) VisitDenyApprovalResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DenyApprovaldefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response DenyApprovaldefaultJSONResponse) VisitDenyApprovalResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListAuditEventsRequestObject struct {
+	Params ListAuditEventsParams
+}
+
+type ListAuditEventsResponseObject interface {
+	VisitListAuditEventsResponse(w http.ResponseWriter) error
+}
+
+type ListAuditEvents200ResponseHeaders struct {
+	Link string
+}
+
+type ListAuditEvents200JSONResponse struct {
+	Body    []AuditEvent
+	Headers ListAuditEvents200ResponseHeaders
+}
+
+func (response ListAuditEvents200JSONResponse) VisitListAuditEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Link", fmt.Sprint(response.Headers.Link))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListAuditEventsdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ListAuditEventsdefaultJSONResponse) VisitListAuditEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExportAuditEventsRequestObject struct {
+	Params ExportAuditEventsParams
+}
+
+type ExportAuditEventsResponseObject interface {
+	VisitExportAuditEventsResponse(w http.ResponseWriter) error
+}
+
+type ExportAuditEvents200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response ExportAuditEvents200ApplicationxNdjsonResponse) VisitExportAuditEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportAuditEventsdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ExportAuditEventsdefaultJSONResponse) VisitExportAuditEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type VerifyAuditLogRequestObject struct {
+}
+
+type VerifyAuditLogResponseObject interface {
+	VisitVerifyAuditLogResponse(w http.ResponseWriter) error
+}
+
+type VerifyAuditLog200JSONResponse AuditVerification
+
+func (response VerifyAuditLog200JSONResponse) VisitVerifyAuditLogResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type VerifyAuditLogdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response VerifyAuditLogdefaultJSONResponse) VisitVerifyAuditLogResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteCatalogScheduleRequestObject struct {
+	Catalog string `json:"catalog"`
+}
+
+type DeleteCatalogScheduleResponseObject interface {
+	VisitDeleteCatalogScheduleResponse(w http.ResponseWriter) error
+}
+
+type DeleteCatalogSchedule204Response struct {
+}
+
+func (response DeleteCatalogSchedule204Response) VisitDeleteCatalogScheduleResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteCatalogScheduledefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response DeleteCatalogScheduledefaultJSONResponse) VisitDeleteCatalogScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetCatalogScheduleRequestObject struct {
+	Catalog string `json:"catalog"`
+}
+
+type GetCatalogScheduleResponseObject interface {
+	VisitGetCatalogScheduleResponse(w http.ResponseWriter) error
+}
+
+type GetCatalogSchedule200JSONResponse CatalogSchedule
+
+func (response GetCatalogSchedule200JSONResponse) VisitGetCatalogScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetCatalogScheduledefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response GetCatalogScheduledefaultJSONResponse) VisitGetCatalogScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SetCatalogScheduleRequestObject struct {
+	Catalog string `json:"catalog"`
+	Body    *SetCatalogScheduleJSONRequestBody
+}
+
+type SetCatalogScheduleResponseObject interface {
+	VisitSetCatalogScheduleResponse(w http.ResponseWriter) error
+}
+
+type SetCatalogSchedule200JSONResponse CatalogSchedule
+
+func (response SetCatalogSchedule200JSONResponse) VisitSetCatalogScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SetCatalogScheduledefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response SetCatalogScheduledefaultJSONResponse) VisitSetCatalogScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type HealthRequestObject struct {
+}
+
+type HealthResponseObject interface {
+	VisitHealthResponse(w http.ResponseWriter) error
+}
+
+type Health200JSONResponse Status
+
+func (response Health200JSONResponse) VisitHealthResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetOperationRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetOperationResponseObject interface {
+	VisitGetOperationResponse(w http.ResponseWriter) error
+}
+
+type GetOperation200JSONResponse Operation
+
+func (response GetOperation200JSONResponse) VisitGetOperationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetOperationdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response GetOperationdefaultJSONResponse) VisitGetOperationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CancelOperationRequestObject struct {
+	Id     string `json:"id"`
+	Params CancelOperationParams
+}
+
+type CancelOperationResponseObject interface {
+	VisitCancelOperationResponse(w http.ResponseWriter) error
+}
+
+type CancelOperation200JSONResponse Operation
+
+func (response CancelOperation200JSONResponse) VisitCancelOperationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CancelOperationdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response CancelOperationdefaultJSONResponse) VisitCancelOperationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListRevocationsRequestObject struct {
+}
+
+type ListRevocationsResponseObject interface {
+	VisitListRevocationsResponse(w http.ResponseWriter) error
+}
+
+type ListRevocations200JSONResponse []Revocation
+
+func (response ListRevocations200JSONResponse) VisitListRevocationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListRevocationsdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ListRevocationsdefaultJSONResponse) VisitListRevocationsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateRevocationRequestObject struct {
+	Params CreateRevocationParams
+	Body   *CreateRevocationJSONRequestBody
+}
+
+type CreateRevocationResponseObject interface {
+	VisitCreateRevocationResponse(w http.ResponseWriter) error
+}
+
+type CreateRevocation201JSONResponse Revocation
+
+func (response CreateRevocation201JSONResponse) VisitCreateRevocationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateRevocationdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response CreateRevocationdefaultJSONResponse) VisitCreateRevocationResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListSandboxesRequestObject struct {
+	Params ListSandboxesParams
+}
+
+type ListSandboxesResponseObject interface {
+	VisitListSandboxesResponse(w http.ResponseWriter) error
+}
+
+type ListSandboxes200ResponseHeaders struct {
+	Link        string
+	XTotalCount int
+}
+
+type ListSandboxes200JSONResponse struct {
+	Body    []Sandbox
+	Headers ListSandboxes200ResponseHeaders
+}
+
+func (response ListSandboxes200JSONResponse) VisitListSandboxesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Link", fmt.Sprint(response.Headers.Link))
+	w.Header().Set("X-Total-Count", fmt.Sprint(response.Headers.XTotalCount))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListSandboxesdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ListSandboxesdefaultJSONResponse) VisitListSandboxesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateSandboxRequestObject struct {
+	Params CreateSandboxParams
+	Body   *CreateSandboxJSONRequestBody
+}
+
+type CreateSandboxResponseObject interface {
+	VisitCreateSandboxResponse(w http.ResponseWriter) error
+}
+
+type CreateSandbox202ResponseHeaders struct {
+	Location          string
+	OperationLocation string
+}
+
+type CreateSandbox202JSONResponse struct {
+	Body    Operation
+	Headers CreateSandbox202ResponseHeaders
+}
+
+func (response CreateSandbox202JSONResponse) VisitCreateSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.Header().Set("Operation-Location", fmt.Sprint(response.Headers.OperationLocation))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateSandbox429ResponseHeaders struct {
+	RetryAfter int
+}
+
+type CreateSandbox429JSONResponse struct {
+	Body    Problem
+	Headers CreateSandbox429ResponseHeaders
+}
+
+func (response CreateSandbox429JSONResponse) VisitCreateSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateSandboxdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response CreateSandboxdefaultJSONResponse) VisitCreateSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetSandboxByNameRequestObject struct {
+	Name   string `json:"name"`
+	Params GetSandboxByNameParams
+}
+
+type GetSandboxByNameResponseObject interface {
+	VisitGetSandboxByNameResponse(w http.ResponseWriter) error
+}
+
+type GetSandboxByName200ResponseHeaders struct {
+	Link        string
+	XTotalCount int
+}
+
+type GetSandboxByName200JSONResponse struct {
+	Body    []Sandbox
+	Headers GetSandboxByName200ResponseHeaders
+}
+
+func (response GetSandboxByName200JSONResponse) VisitGetSandboxByNameResponse(w http.ResponseWriter) error {
+	w.Header().Set("Link", fmt.Sprint(response.Headers.Link))
+	w.Header().Set("X-Total-Count", fmt.Sprint(response.Headers.XTotalCount))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetSandboxByNamedefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response GetSandboxByNamedefaultJSONResponse) VisitGetSandboxByNameResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteSandboxRequestObject struct {
+	Id     string `json:"id"`
+	Params DeleteSandboxParams
+}
+
+type DeleteSandboxResponseObject interface {
+	VisitDeleteSandboxResponse(w http.ResponseWriter) error
+}
+
+type DeleteSandbox202ResponseHeaders struct {
+	OperationLocation string
+}
+
+type DeleteSandbox202JSONResponse struct {
+	Body    Operation
+	Headers DeleteSandbox202ResponseHeaders
+}
+
+func (response DeleteSandbox202JSONResponse) VisitDeleteSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Operation-Location", fmt.Sprint(response.Headers.OperationLocation))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteSandbox412JSONResponse Problem
+
+func (response DeleteSandbox412JSONResponse) VisitDeleteSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(412)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteSandboxdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response DeleteSandboxdefaultJSONResponse) VisitDeleteSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetSandboxRequestObject struct {
+	Id     string `json:"id"`
+	Params GetSandboxParams
+}
+
+type GetSandboxResponseObject interface {
+	VisitGetSandboxResponse(w http.ResponseWriter) error
+}
+
+type GetSandbox200ResponseHeaders struct {
+	ETag string
+}
+
+type GetSandbox200JSONResponse struct {
+	Body    Sandbox
+	Headers GetSandbox200ResponseHeaders
+}
+
+func (response GetSandbox200JSONResponse) VisitGetSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetSandbox304ResponseHeaders struct {
+	ETag string
+}
+
+type GetSandbox304Response struct {
+	Headers GetSandbox304ResponseHeaders
+}
+
+func (response GetSandbox304Response) VisitGetSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetSandboxdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response GetSandboxdefaultJSONResponse) VisitGetSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateSandboxRequestObject struct {
+	Id     string `json:"id"`
+	Params UpdateSandboxParams
+	Body   *UpdateSandboxJSONRequestBody
+}
+
+type UpdateSandboxResponseObject interface {
+	VisitUpdateSandboxResponse(w http.ResponseWriter) error
+}
+
+type UpdateSandbox202ResponseHeaders struct {
+	ETag              string
+	OperationLocation string
+}
+
+type UpdateSandbox202JSONResponse struct {
+	Body    Operation
+	Headers UpdateSandbox202ResponseHeaders
+}
+
+func (response UpdateSandbox202JSONResponse) VisitUpdateSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Operation-Location", fmt.Sprint(response.Headers.OperationLocation))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateSandbox412JSONResponse Problem
+
+func (response UpdateSandbox412JSONResponse) VisitUpdateSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(412)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateSandboxdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response UpdateSandboxdefaultJSONResponse) VisitUpdateSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type DeleteSandboxScheduleRequestObject struct {
+	Id string `json:"id"`
+}
+
+type DeleteSandboxScheduleResponseObject interface {
+	VisitDeleteSandboxScheduleResponse(w http.ResponseWriter) error
+}
+
+type DeleteSandboxSchedule204Response struct {
+}
+
+func (response DeleteSandboxSchedule204Response) VisitDeleteSandboxScheduleResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteSandboxScheduledefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response DeleteSandboxScheduledefaultJSONResponse) VisitDeleteSandboxScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetSandboxScheduleRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetSandboxScheduleResponseObject interface {
+	VisitGetSandboxScheduleResponse(w http.ResponseWriter) error
+}
+
+type GetSandboxSchedule200JSONResponse Schedule
+
+func (response GetSandboxSchedule200JSONResponse) VisitGetSandboxScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetSandboxScheduledefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response GetSandboxScheduledefaultJSONResponse) VisitGetSandboxScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SetSandboxScheduleRequestObject struct {
+	Id   string `json:"id"`
+	Body *SetSandboxScheduleJSONRequestBody
+}
+
+type SetSandboxScheduleResponseObject interface {
+	VisitSetSandboxScheduleResponse(w http.ResponseWriter) error
+}
+
+type SetSandboxSchedule200JSONResponse Schedule
+
+func (response SetSandboxSchedule200JSONResponse) VisitSetSandboxScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SetSandboxScheduledefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response SetSandboxScheduledefaultJSONResponse) VisitSetSandboxScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SkipSandboxScheduleRequestObject struct {
+	Id   string `json:"id"`
+	Body *SkipSandboxScheduleJSONRequestBody
+}
+
+type SkipSandboxScheduleResponseObject interface {
+	VisitSkipSandboxScheduleResponse(w http.ResponseWriter) error
+}
+
+type SkipSandboxSchedule200JSONResponse Schedule
+
+func (response SkipSandboxSchedule200JSONResponse) VisitSkipSandboxScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SkipSandboxScheduledefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response SkipSandboxScheduledefaultJSONResponse) VisitSkipSandboxScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ForceDeleteSandboxRequestObject struct {
+	Id     string `json:"id"`
+	Params ForceDeleteSandboxParams
+}
+
+type ForceDeleteSandboxResponseObject interface {
+	VisitForceDeleteSandboxResponse(w http.ResponseWriter) error
+}
+
+type ForceDeleteSandbox202ResponseHeaders struct {
+	OperationLocation string
+}
+
+type ForceDeleteSandbox202JSONResponse struct {
+	Body    Operation
+	Headers ForceDeleteSandbox202ResponseHeaders
+}
+
+func (response ForceDeleteSandbox202JSONResponse) VisitForceDeleteSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Operation-Location", fmt.Sprint(response.Headers.OperationLocation))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ForceDeleteSandboxdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ForceDeleteSandboxdefaultJSONResponse) VisitForceDeleteSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type StartSandboxRequestObject struct {
+	Id     string `json:"id"`
+	Params StartSandboxParams
+}
+
+type StartSandboxResponseObject interface {
+	VisitStartSandboxResponse(w http.ResponseWriter) error
+}
+
+type StartSandbox202ResponseHeaders struct {
+	OperationLocation string
+}
+
+type StartSandbox202JSONResponse struct {
+	Body    Operation
+	Headers StartSandbox202ResponseHeaders
+}
+
+func (response StartSandbox202JSONResponse) VisitStartSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Operation-Location", fmt.Sprint(response.Headers.OperationLocation))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type StartSandboxdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response StartSandboxdefaultJSONResponse) VisitStartSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type StopSandboxRequestObject struct {
+	Id     string `json:"id"`
+	Params StopSandboxParams
+}
+
+type StopSandboxResponseObject interface {
+	VisitStopSandboxResponse(w http.ResponseWriter) error
+}
+
+type StopSandbox202ResponseHeaders struct {
+	OperationLocation string
+}
+
+type StopSandbox202JSONResponse struct {
+	Body    Operation
+	Headers StopSandbox202ResponseHeaders
+}
+
+func (response StopSandbox202JSONResponse) VisitStopSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Operation-Location", fmt.Sprint(response.Headers.OperationLocation))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type StopSandboxdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response StopSandboxdefaultJSONResponse) VisitStopSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type BatchSandboxesRequestObject struct {
+	Params BatchSandboxesParams
+	Body   *BatchSandboxesJSONRequestBody
+}
+
+type BatchSandboxesResponseObject interface {
+	VisitBatchSandboxesResponse(w http.ResponseWriter) error
+}
+
+type BatchSandboxes200JSONResponse BatchResponse
+
+func (response BatchSandboxes200JSONResponse) VisitBatchSandboxesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type BatchSandboxesdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response BatchSandboxesdefaultJSONResponse) VisitBatchSandboxesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ReconcileSandboxesRequestObject struct {
+	Params ReconcileSandboxesParams
+}
+
+type ReconcileSandboxesResponseObject interface {
+	VisitReconcileSandboxesResponse(w http.ResponseWriter) error
+}
+
+type ReconcileSandboxes200JSONResponse ReconcileResponse
+
+func (response ReconcileSandboxes200JSONResponse) VisitReconcileSandboxesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReconcileSandboxesdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ReconcileSandboxesdefaultJSONResponse) VisitReconcileSandboxesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ResolveSandboxRequestObject struct {
+	Params ResolveSandboxParams
+}
+
+type ResolveSandboxResponseObject interface {
+	VisitResolveSandboxResponse(w http.ResponseWriter) error
+}
+
+type ResolveSandbox200ResponseHeaders struct {
+	ETag string
+}
+
+type ResolveSandbox200JSONResponse struct {
+	Body    Sandbox
+	Headers ResolveSandbox200ResponseHeaders
+}
+
+func (response ResolveSandbox200JSONResponse) VisitResolveSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ResolveSandboxdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ResolveSandboxdefaultJSONResponse) VisitResolveSandboxResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListApiTokensRequestObject struct {
+}
+
+type ListApiTokensResponseObject interface {
+	VisitListApiTokensResponse(w http.ResponseWriter) error
+}
+
+type ListApiTokens200JSONResponse []ApiToken
+
+func (response ListApiTokens200JSONResponse) VisitListApiTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListApiTokensdefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ListApiTokensdefaultJSONResponse) VisitListApiTokensResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CreateApiTokenRequestObject struct {
+	Params CreateApiTokenParams
+	Body   *CreateApiTokenJSONRequestBody
+}
+
+type CreateApiTokenResponseObject interface {
+	VisitCreateApiTokenResponse(w http.ResponseWriter) error
+}
+
+type CreateApiToken201JSONResponse ApiTokenCreated
+
+func (response CreateApiToken201JSONResponse) VisitCreateApiTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateApiTokendefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response CreateApiTokendefaultJSONResponse) VisitCreateApiTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RevokeApiTokenRequestObject struct {
+	Id string `json:"id"`
+}
+
+type RevokeApiTokenResponseObject interface {
+	VisitRevokeApiTokenResponse(w http.ResponseWriter) error
+}
+
+type RevokeApiToken204Response struct {
+}
+
+func (response RevokeApiToken204Response) VisitRevokeApiTokenResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type RevokeApiTokendefaultJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response RevokeApiTokendefaultJSONResponse) VisitRevokeApiTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// List approval requests
+	// (GET /approvals)
+	ListApprovals(ctx context.Context, request ListApprovalsRequestObject) (ListApprovalsResponseObject, error)
+	// Get an approval request
+	// (GET /approvals/{id})
+	GetApproval(ctx context.Context, request GetApprovalRequestObject) (GetApprovalResponseObject, error)
+	// Approve an approval request
+	// (POST /approvals/{id}:approve)
+	ApproveApproval(ctx context.Context, request ApproveApprovalRequestObject) (ApproveApprovalResponseObject, error)
+	// Deny an approval request
+	// (POST /approvals/{id}:deny)
+	DenyApproval(ctx context.Context, request DenyApprovalRequestObject) (DenyApprovalResponseObject, error)
+	// List audit events
+	// (GET /audit)
+	ListAuditEvents(ctx context.Context, request ListAuditEventsRequestObject) (ListAuditEventsResponseObject, error)
+	// Export audit events
+	// (GET /audit/export)
+	ExportAuditEvents(ctx context.Context, request ExportAuditEventsRequestObject) (ExportAuditEventsResponseObject, error)
+	// Verify the audit log
+	// (GET /audit/verify)
+	VerifyAuditLog(ctx context.Context, request VerifyAuditLogRequestObject) (VerifyAuditLogResponseObject, error)
+	// Delete the schedule of a catalog
+	// (DELETE /catalogs/{catalog}/schedule)
+	DeleteCatalogSchedule(ctx context.Context, request DeleteCatalogScheduleRequestObject) (DeleteCatalogScheduleResponseObject, error)
+	// Get the schedule of a catalog
+	// (GET /catalogs/{catalog}/schedule)
+	GetCatalogSchedule(ctx context.Context, request GetCatalogScheduleRequestObject) (GetCatalogScheduleResponseObject, error)
+	// Set the schedule of a catalog
+	// (PUT /catalogs/{catalog}/schedule)
+	SetCatalogSchedule(ctx context.Context, request SetCatalogScheduleRequestObject) (SetCatalogScheduleResponseObject, error)
+	// Health check
+	// (GET /health)
+	Health(ctx context.Context, request HealthRequestObject) (HealthResponseObject, error)
+	// Get an operation
+	// (GET /operations/{id})
+	GetOperation(ctx context.Context, request GetOperationRequestObject) (GetOperationResponseObject, error)
+	// Cancel an operation
+	// (POST /operations/{id}:cancel)
+	CancelOperation(ctx context.Context, request CancelOperationRequestObject) (CancelOperationResponseObject, error)
+	// List revoked tokens
+	// (GET /revocations)
+	ListRevocations(ctx context.Context, request ListRevocationsRequestObject) (ListRevocationsResponseObject, error)
+	// Revoke tokens
+	// (POST /revocations)
+	CreateRevocation(ctx context.Context, request CreateRevocationRequestObject) (CreateRevocationResponseObject, error)
+	// List sandboxes
+	// (GET /sandboxes)
+	ListSandboxes(ctx context.Context, request ListSandboxesRequestObject) (ListSandboxesResponseObject, error)
+	// Create a sandbox
+	// (POST /sandboxes)
+	CreateSandbox(ctx context.Context, request CreateSandboxRequestObject) (CreateSandboxResponseObject, error)
+	// Get a sandbox by name
+	// (GET /sandboxes/name/{name})
+	GetSandboxByName(ctx context.Context, request GetSandboxByNameRequestObject) (GetSandboxByNameResponseObject, error)
+	// Delete a sandbox
+	// (DELETE /sandboxes/{id})
+	DeleteSandbox(ctx context.Context, request DeleteSandboxRequestObject) (DeleteSandboxResponseObject, error)
+	// Get a sandbox
+	// (GET /sandboxes/{id})
+	GetSandbox(ctx context.Context, request GetSandboxRequestObject) (GetSandboxResponseObject, error)
+	// Update a sandbox
+	// (PATCH /sandboxes/{id})
+	UpdateSandbox(ctx context.Context, request UpdateSandboxRequestObject) (UpdateSandboxResponseObject, error)
+	// Delete the schedule of a sandbox
+	// (DELETE /sandboxes/{id}/schedule)
+	DeleteSandboxSchedule(ctx context.Context, request DeleteSandboxScheduleRequestObject) (DeleteSandboxScheduleResponseObject, error)
+	// Get the schedule of a sandbox
+	// (GET /sandboxes/{id}/schedule)
+	GetSandboxSchedule(ctx context.Context, request GetSandboxScheduleRequestObject) (GetSandboxScheduleResponseObject, error)
+	// Set the schedule of a sandbox
+	// (PUT /sandboxes/{id}/schedule)
+	SetSandboxSchedule(ctx context.Context, request SetSandboxScheduleRequestObject) (SetSandboxScheduleResponseObject, error)
+	// Skip the next run of the schedule
+	// (POST /sandboxes/{id}/schedule:skip)
+	SkipSandboxSchedule(ctx context.Context, request SkipSandboxScheduleRequestObject) (SkipSandboxScheduleResponseObject, error)
+	// Force delete a sandbox
+	// (POST /sandboxes/{id}:forceDelete)
+	ForceDeleteSandbox(ctx context.Context, request ForceDeleteSandboxRequestObject) (ForceDeleteSandboxResponseObject, error)
+	// Start a stopped sandbox
+	// (POST /sandboxes/{id}:start)
+	StartSandbox(ctx context.Context, request StartSandboxRequestObject) (StartSandboxResponseObject, error)
+	// Stop a sandbox
+	// (POST /sandboxes/{id}:stop)
+	StopSandbox(ctx context.Context, request StopSandboxRequestObject) (StopSandboxResponseObject, error)
+	// Run operations on many sandboxes
+	// (POST /sandboxes:batch)
+	BatchSandboxes(ctx context.Context, request BatchSandboxesRequestObject) (BatchSandboxesResponseObject, error)
+	// Reconcile the lost operations
+	// (POST /sandboxes:reconcile)
+	ReconcileSandboxes(ctx context.Context, request ReconcileSandboxesRequestObject) (ReconcileSandboxesResponseObject, error)
+	// Resolve a sandbox by name
+	// (GET /sandboxes:resolve)
+	ResolveSandbox(ctx context.Context, request ResolveSandboxRequestObject) (ResolveSandboxResponseObject, error)
+	// List API tokens
+	// (GET /tokens)
+	ListApiTokens(ctx context.Context, request ListApiTokensRequestObject) (ListApiTokensResponseObject, error)
+	// Create an API token
+	// (POST /tokens)
+	CreateApiToken(ctx context.Context, request CreateApiTokenRequestObject) (CreateApiTokenResponseObject, error)
+	// Revoke an API token
+	// (DELETE /tokens/{id})
+	RevokeApiToken(ctx context.Context, request RevokeApiTokenRequestObject) (RevokeApiTokenResponseObject, error)
+}
+
+type StrictHandlerFunc = runtime.StrictHttpHandlerFunc
+type StrictMiddlewareFunc = runtime.StrictHttpMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// ListApprovals operation middleware
+func (sh *strictHandler) ListApprovals(w http.ResponseWriter, r *http.Request, params ListApprovalsParams) {
+	var request ListApprovalsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListApprovals(ctx, request.(ListApprovalsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListApprovals")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListApprovalsResponseObject); ok {
+		if err := validResponse.VisitListApprovalsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// GetApproval operation middleware
+func (sh *strictHandler) GetApproval(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetApprovalRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetApproval(ctx, request.(GetApprovalRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetApproval")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetApprovalResponseObject); ok {
+		if err := validResponse.VisitGetApprovalResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// ApproveApproval operation middleware
+func (sh *strictHandler) ApproveApproval(w http.ResponseWriter, r *http.Request, id string, params ApproveApprovalParams) {
+	var request ApproveApprovalRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	var body ApproveApprovalJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ApproveApproval(ctx, request.(ApproveApprovalRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ApproveApproval")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ApproveApprovalResponseObject); ok {
+		if err := validResponse.VisitApproveApprovalResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// DenyApproval operation middleware
+func (sh *strictHandler) DenyApproval(w http.ResponseWriter, r *http.Request, id string, params DenyApprovalParams) {
+	var request DenyApprovalRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	var body DenyApprovalJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DenyApproval(ctx, request.(DenyApprovalRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DenyApproval")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DenyApprovalResponseObject); ok {
+		if err := validResponse.VisitDenyApprovalResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// ListAuditEvents operation middleware
+func (sh *strictHandler) ListAuditEvents(w http.ResponseWriter, r *http.Request, params ListAuditEventsParams) {
+	var request ListAuditEventsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListAuditEvents(ctx, request.(ListAuditEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListAuditEvents")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListAuditEventsResponseObject); ok {
+		if err := validResponse.VisitListAuditEventsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// ExportAuditEvents operation middleware
+func (sh *strictHandler) ExportAuditEvents(w http.ResponseWriter, r *http.Request, params ExportAuditEventsParams) {
+	var request ExportAuditEventsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ExportAuditEvents(ctx, request.(ExportAuditEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExportAuditEvents")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExportAuditEventsResponseObject); ok {
+		if err := validResponse.VisitExportAuditEventsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// VerifyAuditLog operation middleware
+func (sh *strictHandler) VerifyAuditLog(w http.ResponseWriter, r *http.Request) {
+	var request VerifyAuditLogRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.VerifyAuditLog(ctx, request.(VerifyAuditLogRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "VerifyAuditLog")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(VerifyAuditLogResponseObject); ok {
+		if err := validResponse.VisitVerifyAuditLogResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// DeleteCatalogSchedule operation middleware
+func (sh *strictHandler) DeleteCatalogSchedule(w http.ResponseWriter, r *http.Request, catalog string) {
+	var request DeleteCatalogScheduleRequestObject
+
+	request.Catalog = catalog
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteCatalogSchedule(ctx, request.(DeleteCatalogScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteCatalogSchedule")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteCatalogScheduleResponseObject); ok {
+		if err := validResponse.VisitDeleteCatalogScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// GetCatalogSchedule operation middleware
+func (sh *strictHandler) GetCatalogSchedule(w http.ResponseWriter, r *http.Request, catalog string) {
+	var request GetCatalogScheduleRequestObject
+
+	request.Catalog = catalog
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetCatalogSchedule(ctx, request.(GetCatalogScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetCatalogSchedule")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetCatalogScheduleResponseObject); ok {
+		if err := validResponse.VisitGetCatalogScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// SetCatalogSchedule operation middleware
+func (sh *strictHandler) SetCatalogSchedule(w http.ResponseWriter, r *http.Request, catalog string) {
+	var request SetCatalogScheduleRequestObject
+
+	request.Catalog = catalog
+
+	var body SetCatalogScheduleJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SetCatalogSchedule(ctx, request.(SetCatalogScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SetCatalogSchedule")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SetCatalogScheduleResponseObject); ok {
+		if err := validResponse.VisitSetCatalogScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Health operation middleware
+func (sh *strictHandler) Health(w http.ResponseWriter, r *http.Request) {
+	var request HealthRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Health(ctx, request.(HealthRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Health")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(HealthResponseObject); ok {
+		if err := validResponse.VisitHealthResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// GetOperation operation middleware
+func (sh *strictHandler) GetOperation(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetOperationRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetOperation(ctx, request.(GetOperationRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetOperation")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetOperationResponseObject); ok {
+		if err := validResponse.VisitGetOperationResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// CancelOperation operation middleware
+func (sh *strictHandler) CancelOperation(w http.ResponseWriter, r *http.Request, id string, params CancelOperationParams) {
+	var request CancelOperationRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CancelOperation(ctx, request.(CancelOperationRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CancelOperation")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CancelOperationResponseObject); ok {
+		if err := validResponse.VisitCancelOperationResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// ListRevocations operation middleware
+func (sh *strictHandler) ListRevocations(w http.ResponseWriter, r *http.Request) {
+	var request ListRevocationsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListRevocations(ctx, request.(ListRevocationsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListRevocations")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListRevocationsResponseObject); ok {
+		if err := validResponse.VisitListRevocationsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// CreateRevocation operation middleware
+func (sh *strictHandler) CreateRevocation(w http.ResponseWriter, r *http.Request, params CreateRevocationParams) {
+	var request CreateRevocationRequestObject
+
+	request.Params = params
+
+	var body CreateRevocationJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateRevocation(ctx, request.(CreateRevocationRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateRevocation")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateRevocationResponseObject); ok {
+		if err := validResponse.VisitCreateRevocationResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// ListSandboxes operation middleware
+func (sh *strictHandler) ListSandboxes(w http.ResponseWriter, r *http.Request, params ListSandboxesParams) {
+	var request ListSandboxesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListSandboxes(ctx, request.(ListSandboxesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListSandboxes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListSandboxesResponseObject); ok {
+		if err := validResponse.VisitListSandboxesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// CreateSandbox operation middleware
+func (sh *strictHandler) CreateSandbox(w http.ResponseWriter, r *http.Request, params CreateSandboxParams) {
+	var request CreateSandboxRequestObject
+
+	request.Params = params
+
+	var body CreateSandboxJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateSandbox(ctx, request.(CreateSandboxRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateSandbox")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateSandboxResponseObject); ok {
+		if err := validResponse.VisitCreateSandboxResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// GetSandboxByName operation middleware
+func (sh *strictHandler) GetSandboxByName(w http.ResponseWriter, r *http.Request, name string, params GetSandboxByNameParams) {
+	var request GetSandboxByNameRequestObject
+
+	request.Name = name
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetSandboxByName(ctx, request.(GetSandboxByNameRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetSandboxByName")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetSandboxByNameResponseObject); ok {
+		if err := validResponse.VisitGetSandboxByNameResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// DeleteSandbox operation middleware
+func (sh *strictHandler) DeleteSandbox(w http.ResponseWriter, r *http.Request, id string, params DeleteSandboxParams) {
+	var request DeleteSandboxRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteSandbox(ctx, request.(DeleteSandboxRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteSandbox")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteSandboxResponseObject); ok {
+		if err := validResponse.VisitDeleteSandboxResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// GetSandbox operation middleware
+func (sh *strictHandler) GetSandbox(w http.ResponseWriter, r *http.Request, id string, params GetSandboxParams) {
+	var request GetSandboxRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetSandbox(ctx, request.(GetSandboxRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetSandbox")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetSandboxResponseObject); ok {
+		if err := validResponse.VisitGetSandboxResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// UpdateSandbox operation middleware
+func (sh *strictHandler) UpdateSandbox(w http.ResponseWriter, r *http.Request, id string, params UpdateSandboxParams) {
+	var request UpdateSandboxRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	var body UpdateSandboxJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateSandbox(ctx, request.(UpdateSandboxRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateSandbox")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateSandboxResponseObject); ok {
+		if err := validResponse.VisitUpdateSandboxResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-func (response DeleteSandbox204Response) VisitDeleteSandboxResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
-}
+// DeleteSandboxSchedule operation middleware
+func (sh *strictHandler) DeleteSandboxSchedule(w http.ResponseWriter, r *http.Request, id string) {
+	var request DeleteSandboxScheduleRequestObject
 
-type DeleteSandboxdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+	request.Id = id
 
-func (response DeleteSandboxdefaultJSONResponse) VisitDeleteSandboxResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteSandboxSchedule(ctx, request.(DeleteSandboxScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteSandboxSchedule")
+	}
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type GetSandboxRequestObject struct {
-	Id string `json:"id"`
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteSandboxScheduleResponseObject); ok {
+		if err := validResponse.VisitDeleteSandboxScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type GetSandboxResponseObject interface {
-	VisitGetSandboxResponse(w http.ResponseWriter) error
-}
+// GetSandboxSchedule operation middleware
+func (sh *strictHandler) GetSandboxSchedule(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetSandboxScheduleRequestObject
 
-type GetSandbox200JSONResponse Sandbox
+	request.Id = id
 
-func (response GetSandbox200JSONResponse) VisitGetSandboxResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetSandboxSchedule(ctx, request.(GetSandboxScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetSandboxSchedule")
+	}
 
-	return json.NewEncoder(w).Encode(response)
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type GetSandboxdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetSandboxScheduleResponseObject); ok {
+		if err := validResponse.VisitGetSandboxScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-func (response GetSandboxdefaultJSONResponse) VisitGetSandboxResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+// SetSandboxSchedule operation middleware
+func (sh *strictHandler) SetSandboxSchedule(w http.ResponseWriter, r *http.Request, id string) {
+	var request SetSandboxScheduleRequestObject
 
-	return json.NewEncoder(w).Encode(response.Body)
-}
+	request.Id = id
 
-type UpdateSandboxRequestObject struct {
-	Id   string `json:"id"`
-	Body *UpdateSandboxJSONRequestBody
-}
+	var body SetSandboxScheduleJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-type UpdateSandboxResponseObject interface {
-	VisitUpdateSandboxResponse(w http.ResponseWriter) error
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SetSandboxSchedule(ctx, request.(SetSandboxScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SetSandboxSchedule")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SetSandboxScheduleResponseObject); ok {
+		if err := validResponse.VisitSetSandboxScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type UpdateSandbox200JSONResponse Sandbox
+// SkipSandboxSchedule operation middleware
+func (sh *strictHandler) SkipSandboxSchedule(w http.ResponseWriter, r *http.Request, id string) {
+	var request SkipSandboxScheduleRequestObject
 
-func (response UpdateSandbox200JSONResponse) VisitUpdateSandboxResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	request.Id = id
 
-	return json.NewEncoder(w).Encode(response)
-}
+	var body SkipSandboxScheduleJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-type UpdateSandboxdefaultJSONResponse struct {
-	Body       Error
-	StatusCode int
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SkipSandboxSchedule(ctx, request.(SkipSandboxScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SkipSandboxSchedule")
+	}
 
-func (response UpdateSandboxdefaultJSONResponse) VisitUpdateSandboxResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(response.StatusCode)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response.Body)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SkipSandboxScheduleResponseObject); ok {
+		if err := validResponse.VisitSkipSandboxScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-// StrictServerInterface represents all server handlers.
-type StrictServerInterface interface {
-	// Health check
-	// (GET /health)
-	Health(ctx context.Context, request HealthRequestObject) (HealthResponseObject, error)
-	// List sandboxes
-	// (GET /sandboxes)
-	ListSandboxes(ctx context.Context, request ListSandboxesRequestObject) (ListSandboxesResponseObject, error)
-	// Create a sandbox
-	// (POST /sandboxes)
-	CreateSandbox(ctx context.Context, request CreateSandboxRequestObject) (CreateSandboxResponseObject, error)
-	// Get a sandbox by name
-	// (GET /sandboxes/name/{name})
-	GetSandboxByName(ctx context.Context, request GetSandboxByNameRequestObject) (GetSandboxByNameResponseObject, error)
-	// Delete a sandbox
-	// (DELETE /sandboxes/{id})
-	DeleteSandbox(ctx context.Context, request DeleteSandboxRequestObject) (DeleteSandboxResponseObject, error)
-	// Get a sandbox
-	// (GET /sandboxes/{id})
-	GetSandbox(ctx context.Context, request GetSandboxRequestObject) (GetSandboxResponseObject, error)
-	// Update a sandbox
-	// (PATCH /sandboxes/{id})
-	UpdateSandbox(ctx context.Context, request UpdateSandboxRequestObject) (UpdateSandboxResponseObject, error)
-}
+// ForceDeleteSandbox operation middleware
+func (sh *strictHandler) ForceDeleteSandbox(w http.ResponseWriter, r *http.Request, id string, params ForceDeleteSandboxParams) {
+	var request ForceDeleteSandboxRequestObject
 
-type StrictHandlerFunc = runtime.StrictHttpHandlerFunc
-type StrictMiddlewareFunc = runtime.StrictHttpMiddlewareFunc
+	request.Id = id
+	request.Params = params
 
-type StrictHTTPServerOptions struct {
-	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
-	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ForceDeleteSandbox(ctx, request.(ForceDeleteSandboxRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ForceDeleteSandbox")
+	}
 
-func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
-		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
-		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		},
-	}}
-}
+	response, err := handler(r.Context(), w, r, request)
 
-func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ForceDeleteSandboxResponseObject); ok {
+		if err := validResponse.VisitForceDeleteSandboxResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type strictHandler struct {
-	ssi         StrictServerInterface
-	middlewares []StrictMiddlewareFunc
-	options     StrictHTTPServerOptions
-}
+// StartSandbox operation middleware
+func (sh *strictHandler) StartSandbox(w http.ResponseWriter, r *http.Request, id string, params StartSandboxParams) {
+	var request StartSandboxRequestObject
 
-// Health operation middleware
-func (sh *strictHandler) Health(w http.ResponseWriter, r *http.Request) {
-	var request HealthRequestObject
+	request.Id = id
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.Health(ctx, request.(HealthRequestObject))
+		return sh.ssi.StartSandbox(ctx, request.(StartSandboxRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "Health")
+		handler = middleware(handler, "StartSandbox")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(HealthResponseObject); ok {
-		if err := validResponse.VisitHealthResponse(w); err != nil {
+	} else if validResponse, ok := response.(StartSandboxResponseObject); ok {
+		if err := validResponse.VisitStartSandboxResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -745,25 +4823,26 @@ func (sh *strictHandler) Health(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ListSandboxes operation middleware
-func (sh *strictHandler) ListSandboxes(w http.ResponseWriter, r *http.Request, params ListSandboxesParams) {
-	var request ListSandboxesRequestObject
+// StopSandbox operation middleware
+func (sh *strictHandler) StopSandbox(w http.ResponseWriter, r *http.Request, id string, params StopSandboxParams) {
+	var request StopSandboxRequestObject
 
+	request.Id = id
 	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.ListSandboxes(ctx, request.(ListSandboxesRequestObject))
+		return sh.ssi.StopSandbox(ctx, request.(StopSandboxRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "ListSandboxes")
+		handler = middleware(handler, "StopSandbox")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(ListSandboxesResponseObject); ok {
-		if err := validResponse.VisitListSandboxesResponse(w); err != nil {
+	} else if validResponse, ok := response.(StopSandboxResponseObject); ok {
+		if err := validResponse.VisitStopSandboxResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -771,11 +4850,13 @@ func (sh *strictHandler) ListSandboxes(w http.ResponseWriter, r *http.Request, p
 	}
 }
 
-// CreateSandbox operation middleware
-func (sh *strictHandler) CreateSandbox(w http.ResponseWriter, r *http.Request) {
-	var request CreateSandboxRequestObject
+// BatchSandboxes operation middleware
+func (sh *strictHandler) BatchSandboxes(w http.ResponseWriter, r *http.Request, params BatchSandboxesParams) {
+	var request BatchSandboxesRequestObject
 
-	var body CreateSandboxJSONRequestBody
+	request.Params = params
+
+	var body BatchSandboxesJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
 		return
@@ -783,18 +4864,18 @@ func (sh *strictHandler) CreateSandbox(w http.ResponseWriter, r *http.Request) {
 	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.CreateSandbox(ctx, request.(CreateSandboxRequestObject))
+		return sh.ssi.BatchSandboxes(ctx, request.(BatchSandboxesRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "CreateSandbox")
+		handler = middleware(handler, "BatchSandboxes")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(CreateSandboxResponseObject); ok {
-		if err := validResponse.VisitCreateSandboxResponse(w); err != nil {
+	} else if validResponse, ok := response.(BatchSandboxesResponseObject); ok {
+		if err := validResponse.VisitBatchSandboxesResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -802,25 +4883,25 @@ func (sh *strictHandler) CreateSandbox(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// GetSandboxByName operation middleware
-func (sh *strictHandler) GetSandboxByName(w http.ResponseWriter, r *http.Request, name string) {
-	var request GetSandboxByNameRequestObject
+// ReconcileSandboxes operation middleware
+func (sh *strictHandler) ReconcileSandboxes(w http.ResponseWriter, r *http.Request, params ReconcileSandboxesParams) {
+	var request ReconcileSandboxesRequestObject
 
-	request.Name = name
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetSandboxByName(ctx, request.(GetSandboxByNameRequestObject))
+		return sh.ssi.ReconcileSandboxes(ctx, request.(ReconcileSandboxesRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetSandboxByName")
+		handler = middleware(handler, "ReconcileSandboxes")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetSandboxByNameResponseObject); ok {
-		if err := validResponse.VisitGetSandboxByNameResponse(w); err != nil {
+	} else if validResponse, ok := response.(ReconcileSandboxesResponseObject); ok {
+		if err := validResponse.VisitReconcileSandboxesResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -828,25 +4909,25 @@ func (sh *strictHandler) GetSandboxByName(w http.ResponseWriter, r *http.Request
 	}
 }
 
-// DeleteSandbox operation middleware
-func (sh *strictHandler) DeleteSandbox(w http.ResponseWriter, r *http.Request, id string) {
-	var request DeleteSandboxRequestObject
+// ResolveSandbox operation middleware
+func (sh *strictHandler) ResolveSandbox(w http.ResponseWriter, r *http.Request, params ResolveSandboxParams) {
+	var request ResolveSandboxRequestObject
 
-	request.Id = id
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.DeleteSandbox(ctx, request.(DeleteSandboxRequestObject))
+		return sh.ssi.ResolveSandbox(ctx, request.(ResolveSandboxRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "DeleteSandbox")
+		handler = middleware(handler, "ResolveSandbox")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(DeleteSandboxResponseObject); ok {
-		if err := validResponse.VisitDeleteSandboxResponse(w); err != nil {
+	} else if validResponse, ok := response.(ResolveSandboxResponseObject); ok {
+		if err := validResponse.VisitResolveSandboxResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -854,25 +4935,23 @@ func (sh *strictHandler) DeleteSandbox(w http.ResponseWriter, r *http.Request, i
 	}
 }
 
-// GetSandbox operation middleware
-func (sh *strictHandler) GetSandbox(w http.ResponseWriter, r *http.Request, id string) {
-	var request GetSandboxRequestObject
-
-	request.Id = id
+// ListApiTokens operation middleware
+func (sh *strictHandler) ListApiTokens(w http.ResponseWriter, r *http.Request) {
+	var request ListApiTokensRequestObject
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetSandbox(ctx, request.(GetSandboxRequestObject))
+		return sh.ssi.ListApiTokens(ctx, request.(ListApiTokensRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetSandbox")
+		handler = middleware(handler, "ListApiTokens")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetSandboxResponseObject); ok {
-		if err := validResponse.VisitGetSandboxResponse(w); err != nil {
+	} else if validResponse, ok := response.(ListApiTokensResponseObject); ok {
+		if err := validResponse.VisitListApiTokensResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -880,13 +4959,13 @@ func (sh *strictHandler) GetSandbox(w http.ResponseWriter, r *http.Request, id s
 	}
 }
 
-// UpdateSandbox operation middleware
-func (sh *strictHandler) UpdateSandbox(w http.ResponseWriter, r *http.Request, id string) {
-	var request UpdateSandboxRequestObject
+// CreateApiToken operation middleware
+func (sh *strictHandler) CreateApiToken(w http.ResponseWriter, r *http.Request, params CreateApiTokenParams) {
+	var request CreateApiTokenRequestObject
 
-	request.Id = id
+	request.Params = params
 
-	var body UpdateSandboxJSONRequestBody
+	var body CreateApiTokenJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
 		return
@@ -894,18 +4973,44 @@ func (sh *strictHandler) UpdateSandbox(w http.ResponseWriter, r *http.Request, i
 	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.UpdateSandbox(ctx, request.(UpdateSandboxRequestObject))
+		return sh.ssi.CreateApiToken(ctx, request.(CreateApiTokenRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "UpdateSandbox")
+		handler = middleware(handler, "CreateApiToken")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(UpdateSandboxResponseObject); ok {
-		if err := validResponse.VisitUpdateSandboxResponse(w); err != nil {
+	} else if validResponse, ok := response.(CreateApiTokenResponseObject); ok {
+		if err := validResponse.VisitCreateApiTokenResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// RevokeApiToken operation middleware
+func (sh *strictHandler) RevokeApiToken(w http.ResponseWriter, r *http.Request, id string) {
+	var request RevokeApiTokenRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RevokeApiToken(ctx, request.(RevokeApiTokenRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RevokeApiToken")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RevokeApiTokenResponseObject); ok {
+		if err := validResponse.VisitRevokeApiTokenResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -916,24 +5021,139 @@ func (sh *strictHandler) UpdateSandbox(w http.ResponseWriter, r *http.Request, i
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xXTXPbNhP+K5h93yNrKnEPGd7sSHFVeySNZE864/EBIlciEpJggGViVcP/3gFAiZRI",
-	"yXLi1u20F4nELvfz2QfAGkKZ5jLDjDQEa9BhjCm3jwOlpDIPuZI5KhJol0MZofmPUIdK5CRkBoFTZlbm",
-	"wUKqlBMEIDI6fwse0CpH94pLVFB6kKLWfHnQ0Ea8/VSTEtkSytIDhV8KoTCC4B4qhxv1h62+nH/CkIyn",
-	"Gc+iuXzsSEQhJ4wuyLxsQ4444U8k0g7fHuBjLhTq53wiIqPbWs54ip0CTZwKGx9mRWpynN6NRsPRFXgw",
-	"ux1PJoM+eDD4bTKc2qfJYNR30g8Xwxu71B/cDG7t093oejT+OGrUpXZU5NHz0t8rvYigSsNrlLJpt1mv",
-	"bWJHWvTeWmk36juqfqC8eylU0df2jwR3Z9N6geD2YnjC+RYNu14b43MCgsbXBjLT6XjagYSy5dbYwLBQ",
-	"glYzQwfO5SVyheqioNi8ze3bh02+v368NS222hBU0jr3mCiH0hgW2UK2h/42FpoJzTirqs0ufi8UsovJ",
-	"kGlUX1GdGWOCEmOtpQMefEWlna03Z72znqmDzDHjuYAAzs96Z+fgQc4ptrn4MfLEJbJEasfzixWzMMbw",
-	"M1hLihvRMNoKwbRR5zLTrjxvez3HjhlhZk3yPE9EaL/zP2ljd0Ov5un/ChcQwP/8mn/9inz9quu2YLuB",
-	"ja9de4o05Wq1H6kR+dpVx0XVmd2N0MRqtf38jHjWkOZc8RQJlYbgvt05ZFmRzlExuWCCMNWMJFNIhcrA",
-	"9BsC+FKgWm3YIoBEpIKgOQakCvQaxdnfL0rvRL/6s8jZHBdSIdPEFYlsadZDmSQYEqMYmUJdJMQ00oH4",
-	"5GLhhM8I8OEH4WAzeBIX1VZWjyxXiq8OAcWsLXiR0IsB0x0JOtwVGT7mGBJGDCudJkr3EFd6kEvdgUy3",
-	"AzC+0W1h0ynMtlLTIdR0KaPVy03fzm7UkeyGfwyunNI+VMoWGt68dHhdgbmQzb4cI4/swK7hRjo3HTxQ",
-	"ScwEmcHI8JsZDlmoEKED7dsdo3x9aFX7k2Wk5s50DxV0gm/wYKayBmELXLt06Zvp99fmtzxInVdItQE2",
-	"X7HqCLGL0ivcEOjlauQUjpLoBlCVMctJZq+qKamSPElI2xb9x0fH+Ki7jXt4WIuodAhIkDpuKn27foSs",
-	"nEJNVidhYNjvRoA9bv9I/39uZzCS7H3Vhn/kRLc6UHonjO2RcX31JvX+im3ibzyL9mjAKYzbPXQXsCNt",
-	"dAqv2Mk/7ShS3T2PH0XcvfuEo8i/B2PfQSktlJXHLbnP7RXVwaxQSXXhDXw/kSFPYqkpeNd714Pyofwj",
-	"AAD//0Y96B5wEwAA",
+	"H4sIAAAAAAAC/+x9a3PcNpboX8HyTtXszlJS24kzsarmgyLJE00cWSXJ46k78s1C5OluRGyAA4CSe1z6",
+	"77dw8OALZLNt2Ym1/pLITRI4AM77hfdJJlal4MC1SvbfJ0ugOUj88/iSLsz/c1CZZKVmgif7yd9BKiY4",
+	"EXOil0AU5fm1eJcSLcg1kEpBThjHRyfznZ+pzpaE8tz841RwcL/4WdJEZUtYUTONXpeQ7CdKS8YXyf19",
+	"mrxk/KYPgPnVzGam4PBOk5IuICV3TC+JhOIvV4n59SrZJT8zpRhfEGHhKaiyL+9umPcctFwfzDXI/uwX",
+	"kAmeKwPAHWWaXMNcSCDSfGLmMhNJ+FcFSsdmYVzDAiROcyk0LQ5FxXV/mtNqdQ3SbDItChx0ZTbOzMA0",
+	"rFRK2NzPA7ldPONZUeWAw47PfZ8mJZV0Bdod9UGVM32Q2cnfJ8yA8K8K5DpJE05X5mtqn47vnB9HyJFh",
+	"hJw0Snz/T3KPeXALXJtzyATXjFdgD5kpbXaJ4udpHAb3rIZhLuSKartH332bpMmKcbaqVsn+LI0dHUL4",
+	"QopVH8BjA5Yid0uWLYnIskpKyAnVCJ1mKyBCkoIOQzc3w0aBy6mGHTNEkg7t2qtKZ2IFQ7sv3OPm8MDN",
+	"Ov+ZqCrLQKkkTeaUFZWE5O3gNOcW807yoYlkeGHCUV9SuYCRwbR/PnmsS3w4Nhq+MWU8MfWIHSfwxzxw",
+	"ulp8yNkeVlKJCDW8Kum/DOKLG+CDpGAQKiWamnfM3/gQuahlw56gSgm3TFQKeeQA+JkFZHzjTnJYlUID",
+	"z9Y/wboP9WvODNQ3sPZTO3TZJecRPmq5mxU2K/uZBF1JrvBHIdmCcVoQCaoUXAFhXGmgyCgklEC1H3BV",
+	"aWpg2L3ifn12C+oFNmDfMcA3V7qi714CX+hlsv/02bPYOZ3MUb5FUOaSLvois7FGQ3PKMPXGG+SOKrIS",
+	"OZszyIliPINBsJ2s3XQycyOEt4GRC22ljnKbbuR7D0r+Rz0d0FoP2ARtU6D1wD1HcKwasEFaeo3kHzs4",
+	"1g4KXRJAiyH6oDDNYU6rQif7c1ooCEhwLUQBlDu9ZcUiMv2yBakFTAu3rQNgFDhUdP4ns1lqUNLKqSez",
+	"2awhtp6kUalvh7Eiv2SXhm304TwDqYQhKIoCwXKXlDBNaKaV16bc6YOyi1FE3HGzMl4Y8EspSpCaAc6V",
+	"SaAa8gM9leWlCbwrmQS1zScsj6BRmhi177Xys3eOBOXxvFYPKxX+7ZYtIRMytyJ8JZQmgmdAKFkxXmkD",
+	"xzTg7HlGwJNwK2622xuVidJuLCJRdFj3A5WSrhEpDaNhEnIj6lnuMSwMljYOqbn7tQ4grn+FTJuxPeoc",
+	"4hdm+vZxt86uw2PMI+TBnW32u0vJGqgk1BDn1pvbYNBPHDmEf4/uY0fHx987AJo/M1oUIMmSIuEu6S0K",
+	"2hXRQiRpfRhBo7Ikso/y0v19F9GqENAT+/WTDUfXPbVtTgrpgxbFq3my/8/3yR8kzJP95P/s1RbgnmMQ",
+	"e/7D5D7tnq6Oc41Lv1WGGUAxR4bBFFFLcceRLSDlRNWb5gLt8P3FvMXllFLcxoWBFaNGB8K1tiQUvMsA",
+	"cq8FlKJgWYRJidUKrC3WO58cMpZvR6Tukx/W0QFHSAR5EsIJHGH2KoL7hlCdtlbHFMmhAA25+Zkn6cfx",
+	"SwlUCR4hi/OqqKnCbmJkl1WTEjawpTQJJuw2Wxs+GthcB9BJPvb0dIghK011hdCPE4hFxQv7dpTD1nC0",
+	"Zw1z1JvdXlR7XzYTuQXlCDKmnAU/iNotFvn02xg1Dk5wEXbGc7iz49Ojk9O/JmlycHZ2/urvx0dJmhwd",
+	"n57gH8f/ODs5x78OD04Pj18eH0WZH9pZaFNF5AXXMlgJ1LxICrHYJYbbZEvKF6DIUhS5ZTA6/JqTOYMi",
+	"V6k3y4wPCu1+OxI6M+wwS6qWJBO3IK09YT9sexnM19q/2zWX8BVrUrS3nQaHSntRb5ZUo26fCw4pgd3F",
+	"rieiXce9hAy/WFxJCa30cleCORSzPCGb9oMi4cn12m2WYQaaZShtEbrezlPvqenIvwqP3i/USr0UYVor",
+	"DaswuT+BFc3Bz6tA3rLMLGtV6nUfTmPMicoI+ltasNzKjAHwvAuI5jkzsNHirLHBWlYQwVZ74Ft/Zo62",
+	"vxU/wjsCPBNGAbz48WDn6bPvWkiAZz/MXHt+pa5SnibefxATBa8vD53LqD3bNDYpal/QNj4eg8Vw++MH",
+	"bUebJkZ490neH/wfO06K75wcdRwDqbc5LTka2sko+pqvjVoRXb6lm0ORQ2Qdl5dnxL5Qz2S9B6NT0YZv",
+	"t3+UuuHH6ku+lmOqDc5PjAe3pn2vzRUM6VGvkW1SoFD0NLAqDT7X4MJtucAa3rWm1652FQZ0cEQSlUGG",
+	"Nf8dJJs7hhOxCm59jGHI1R12XZFsCdkNtCyAYRKaM6n0CUd+EkOsF+a5O08udO0ccDyM8ZSsXKyAzetf",
+	"jWbFuKaZngYHAtA4/KZPoHlC9r3U70hsP38wIBqToL+PtVTxVG1lBuoJGrgZ2eqDUeKeZpwFDm/FkZF/",
+	"dnAnOtWWWmZHwDi0PjkKE/nRee6U2cZEEZP+GoqNKtpL+1bDPhx7+6KhoJnzEsXUT87Nq5bhSB3b14ts",
+	"CXlVBDbuN9WT47SN1LAqC6qnQnXpX+9in5t2EOscE46gB9NLkMG1XDux3BEqKMBwmtpf21Sz7FPIG44j",
+	"ioEzJYpbE8JaAm95RNHjmAG7hXwb5cpGsQgty4KBUS8M4ct1b/4kDfQzhWxyuT6veMwB2J7+0DAuXIfd",
+	"G4PQEkohNblDtU9UhYkVON3Pa0O5sM7KpO9QHCXZU7gjUJOt997297pLZ9vhXjDowh9jGFgzr3v0UDrX",
+	"xrPZrG8AeqSZNOaFfzlqpjjstWK8zzfrE+zvsARVFXrLFZ7jRxudbW7eepIRysMBRzh+n5tLuXnvzqS4",
+	"LuxpMJ7Du4jLVyjWRCCzCd5p7ulRSP9LH73imm0JMugCY/C9Ci9uNt6DCdpZwMuD09PjWpzkck1kxVNy",
+	"cHh4fHZ5fGTdtuZRAAu9U4ZnQ95gBm4kY9K6T5M0eXFwErdeu7oXbm9D03LwDh75RQP9O0IDn6iOrz1E",
+	"we7omlDVjvOxQoNU6EstgKKrGtyvZFUpbdiOAh1hpihQm7BEXaxnEubsXfQxOv83nFjPP3r++vTUeg8u",
+	"Ll+dnXV8BrVvwe1+mlwc/nh89Ppl8/Ev1vFw8BIdDy+PLxsD+j8PzvHXGFvvUW7vmA6ppoVYeBk+It3V",
+	"ksra/m7FR6wZjQOlXh756JmVAFfcxVCI8uMtQCtCSSbKtZW1tWDFvBdZcVUHdNFOZPqK/yoYV80ZY9LT",
+	"PRo6MakPZUy4ml+NvJGgWklA5gvlTf52eNDJefTPKC3KEqktMqko43NeaMpzKnOSDU0uSuXspRl58pz8",
+	"ifyJPNl5FpvFbNK/BY+c4snB6QHxj52k9DPZFcAtLSqqMb9poxHm97exsObGNiCJsYYXxgV17Fl7++TQ",
+	"PRVRBGjtJgi5PYZn50ITBeYnA3pJdTDWr0W+tt6u2FatQCm6gAHvFVPkTgq+qFFyYKDOvvi3/Oix1b8M",
+	"2n3cgdMJxH8Xdb637D8JsGMUHZM7sGeO0UT7zCQpUWueWTXRGt+LpkNAVDIDspCiKhPUZJpgPJtFYH/V",
+	"lHkfHwSdIt3DlBZhhh37N4znTfZ7eH58cHns+eax45rIgy+PTw2PfX12dOAeHJxfBu4cdxqBzIDrQ7Eq",
+	"UYnui2gpFhJU2N8gh1PLxGbmEJ7MZh1b+5unUeViqprgF3v66vIXXAUKj4bkeX14eHx81JTyG9zVSkMZ",
+	"41JQ9tULdMJwXawJvIOsMmI6ds5VmW+HGpuiDXjUjTBD93DcKtpB3xqKt2OYPcCXsqijDV8m+GwbHmM/",
+	"8483Mls7/BhP8SrwwEw5aMy/MT55mDNuxfj5i0Py5+9nf07SSWu90PS6ALKixrkERALN8QcY3QM7ddQZ",
+	"U1BuMUmVkBnPmmVTTPmkM6PSBs+rXeAQF4nozM5j1gl41A7OSZZQQ1RFwnuMK015Ftms1+cnRMIcWouQ",
+	"PoprRIzzum5Y3JBB0PTwuq2fwFU001EVbymkJqparWgdjXJAEe3cqHHVcnzdLAeu2TxkvI2P2Y2Uu5cQ",
+	"5gax43JjRHAOmeAZK+DzGMnN6R7MUO4O2lvBthbxh1mp0oORXwwgoP29G6hfiVvUNloOZ5soUcBcGwaE",
+	"/4qiuh1mw4TtBL5mVqqHmTmp23F5N6BcREMqneOqNy5+TreijgR0kzUw5cln1kDtVzTwFD6P5HqNeWW/",
+	"apZ6FyMLYZKDsxP/vZDOpLKf+Q1wQUymVFVn54Zsq4hNtMkz3shWbcHvf8qC2M8l2jk24HxHZa4+Ninj",
+	"V81GkjUeKrHMfTKUVmF3NJ6r2VNH6rGaoIyjSp1QFvU8/6qZOWx/si4Nyzo1Pvw0Ldb4M20inbMCSVZQ",
+	"tvKv/+3NpXVo2+/MaQNnLiLvstfYHONMClxawLTdd2fcT6vCdTeB1cLBO0YYSdq2k56lY+izKS+kdfx9",
+	"EP2ZBMhaJOlosOKaFYSLOyLmm6GLOWNcbCOmfSp9CFwPOKE+wPZqrfH9p0xQZXkBb6jkIymqaFhgfq2J",
+	"R9/hy12mbYbZJa95gTm77WeoSQlJbqDUmJjnoK+9omZMIwxKkEzk5htxC3Ib9L0BKN8sgZ/kBcSRxK5A",
+	"lNoAU4X8EqASDaQmUZrFNCI1fWXkQ0OAPbC50LEc0FPzs4cGAY+tObg9RxNoHNF2EvsdTqIHJTb2Q0Yg",
+	"AxJ552LexA6T3YWZY5jlPPnA+0b21g5d63n4eNdumrw+/en01ZvTuJP3QyOnD2SZu5zduK3d5CSj8QIH",
+	"3aSk62kH+IWF0c88mrZICSvrmHL+70blD1ssNaF3dI0Mz8c/nB4YdssFp2tiCIjoxJVeAt+GDT5UmN4h",
+	"zXjq50U7l3XYJRzqeZgO8sCFjw7+Xcmut5OY2TF27UJ+GE73Cq41WhlI8vr8ZJe8BG3jTjlbMK1SUvEc",
+	"pMqEBJWS5bpcAreBcCtczBul0RSez0yejaSZ/RyPyOhNwF1FK3Vf2P1v6AvPe8n9JTVAmGX/v38e7Pxf",
+	"uvPv2c7z3V923r6fpd8/v69/+2Xn7R9i5+a28szXRsV9z9GY/98uXp2SFcgFGP96tiT/iZ6jb55/91+d",
+	"/a9rkp3ThUoIhp9ewtr8kBJeFQXJCqDNlNRdYikRv8HZ8tQVruDPClD9wm8pnigam0YluOEmsNSY0ieL",
+	"RkNDLVWqsenffZsmZnTj1PJplIPb/qc/TFCqekpnd/iH0LrG9ZKfAErVUzkc7deKkjsTCQo0muhaEMSF",
+	"ZBDoqLIyNaKxcSMiwYiBb2puEVSdxmzfzp5vnq6niqfJu52F2HE/OtL52eCkpZ+ans5FbNctz7muWKF3",
+	"GCdGShCqFFvwOhbTZDkdp4bAIpMOz0rJoeBasutKC2nsOJeoYzl8KdktK8BkhpvZlK3e9+kC1GW4W3oI",
+	"6owvEGwMnKTJK6cNvlYgyYGtkTvITemd0pKad94O85fLhnjo6GjuSSv41PPnGOrNoSzE2picUqzs8gpq",
+	"uI+XPaOr2xg8Gwl1a1G2bQvD1W30F+sDFyZr0gnoTrhWWd4H+RXHwLVZiGH2Ga2MoDbnx+bkWvhSXy3K",
+	"kHaPM9ilVxBS/a84rtWmOTBl4uEtaQ51srleApPt6DrjS5BM24FUO0OPyTpab+a3IcN2HN+yXftccFBX",
+	"vDGZfYgQOwHqkHFiTL4TdXczNhV4m5uKS3CI0HfoNV5VUKNDz6xqGEPwTl/U+tfE6jv8SJTbfLMxfPeA",
+	"GQhozP7vSj9oVR5tSEBoHWBUyXR4dHHDyrHktGgepujgohZE3bCylcVi3UGhRkGL0sO6OeNqJJvVw/0a",
+	"ba0+5C0s65aOjmLIlnWm7WN32aOmuCNJu/rVhmP1MESXG8zx9jIbQdYJJvyrn4zNfn7+6jy+951pzRiQ",
+	"VZLptdnulZ3yB6AS5EGlUZO+xn+98Hzhb28ukzTmQrRVt1T66ke5Yg7/m36UUBSxLzHEaiyL9RUPBo5/",
+	"euccLN0MOmqzX0Mdu+Bga9eN7/eKNwue/FDUCPfwEeVrweGPqj1mgGDfSlsgtgBUtSRwKIPa7QzOVmXB",
+	"jBAJ4KdWtobVuDfq1e9e8SveckQr4NrLG7P5QrJ/W/3JNfroh7e/+/OzWXrFrcJkvruW4k6BDC0TlON2",
+	"mRA3DAJnBXnrCqEFhyueCT5ni0oac8OAVHFF542ir0YxWp0h54a0ZdTiitteHX2Be3hx/iLM7xs5mB93",
+	"sCDGLQ4nvuKmrgny5oQuJkO5ugPpeyWZQd68ebNzUL+HRR9FAXwB6RVnNjj+i91cwcm3syfOIFbVfM4y",
+	"Blz/gjhbj+hp1eHyFcfvvrHCHg1/NAyQHmoevtS6tO0ZGJ+LmOvUyHpFKPF1E1Z9Np5+exC7IQy8n/Te",
+	"SdLk1vbsSvaTJ7uz3ZkLe3JasmQ/+WZ3tvtNgqbcEql3z2Mr/msBOtaDS+l2WV+08BpUtPQ6JaLIsekJ",
+	"k0onjRjsSe4GPwggtFtVDZSt16/s2eYb92kX5IHuGyiMnFMIBQKCKkgmigIyv0gTanZRpmhbpfncPoy0",
+	"6ZhtaiY10NckFLSGLfYU6etBi8JRyKoRcBqAMCQH1BBuVd38FqPymDSAWPF0NkvQV8C1q9ttmGt7v7qg",
+	"Uj3ZpIQBP2skU6CX0/fSVaD0OKt1M7jNH4TQJVr8dx/SSWkDfXgqDu9KmxVvMxCaohHxtikU694QFn5I",
+	"3potdhkmfnmRtd2nDfLce8/y+0Ea/StoQnlvkB65/RUCtfWJraPT+bFOjjyeGa5RoxnzVYRWY7HOhOEW",
+	"Px+LVdOQqX9Yr356HFgydMQRNAlDGP1QxMq77IZBu+y2Y0Y1I0W1gx3LUzW6ZKzW3kUxN/Qwmm3g6Z2G",
+	"Yn2e+UkQE7fgB5GvHxwnQ6eG+/v7+9+IBo5sf5IaZx4DQXgcnkoUOfD1MEUcAV+PkoNvu4J6ofYh3UYK",
+	"MHZ165GDGfcrLXylhU9LC4i9w4RgCvY36/euHL/bgKVdQh8Kz5pqvfNLx5r1hjaVqtGSBduvNVqpRGyC",
+	"0Cdme6ug0Zj2Pp38tk32nPZ6o/Hodp+c5JM/qNuuTv3C94Od+j72tJ0Mv5i+meacp7ztzLfPY28EhNrK",
+	"4kAiAI+HjYbZvl91bE732h6+c3//hXEY46OKmyjNzagZyx68K4Uc5i/H+Dh069yGyxgnFgbcXzIOhudw",
+	"IPVBkhKkYToQY0YWKNtYQFUryHvsB18wu2N7v4Q+BvhawNIYg7Ir+sqivngWtR3nebfD8z55dlWoHiEe",
+	"NMimjdDJI+AMnroHeMMtSDZfD/KGuoOG7cyGnXgca7hbYqZA3QfuIPR7EzKo45Z6ryXQG9Xo5uNKpamD",
+	"iQgeI2RsYrTGA3qJBbyfTiPt9UyKHIwtcwmxZ7M3jwFF7C632b3FERcFV3vv3V/3e6qVkBCvLj3C39ux",
+	"xk7AnhatguVMlAyU9VoEd3UIqUeMNjNBtxvABofZ4bQsgk6SB6i44VYXlQ9bb93sjtHQZITZfTvS4MDR",
+	"12PAvwF0of5czBoHvaoWa7XA2P+eTU5R23WBiHlhHz1uPRzr7G5VBF0u4lzgC0NeGXP5jmJtWelYxV1Z",
+	"0GyUPaaj+HrFR1KpFqDHupNwcReRsxePE+Ef3lHWSWO5v7/vgnz/ldI+kZi4GKc2o68sgRY2zyUqL37E",
+	"x05x69KAffgpdcwQwI3GwO6ba21BiksLwE6INNbNVCkxetaOrDgWlIVBYiLvVePhKO2HF7+E0GO9qscQ",
+	"e5QDUUfRWGUfWfYzyjMohoMrvmO/fa8Ied6T0ecQPxzBoI8NpHzFud8M5+66OGcPO4J2deH9hGwlE+7b",
+	"8b1LQ0ahmpCPdN6Y5nO4puv5tnFNtxoTPApXEq6sv6whlmLeqw+220Fic5eIUKHuErrdWwVQM38mAUvf",
+	"aKGsQznU2qvueFQ220QI+3p7HfiO6x1wvXbA+YY5qEwzHu4ZinmqbCVoA1M+lgN+IgW2199hkgr75BPM",
+	"H3ew4aE8BmLx+O/IxLDH2ggaZY5NW6nP/C4aT/+XJ2M2PYUmE7xRf6IrBWoApkj65cd3Q32o4vkpfVGn",
+	"70unZUL0hNyzkbv/ps9Xex3oqoE14efSNq2NQ9LoavtA4LRClj9V1yA5aFBE6XXhy8J8z2snZP5HA139",
+	"paTrlSGfFPjtf/yllCJPNQPsufyfd3Cd0pL9V/ofBSxotv6fq8H7AluNfDesKTaAb1IwfEXuaPOD0UF/",
+	"QMp+sFF9LfCDguoG/QhQp2IKzuRR1VcJVj5xzDnOHIo8+e775cCJ+2He4CjboTE270PGK6TugHe9HpjQ",
+	"vBvnru0rBFuXU4x3vcCR307YzQsDp5DD13b6ZzHozFANwCj+C3+MT71Btrk7eSe82brH9POktjiRPcV4",
+	"wIqvD8piSZPWhaqbPmrcd35//4X7QDpa06BJcuiu+qgbQFzGvex4sWS7EY+7aVQCwVtgfbsHX9YmbsNl",
+	"HCvmLY55pYIZo0To/7Fyj2m+HrYkLkJd8O/SjGj3v4n5oe0LdSlSstnKePp5HDCmS0Bpo8pNShvskOif",
+	"ePzgcBdaAoyz+NprtTN9+DphWUua3XTu3x6d0Ez57dPnn5OIL4UgK6N7h8qo/kV12r8zRGqGHq7BrDRC",
+	"dFe8fVB4K/nOgb+ObozJ4av2zfv7L93p1mFeHZNyz8jcvffmvxuqkOpupGvi+hr1QgGOen9YuysyR8MB",
+	"ntTNYKnrea81SKz0tC2E5kJmhmUq0VFsfN81Z2cye8cb41qKvMogNx5pojQrCnINpBDosqnKKx73Abv1",
+	"jAQRR4OG6RY29FeF5KtCMhqU6dFZh2B9FG9DEhVttAiJJUE9lKIwAVHnP7t+QsNXyH2i0Mxvqhn8FkL8",
+	"ydPPifhn2I3aNsMiL7Bo/0uPUfWoZyyHbJjGalk4VQo+EA1MIcdTwcGR5KeMZgZpsVk6HF/SxSZGj+/g",
+	"WN/EEhxPhSY/i5zNWZcOtxv80cgPtGd9I8TO/QXowmkVQq5A05xq6ns2dtsh7pJjjG21buZ21zsKmUNo",
+	"MukHXDKlhVy3uhtSCZPv6omZuBbuxyq5pljbeCI7eCL//UHU6Fr79ZHw0F0D3k68+71b39NpO/0qj79E",
+	"eexY1aD5arThbWsLhtO++w32Whm1pjNRUShyTbNQENvMKsTycdcbEH2EdXO/8BpTV9wz0bqMwX7UGIwX",
+	"USdfS3efmnP7ydXsx1dvcDe51mCKnjiKdK1RhvXI381hzx48KXpKPnJjmx9f5n9TY9uU+T8Jj9JOCj+V",
+	"QMyKK99n1IQlY9n8vwNke2zJ948fy++mZdxPkeD7yvcnjUbiLlpdRw0V4F1CSAjdJvnmHxb5sfDbRdfM",
+	"5Sl9xL9h5aPGfLPA3yXe98qN7OVqXzwBRLrjtigiRgT7GO04CvrrUD+jWhFxOOiy2HxPQUw2cV1RidJV",
+	"dkPulsyonlo1DBoTMCmEb6sQfo82K7f5/ZA3GzFYw7/ZQQlugRPWNuaNQsz/qMl1NPv1Rb3iB7TqvzqZ",
+	"P6lR+6Vn2CLSOaQdF0v7KFlGxJHGlva+J/mgFo/vfUXwrwj+GSTPAErGsVuM6Vp4ecMITovyK0p/RenP",
+	"gdItRGxj8v61D3GM5e6lBN5p4I0GM93EIpc0FC6yLZgykAsOJg5v/ieky/muG0O4blY+NsI0rMjC3i2E",
+	"7ehdnUNKKHEtzfGVXIAyOhHaL3i4ZkoVSSv0vbUw2R5VKXtTqgUD82LsXWDea1kWVJscZ4KSTw36EX8w",
+	"u/bhJSGfKVsQoTwPHQ8/q/ni5rajDzf1CeqyTaB5DAVIFW9ZA7xDKl0CDLdoDxOhCUV4e7xdEFzf7lNK",
+	"sZCgLCpTTpaikikaKLWRWBfWLVm2DDLOWjd+bL0Ee58Sk036luDu7XaXlroSm5T4wpqU+LoabAXrK2vw",
+	"Nu8OD1aOnHfJa7wD78ls1nxo5gq7EvIRY2QYLkIfIcWeK9Cn+ePeRHYSowtmJyhfm3KCxUCafbiqPZJn",
+	"767I696C9kndwf2b7aNUF/a1XvqjIDu/sNjZ9mlOicL2AR8NOrgvSFZJCVwXa7IURbjIweDBLjGpmhZn",
+	"K87+VQGhK8EXzc9BOYLz1305g9/nZUq8nYpqshLuIi9VZUv/dRzvEf4tU2Q+faKo29d8sDTrIxNFv8h0",
+	"my853uIQbSir0lXZbuw5UIJUgtOCUHtTYLte3Kesc7gLDQic/9kVixuBYmulcntXEbPJu/nAvSns0gL2",
+	"eS7ssLNt06OgrplPHkMNUHs5G4qAorhQpw3gJyrcenh4YtV6+1bzzqqhogajsqe1zuNu1GrfntW6MUpi",
+	"dSLkzoAx36MBgp03mSJKC7zTqV5l8MnaO4Q1llnQRbMXwnCFUcCX36nR4OH7bfoUtGfPo5lW/tGXTTme",
+	"IHiNNU2mujFPvdHoIwzQ5ajmb2fJWlYaLm6LqBTm+TBydhwzYcbPmRTzZbapkAMtKjonPzqYHQHvObPH",
+	"UcnC3Zq2v7dXiIwWS6H0/vez72fJ/dv7/z8AhnRxTNK4AAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
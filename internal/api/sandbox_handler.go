@@ -2,36 +2,309 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/makirill/sandbox-azure/internal/log"
 	"github.com/makirill/sandbox-azure/internal/models"
 )
 
 type SandboxHandler struct {
-	instances models.SandboxController
+	instances   models.SandboxController
+	tokens      models.TokenController
+	revocations models.RevocationController
+	audit       models.AuditController
 }
 
 // Helper to map the string status to the SandboxStatus enum
 func toSandboxStatus(status string) SandboxStatus {
 	var statusMap = map[string]SandboxStatus{
-		"deleted": DELETED,
-		"expired": EXPIRED,
-		"failed":  FAILED,
-		"pending": PENDING,
-		"running": RUNNING,
-		"stopped": STOPPED,
+		"deleted":          SandboxStatusDELETED,
+		"expired":          SandboxStatusEXPIRED,
+		"failed":           SandboxStatusFAILED,
+		"pending":          SandboxStatusPENDING,
+		"running":          SandboxStatusRUNNING,
+		"stopped":          SandboxStatusSTOPPED,
+		"scheduled":        SandboxStatusSCHEDULED,
+		"pending_approval": SandboxStatusPENDINGAPPROVAL,
+		"deleting":         SandboxStatusDELETING,
+		"stopping":         SandboxStatusSTOPPING,
+		"starting":         SandboxStatusSTARTING,
 	}
 
 	ret, ok := statusMap[strings.ToLower(status)]
 	if !ok {
-		ret = UNKNOWN
+		ret = SandboxStatusUNKNOWN
 	}
 
 	return ret
 }
 
+// Helper to map the sandbox details to the API representation
+func toSandbox(details models.SandboxDetails) Sandbox {
+	sandbox := Sandbox{
+		Name:      details.Name,
+		Id:        details.UUID,
+		Status:    toSandboxStatus(details.Status),
+		CreatedAt: details.CreatedAt,
+		ExpiresAt: details.ExpiresAt,
+		UpdatedAt: details.UpdatedAt,
+	}
+
+	if details.Owner != "" {
+		sandbox.Owner = String(details.Owner)
+	}
+
+	if len(details.Labels) > 0 {
+		labels := Labels(details.Labels)
+		sandbox.Labels = &labels
+	}
+
+	if details.Description != "" {
+		sandbox.Description = String(details.Description)
+	}
+
+	if details.CostCenter != "" {
+		sandbox.CostCenter = String(details.CostCenter)
+	}
+
+	if details.Notes != "" {
+		sandbox.Notes = String(details.Notes)
+	}
+
+	if details.KeepWhenIdle {
+		sandbox.KeepWhenIdle = &details.KeepWhenIdle
+	}
+
+	if details.Role != "" {
+		role := SandboxRole(details.Role)
+		sandbox.Role = &role
+	}
+
+	if details.Template != "" {
+		sandbox.Template = String(details.Template)
+	}
+
+	sandbox.StartAt = details.StartAt
+	sandbox.IdleWarnedAt = details.IdleWarnedAt
+
+	return sandbox
+}
+
+// Helper to convert the role and the template of the new sandbox
+func toSandboxOptions(role *SandboxRole, template *SandboxTemplate) models.SandboxOptions {
+	options := models.SandboxOptions{}
+
+	if role != nil {
+		options.Role = string(*role)
+	}
+	if template != nil {
+		options.Template = *template
+	}
+
+	return options
+}
+
+// Helper to get the principal of the request, the one without the token has
+// no subject and no permissions
+func principalFromContext(ctx context.Context) models.Principal {
+	principal, _ := PrincipalFromContext(ctx)
+
+	return principal
+}
+
+// Helper to map the list query parameters to the models filter
+func toSandboxFilter(params ListSandboxesParams) (models.SandboxFilter, error) {
+	filter := models.SandboxFilter{
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		ExpiresAfter:  params.ExpiresAfter,
+		ExpiresBefore: params.ExpiresBefore,
+	}
+
+	if params.Offset != nil {
+		filter.Offset = *params.Offset
+	}
+	if params.Status != nil {
+		for _, status := range *params.Status {
+			filter.Statuses = append(filter.Statuses, string(status))
+		}
+	}
+	if params.Owner != nil {
+		filter.Owner = *params.Owner
+	}
+	if params.NamePrefix != nil {
+		filter.NamePrefix = *params.NamePrefix
+	}
+	if params.LabelSelector != nil {
+		selector, err := models.ParseLabelSelector(*params.LabelSelector)
+		if err != nil {
+			return models.SandboxFilter{}, err
+		}
+		filter.Selector = selector
+	}
+	if params.ExpiringWithin != nil {
+		within, err := time.ParseDuration(*params.ExpiringWithin)
+		if err != nil {
+			return models.SandboxFilter{}, models.NewValidationError("expiringWithin must be a duration, e.g. 168h")
+		}
+		filter.ExpiringWithin = within
+	}
+	if params.Sort != nil {
+		filter.SortBy = string(*params.Sort)
+	}
+	if params.Order != nil {
+		filter.SortOrder = string(*params.Order)
+	}
+
+	err := setPage(&filter, params.Limit, params.Cursor, params.IncludeTotal)
+
+	return filter, err
+}
+
+// Helper to set the paging parameters shared by the listings
+func setPage(filter *models.SandboxFilter, limit *int, cursor *string, includeTotal *bool) error {
+	if limit != nil {
+		filter.Limit = *limit
+	}
+	if cursor != nil {
+		after, err := models.DecodeCursor(*cursor)
+		if err != nil {
+			return err
+		}
+		filter.After = &after
+	}
+	if includeTotal != nil {
+		filter.WithTotal = *includeTotal
+	}
+
+	return nil
+}
+
+// sandboxPageResponse writes the paging headers only when they apply, the
+// generated responses always write all the declared headers
+type sandboxPageResponse struct {
+	sandboxes []Sandbox
+	next      string
+	total     int
+}
+
+// Make sure we conform to the listing response interfaces
+var _ ListSandboxesResponseObject = sandboxPageResponse{}
+var _ GetSandboxByNameResponseObject = sandboxPageResponse{}
+
+// Helper to build the response out of the page, the link to the next page
+// keeps the query of the current request
+func toSandboxPageResponse(ctx context.Context, page models.SandboxPage) sandboxPageResponse {
+	response := sandboxPageResponse{
+		sandboxes: make([]Sandbox, 0, len(page.Sandboxes)),
+		total:     page.Total,
+	}
+
+	for _, details := range page.Sandboxes {
+		response.sandboxes = append(response.sandboxes, toSandbox(details))
+	}
+
+	if page.Next != nil {
+		u := requestURLFromContext(ctx)
+		query := u.Query()
+		query.Set("cursor", page.Next.Encode())
+		query.Del("offset")
+		response.next = u.Path + "?" + query.Encode()
+	}
+
+	return response
+}
+
+func (response sandboxPageResponse) write(w http.ResponseWriter) error {
+	if response.next != "" {
+		w.Header().Set("Link", "<"+response.next+`>; rel="next"`)
+	}
+	if response.total >= 0 {
+		w.Header().Set("X-Total-Count", strconv.Itoa(response.total))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	return json.NewEncoder(w).Encode(response.sandboxes)
+}
+
+func (response sandboxPageResponse) VisitListSandboxesResponse(w http.ResponseWriter) error {
+	return response.write(w)
+}
+
+func (response sandboxPageResponse) VisitGetSandboxByNameResponse(w http.ResponseWriter) error {
+	return response.write(w)
+}
+
+// Helper to map the operation details to the API representation
+func toOperation(details models.OperationDetails) Operation {
+	operation := Operation{
+		Id:              details.UUID,
+		SandboxId:       details.SandboxID,
+		Kind:            OperationKind(details.Kind),
+		Status:          OperationStatus(details.Status),
+		PercentComplete: int32(details.PercentComplete),
+		Step:            details.Step,
+		CreatedAt:       details.CreatedAt,
+		UpdatedAt:       details.UpdatedAt,
+	}
+
+	if details.ErrorCode != "" {
+		operation.Error = &OperationError{
+			Code:    details.ErrorCode,
+			Message: details.ErrorMessage,
+		}
+	}
+
+	return operation
+}
+
+func operationLocation(id string) string {
+	return "/operations/" + id
+}
+
+// Helper to build the ETag header value out of the sandbox version
+func toETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// Helper to get the expected sandbox version out of the If-Match header.
+// Returns 0 if any version matches and -1 for malformed values, which never match.
+func fromIfMatch(ifMatch *string) int {
+	if ifMatch == nil || strings.TrimSpace(*ifMatch) == "*" {
+		return 0
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimSpace(*ifMatch), `"`))
+	if err != nil || version < 1 {
+		return -1
+	}
+
+	return version
+}
+
+// Helper to check the If-None-Match header against the sandbox version
+func matchesIfNoneMatch(ifNoneMatch *string, version int) bool {
+	if ifNoneMatch == nil {
+		return false
+	}
+
+	etag := toETag(version)
+	for _, tag := range strings.Split(*ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Make sure we conform to the StrictServerInterface
 var _ StrictServerInterface = (*SandboxHandler)(nil)
 
@@ -39,10 +312,13 @@ func String(s string) *string {
 	return &s
 }
 
-func NewSandboxHandler(controller models.SandboxController) *SandboxHandler {
+func NewSandboxHandler(controller models.SandboxController, tokens models.TokenController, revocations models.RevocationController, audit models.AuditController) *SandboxHandler {
 
 	return &SandboxHandler{
-		instances: controller,
+		instances:   controller,
+		tokens:      tokens,
+		revocations: revocations,
+		audit:       audit,
 	}
 }
 
@@ -58,141 +334,184 @@ func (sh *SandboxHandler) Health(ctx context.Context, request HealthRequestObjec
 
 func (sh *SandboxHandler) ListSandboxes(ctx context.Context, request ListSandboxesRequestObject) (ListSandboxesResponseObject, error) {
 
-	detailsList, err := sh.instances.ListAll(request.Params.Limit, request.Params.Offset)
+	filter, err := toSandboxFilter(request.Params)
 	if err != nil {
-		return ListSandboxesdefaultJSONResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body: Error{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			}}, nil
+		problem := problemFromError(err)
+		return ListSandboxesdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
 	}
 
-	sandboxes := make([]Sandbox, 0, len(detailsList))
-	for _, details := range detailsList {
-		sandboxes = append(sandboxes, Sandbox{
-			Name:      details.Name,
-			Id:        details.UUID,
-			Status:    toSandboxStatus(details.Status),
-			CreatedAt: details.CreatedAt,
-			ExpiresAt: details.ExpiresAt,
-			UpdatedAt: details.UpdatedAt,
-		})
+	page, err := sh.instances.ListAll(filter)
+	if err != nil {
+		problem := problemFromError(err)
+		return ListSandboxesdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
 	}
 
-	return ListSandboxes200JSONResponse(sandboxes), nil
+	return toSandboxPageResponse(ctx, page), nil
 }
 
 func (sh *SandboxHandler) CreateSandbox(ctx context.Context, request CreateSandboxRequestObject) (CreateSandboxResponseObject, error) {
-	sandboxDetails, err := sh.instances.Create(request.Body.Name, request.Body.ExpiresAt)
+	var labels map[string]string
+	if request.Body.Labels != nil {
+		labels = *request.Body.Labels
+	}
+
+	sandboxDetails, operation, err := sh.instances.Create(request.Body.Name, request.Body.ExpiresAt, request.Body.StartAt,
+		principalFromContext(ctx), labels, toSandboxOptions(request.Body.Role, request.Body.Template))
+	if errors.Is(err, models.ErrPendingCreateLimit) {
+		return CreateSandbox429JSONResponse{
+			Body:    problemFromError(err),
+			Headers: CreateSandbox429ResponseHeaders{RetryAfter: int(models.PendingCreatesRetryAfter.Seconds())},
+		}, nil
+	}
 	if err != nil {
-		return CreateSandboxdefaultJSONResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body: Error{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			}}, nil
+		problem := problemFromError(err)
+		return CreateSandboxdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
 	}
 
 	log.Logger.Info("Sandbox created", "name", sandboxDetails.Name, "id", sandboxDetails.UUID)
 
-	return CreateSandbox201JSONResponse{
-		Body: Sandbox{
-			Name:      sandboxDetails.Name,
-			Id:        sandboxDetails.UUID,
-			Status:    toSandboxStatus(sandboxDetails.Status),
-			CreatedAt: sandboxDetails.CreatedAt,
-			ExpiresAt: sandboxDetails.ExpiresAt,
-			UpdatedAt: sandboxDetails.UpdatedAt,
-		},
-		Headers: CreateSandbox201ResponseHeaders{
-			Location: "/sandboxes/" + sandboxDetails.UUID,
+	return CreateSandbox202JSONResponse{
+		Body: toOperation(operation),
+		Headers: CreateSandbox202ResponseHeaders{
+			Location:          "/sandboxes/" + sandboxDetails.UUID,
+			OperationLocation: operationLocation(operation.UUID),
 		},
 	}, nil
 
 }
 
 func (sh *SandboxHandler) GetSandboxByName(ctx context.Context, request GetSandboxByNameRequestObject) (GetSandboxByNameResponseObject, error) {
-	detailsList, err := sh.instances.GetByName(request.Name)
+	filter := models.SandboxFilter{
+		Name: request.Name,
+	}
+
+	err := setPage(&filter, request.Params.Limit, request.Params.Cursor, request.Params.IncludeTotal)
 	if err != nil {
-		return GetSandboxByNamedefaultJSONResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body: Error{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			}}, nil
+		problem := problemFromError(err)
+		return GetSandboxByNamedefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
 	}
 
-	sandboxes := make([]Sandbox, 0, len(detailsList))
-	for _, details := range detailsList {
-		sandboxes = append(sandboxes, Sandbox{
-			Name:      details.Name,
-			Id:        details.UUID,
-			Status:    toSandboxStatus(details.Status),
-			CreatedAt: details.CreatedAt,
-			ExpiresAt: details.ExpiresAt,
-			UpdatedAt: details.UpdatedAt,
-		})
+	page, err := sh.instances.ListAll(filter)
+	if err != nil {
+		problem := problemFromError(err)
+		return GetSandboxByNamedefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
 	}
 
-	return GetSandboxByName200JSONResponse(sandboxes), nil
+	return toSandboxPageResponse(ctx, page), nil
 }
 
 func (sh *SandboxHandler) DeleteSandbox(ctx context.Context, request DeleteSandboxRequestObject) (DeleteSandboxResponseObject, error) {
-	sandboxDetails, err := sh.instances.Remove(request.Id)
+	operation, err := sh.instances.Remove(request.Id, principalFromContext(ctx), fromIfMatch(request.Params.IfMatch))
+	if err != nil {
+		problem := problemFromError(err)
+		return DeleteSandboxdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	log.Logger.Info("Sandbox deletion started", "id", request.Id, "operation", operation.UUID)
+
+	return DeleteSandbox202JSONResponse{
+		Body: toOperation(operation),
+		Headers: DeleteSandbox202ResponseHeaders{
+			OperationLocation: operationLocation(operation.UUID),
+		},
+	}, nil
+}
+
+func (sh *SandboxHandler) StopSandbox(ctx context.Context, request StopSandboxRequestObject) (StopSandboxResponseObject, error) {
+	operation, err := sh.instances.Stop(request.Id, principalFromContext(ctx))
 	if err != nil {
-		return DeleteSandboxdefaultJSONResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body: Error{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			}}, nil
+		problem := problemFromError(err)
+		return StopSandboxdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
 	}
 
-	log.Logger.Info("Sandbox deleted", "name", sandboxDetails.Name, "id", sandboxDetails.UUID)
+	log.Logger.Info("Sandbox stop started", "id", request.Id, "operation", operation.UUID)
 
-	return DeleteSandbox204Response{}, nil
+	return StopSandbox202JSONResponse{
+		Body: toOperation(operation),
+		Headers: StopSandbox202ResponseHeaders{
+			OperationLocation: operationLocation(operation.UUID),
+		},
+	}, nil
 }
 
 func (sh *SandboxHandler) GetSandbox(ctx context.Context, request GetSandboxRequestObject) (GetSandboxResponseObject, error) {
 	sandboxDetails, err := sh.instances.GetByUUID(request.Id)
 	if err != nil {
-		return GetSandboxdefaultJSONResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body: Error{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			}}, nil
+		problem := problemFromError(err)
+		return GetSandboxdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	if matchesIfNoneMatch(request.Params.IfNoneMatch, sandboxDetails.Version) {
+		return GetSandbox304Response{
+			Headers: GetSandbox304ResponseHeaders{
+				ETag: toETag(sandboxDetails.Version),
+			},
+		}, nil
 	}
 
-	return GetSandbox200JSONResponse(Sandbox{
-		Name:      sandboxDetails.Name,
-		Id:        sandboxDetails.UUID,
-		Status:    toSandboxStatus(sandboxDetails.Status),
-		CreatedAt: sandboxDetails.CreatedAt,
-		ExpiresAt: sandboxDetails.ExpiresAt,
-		UpdatedAt: sandboxDetails.UpdatedAt,
-	}), nil
+	return GetSandbox200JSONResponse{
+		Body: toSandbox(sandboxDetails),
+		Headers: GetSandbox200ResponseHeaders{
+			ETag: toETag(sandboxDetails.Version),
+		},
+	}, nil
+}
+
+func (sh *SandboxHandler) ResolveSandbox(ctx context.Context, request ResolveSandboxRequestObject) (ResolveSandboxResponseObject, error) {
+	sandboxDetails, err := sh.instances.ResolveByName(request.Params.Name)
+	if err != nil {
+		problem := problemFromError(err)
+		return ResolveSandboxdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	return ResolveSandbox200JSONResponse{
+		Body: toSandbox(sandboxDetails),
+		Headers: ResolveSandbox200ResponseHeaders{
+			ETag: toETag(sandboxDetails.Version),
+		},
+	}, nil
 }
 
 func (sh *SandboxHandler) UpdateSandbox(ctx context.Context, request UpdateSandboxRequestObject) (UpdateSandboxResponseObject, error) {
+	if request.Body == nil {
+		problem := newProblem(http.StatusUnsupportedMediaType, codeBadRequest, "body must be a JSON merge patch ("+mergePatchContentType+")")
+		return UpdateSandboxdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	sandboxDetails, operation, err := sh.instances.Update(request.Id, request.Body.SandboxPatch, principalFromContext(ctx),
+		fromIfMatch(request.Params.IfMatch))
+	if err != nil {
+		problem := problemFromError(err)
+		return UpdateSandboxdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	return UpdateSandbox202JSONResponse{
+		Body: toOperation(operation),
+		Headers: UpdateSandbox202ResponseHeaders{
+			OperationLocation: operationLocation(operation.UUID),
+			ETag:              toETag(sandboxDetails.Version),
+		},
+	}, nil
+}
 
-	sandboxDetails, err := sh.instances.UpdateExpiration(request.Id, request.Body.ExpiresAt)
+func (sh *SandboxHandler) GetOperation(ctx context.Context, request GetOperationRequestObject) (GetOperationResponseObject, error) {
+	operation, err := sh.instances.GetOperation(request.Id)
 	if err != nil {
-		return UpdateSandboxdefaultJSONResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body: Error{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			}}, nil
-	}
-
-	return UpdateSandbox200JSONResponse(Sandbox{
-		Name:      sandboxDetails.Name,
-		Id:        sandboxDetails.UUID,
-		Status:    toSandboxStatus(sandboxDetails.Status),
-		CreatedAt: sandboxDetails.CreatedAt,
-		ExpiresAt: sandboxDetails.ExpiresAt,
-		UpdatedAt: sandboxDetails.UpdatedAt,
-	}), nil
+		problem := problemFromError(err)
+		return GetOperationdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	return GetOperation200JSONResponse(toOperation(operation)), nil
+}
+
+func (sh *SandboxHandler) CancelOperation(ctx context.Context, request CancelOperationRequestObject) (CancelOperationResponseObject, error) {
+	operation, err := sh.instances.CancelOperation(request.Id, principalFromContext(ctx))
+	if err != nil {
+		problem := problemFromError(err)
+		return CancelOperationdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	log.Logger.Info("Operation cancel requested", "id", operation.UUID, "sandbox", operation.SandboxID)
+
+	return CancelOperation200JSONResponse(toOperation(operation)), nil
 }
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+func toSchedule(schedule models.SandboxSchedule) Schedule {
+	result := Schedule{
+		SandboxId:   schedule.SandboxID,
+		StopCron:    schedule.StopCron,
+		StartCron:   schedule.StartCron,
+		Timezone:    schedule.Timezone,
+		NextStopAt:  schedule.NextStopAt,
+		NextStartAt: schedule.NextStartAt,
+	}
+
+	if schedule.Catalog != "" {
+		result.Catalog = &schedule.Catalog
+	}
+
+	return result
+}
+
+func toCatalogSchedule(schedule models.CatalogSchedule) CatalogSchedule {
+	return CatalogSchedule{
+		Catalog:   schedule.Catalog,
+		StopCron:  schedule.StopCron,
+		StartCron: schedule.StartCron,
+		Timezone:  schedule.Timezone,
+	}
+}
+
+func (sh *SandboxHandler) StartSandbox(ctx context.Context, request StartSandboxRequestObject) (StartSandboxResponseObject, error) {
+	operation, err := sh.instances.Start(request.Id, principalFromContext(ctx))
+	if err != nil {
+		problem := problemFromError(err)
+		return StartSandboxdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	log.Logger.Info("Sandbox start started", "id", request.Id, "operation", operation.UUID)
+
+	return StartSandbox202JSONResponse{
+		Body: toOperation(operation),
+		Headers: StartSandbox202ResponseHeaders{
+			OperationLocation: operationLocation(operation.UUID),
+		},
+	}, nil
+}
+
+func (sh *SandboxHandler) GetSandboxSchedule(ctx context.Context, request GetSandboxScheduleRequestObject) (GetSandboxScheduleResponseObject, error) {
+	schedule, err := sh.instances.GetSchedule(request.Id)
+	if err != nil {
+		problem := problemFromError(err)
+		return GetSandboxScheduledefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	return GetSandboxSchedule200JSONResponse(toSchedule(schedule)), nil
+}
+
+func (sh *SandboxHandler) SetSandboxSchedule(ctx context.Context, request SetSandboxScheduleRequestObject) (SetSandboxScheduleResponseObject, error) {
+	body := request.Body
+
+	startCron := ""
+	if body.StartCron != nil {
+		startCron = *body.StartCron
+	}
+
+	timezone := ""
+	if body.Timezone != nil {
+		timezone = *body.Timezone
+	}
+
+	schedule, err := sh.instances.SetSchedule(request.Id, body.StopCron, startCron, timezone, principalFromContext(ctx))
+	if err != nil {
+		problem := problemFromError(err)
+		return SetSandboxScheduledefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	log.Logger.Info("Sandbox schedule set", "id", request.Id, "stopCron", schedule.StopCron, "startCron", schedule.StartCron, "timezone", schedule.Timezone)
+
+	return SetSandboxSchedule200JSONResponse(toSchedule(schedule)), nil
+}
+
+func (sh *SandboxHandler) DeleteSandboxSchedule(ctx context.Context, request DeleteSandboxScheduleRequestObject) (DeleteSandboxScheduleResponseObject, error) {
+	if err := sh.instances.DeleteSchedule(request.Id, principalFromContext(ctx)); err != nil {
+		problem := problemFromError(err)
+		return DeleteSandboxScheduledefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	log.Logger.Info("Sandbox schedule deleted", "id", request.Id)
+
+	return DeleteSandboxSchedule204Response{}, nil
+}
+
+func (sh *SandboxHandler) SkipSandboxSchedule(ctx context.Context, request SkipSandboxScheduleRequestObject) (SkipSandboxScheduleResponseObject, error) {
+	action := string(request.Body.Action)
+
+	schedule, err := sh.instances.SkipSchedule(request.Id, action, principalFromContext(ctx))
+	if err != nil {
+		problem := problemFromError(err)
+		return SkipSandboxScheduledefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	log.Logger.Info("Sandbox schedule run skipped", "id", request.Id, "action", action)
+
+	return SkipSandboxSchedule200JSONResponse(toSchedule(schedule)), nil
+}
+
+func (sh *SandboxHandler) GetCatalogSchedule(ctx context.Context, request GetCatalogScheduleRequestObject) (GetCatalogScheduleResponseObject, error) {
+	schedule, err := sh.instances.GetCatalogSchedule(request.Catalog)
+	if err != nil {
+		problem := problemFromError(err)
+		return GetCatalogScheduledefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	return GetCatalogSchedule200JSONResponse(toCatalogSchedule(schedule)), nil
+}
+
+func (sh *SandboxHandler) SetCatalogSchedule(ctx context.Context, request SetCatalogScheduleRequestObject) (SetCatalogScheduleResponseObject, error) {
+	body := request.Body
+
+	startCron := ""
+	if body.StartCron != nil {
+		startCron = *body.StartCron
+	}
+
+	timezone := ""
+	if body.Timezone != nil {
+		timezone = *body.Timezone
+	}
+
+	schedule, err := sh.instances.SetCatalogSchedule(request.Catalog, body.StopCron, startCron, timezone, principalFromContext(ctx))
+	if err != nil {
+		problem := problemFromError(err)
+		return SetCatalogScheduledefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	log.Logger.Info("Catalog schedule set", "catalog", request.Catalog, "stopCron", schedule.StopCron, "startCron", schedule.StartCron, "timezone", schedule.Timezone)
+
+	return SetCatalogSchedule200JSONResponse(toCatalogSchedule(schedule)), nil
+}
+
+func (sh *SandboxHandler) DeleteCatalogSchedule(ctx context.Context, request DeleteCatalogScheduleRequestObject) (DeleteCatalogScheduleResponseObject, error) {
+	if err := sh.instances.DeleteCatalogSchedule(request.Catalog, principalFromContext(ctx)); err != nil {
+		problem := problemFromError(err)
+		return DeleteCatalogScheduledefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	log.Logger.Info("Catalog schedule deleted", "catalog", request.Catalog)
+
+	return DeleteCatalogSchedule204Response{}, nil
+}
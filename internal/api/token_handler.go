@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+func toApiToken(details models.APITokenDetails) ApiToken {
+	token := ApiToken{
+		Id:         details.UUID,
+		Name:       details.Name,
+		Scopes:     details.Scopes,
+		CreatedAt:  details.CreatedAt,
+		ExpiresAt:  details.ExpiresAt,
+		LastUsedAt: details.LastUsedAt,
+		RevokedAt:  details.RevokedAt,
+	}
+
+	if token.Scopes == nil {
+		token.Scopes = []string{}
+	}
+
+	return token
+}
+
+func (sh *SandboxHandler) ListApiTokens(ctx context.Context, request ListApiTokensRequestObject) (ListApiTokensResponseObject, error) {
+	tokens, err := sh.tokens.List(principalFromContext(ctx))
+	if err != nil {
+		problem := problemFromError(err)
+		return ListApiTokensdefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	response := make(ListApiTokens200JSONResponse, 0, len(tokens))
+	for _, token := range tokens {
+		response = append(response, toApiToken(token))
+	}
+
+	return response, nil
+}
+
+func (sh *SandboxHandler) CreateApiToken(ctx context.Context, request CreateApiTokenRequestObject) (CreateApiTokenResponseObject, error) {
+	body := request.Body
+
+	scopes := make([]string, 0, len(body.Scopes))
+	for _, scope := range body.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	details, secret, err := sh.tokens.Create(principalFromContext(ctx), body.Name, scopes, body.ExpiresAt)
+	if err != nil {
+		problem := problemFromError(err)
+		return CreateApiTokendefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	token := toApiToken(details)
+
+	return CreateApiToken201JSONResponse{
+		Id:         token.Id,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		RevokedAt:  token.RevokedAt,
+		Token:      secret,
+	}, nil
+}
+
+func (sh *SandboxHandler) RevokeApiToken(ctx context.Context, request RevokeApiTokenRequestObject) (RevokeApiTokenResponseObject, error) {
+	if err := sh.tokens.Revoke(request.Id, principalFromContext(ctx)); err != nil {
+		problem := problemFromError(err)
+		return RevokeApiTokendefaultJSONResponse{StatusCode: int(problem.Status), Body: problem}, nil
+	}
+
+	return RevokeApiToken204Response{}, nil
+}
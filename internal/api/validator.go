@@ -0,0 +1,230 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+func init() {
+	// The merge patch is a plain JSON document as far as the validation goes
+	openapi3filter.RegisterBodyDecoder(mergePatchContentType, openapi3filter.RegisteredBodyDecoder("application/json"))
+}
+
+// NewRequestValidator validates the requests against the OpenAPI spec. Unlike
+// the validator of oapi-codegen, it collects all the invalid parameters and
+// body fields and reports them in the problem details, so the clients don't
+// have to fix the request one field at a time.
+func NewRequestValidator(swagger *openapi3.T, authenticate openapi3filter.AuthenticationFunc) (func(next http.Handler) http.Handler, error) {
+	router, err := gorillamux.NewRouter(swagger)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &openapi3filter.Options{
+		AuthenticationFunc: authenticate,
+		MultiError:         true,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				writeProblem(w, problemFromRouting(err))
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				if challenge := authChallenge(err); challenge != "" {
+					w.Header().Set("WWW-Authenticate", challenge)
+				}
+
+				writeProblem(w, problemFromValidation(err))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// problemFromRouting reports the requests which don't match any operation of
+// the spec
+func problemFromRouting(err error) Problem {
+	switch {
+	case errors.Is(err, routers.ErrPathNotFound):
+		return newProblem(http.StatusNotFound, codeNotFound, err.Error())
+	case errors.Is(err, routers.ErrMethodNotAllowed):
+		return newProblem(http.StatusMethodNotAllowed, codeMethodNotAllowed, err.Error())
+	}
+
+	return newProblem(http.StatusBadRequest, codeBadRequest, err.Error())
+}
+
+// problemFromValidation converts the errors of the OpenAPI validation to
+// problem details. Failed authentication takes precedence over the invalid
+// fields. The caller with the valid token lacking the permissions is told
+// apart from the one without a valid token.
+func problemFromValidation(err error) Problem {
+	if authErr, ok := authenticationError(err); ok {
+		switch {
+		case errors.Is(authErr, ErrInsufficientScope):
+			return newProblem(http.StatusForbidden, codeForbidden, "token lacks the permissions required by the operation")
+		case errors.Is(authErr, ErrCSRFTokenMismatch):
+			return newProblem(http.StatusForbidden, codeForbidden, ErrCSRFTokenMismatch.Error())
+		case errors.Is(authErr, ErrMalformedAuthorization):
+			return newProblem(http.StatusBadRequest, codeBadRequest, ErrMalformedAuthorization.Error())
+		}
+
+		return newProblem(http.StatusUnauthorized, codeUnauthorized, "authentication failed")
+	}
+
+	fields := fieldErrorsFromValidation(err)
+	if len(fields) == 0 {
+		return newProblem(http.StatusBadRequest, codeBadRequest, err.Error())
+	}
+
+	problem := newProblem(http.StatusBadRequest, codeValidationFailed, "request has invalid fields")
+	problem.Errors = &fields
+
+	return problem
+}
+
+// Realm of the WWW-Authenticate challenges
+const authRealm = "sandbox"
+
+// authChallenge returns the WWW-Authenticate challenge of RFC 6750 for the
+// failed authentication, the error code is left out if the request has no
+// bearer token at all
+func authChallenge(err error) string {
+	authErr, ok := authenticationError(err)
+	if !ok {
+		return ""
+	}
+
+	var scopeErr *insufficientScopeError
+
+	switch {
+	case errors.As(authErr, &scopeErr):
+		return `Bearer realm="` + authRealm + `", error="insufficient_scope", scope="` + strings.Join(scopeErr.scopes, " ") + `"`
+	case errors.Is(authErr, ErrCSRFTokenMismatch):
+		// Not a problem of the token
+		return ""
+	case errors.Is(authErr, ErrNoCredentials), errors.Is(authErr, ErrUnsupportedScheme):
+		return `Bearer realm="` + authRealm + `"`
+	case errors.Is(authErr, ErrMalformedAuthorization):
+		return `Bearer realm="` + authRealm + `", error="invalid_request"`
+	}
+
+	return `Bearer realm="` + authRealm + `", error="invalid_token", error_description="token is malformed, expired or revoked"`
+}
+
+// Helper to get the error of the failed authentication, if any. The lack of
+// the permissions is reported over the other errors.
+func authenticationError(err error) (error, bool) {
+	var securityErr *openapi3filter.SecurityRequirementsError
+	if !errors.As(err, &securityErr) {
+		return nil, false
+	}
+
+	for _, e := range securityErr.Errors {
+		if errors.Is(e, ErrInsufficientScope) {
+			return e, true
+		}
+	}
+
+	if len(securityErr.Errors) == 0 {
+		return err, true
+	}
+
+	return securityErr.Errors[0], true
+}
+
+// Helper to flatten the validation errors to the list of invalid fields
+func fieldErrorsFromValidation(err error) []FieldError {
+	// Not errors.As, the request errors of the body unwrap to multi errors too
+	if multiErr, ok := err.(openapi3.MultiError); ok {
+		fields := make([]FieldError, 0, len(multiErr))
+		for _, e := range multiErr {
+			fields = append(fields, fieldErrorsFromValidation(e)...)
+		}
+
+		return fields
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return nil
+	}
+
+	if requestErr.Parameter != nil {
+		return []FieldError{{Field: requestErr.Parameter.Name, Message: validationMessage(requestErr)}}
+	}
+
+	if requestErr.RequestBody != nil {
+		fields := bodyFieldErrors(requestErr.Err)
+		if len(fields) == 0 {
+			fields = []FieldError{{Field: "body", Message: validationMessage(requestErr)}}
+		}
+
+		return fields
+	}
+
+	return nil
+}
+
+// Helper to list the body fields which don't match the schema, the fields
+// are named by the dot separated path
+func bodyFieldErrors(err error) []FieldError {
+	if multiErr, ok := err.(openapi3.MultiError); ok {
+		fields := make([]FieldError, 0, len(multiErr))
+		for _, e := range multiErr {
+			fields = append(fields, bodyFieldErrors(e)...)
+		}
+
+		return fields
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return nil
+	}
+
+	field := strings.Join(schemaErr.JSONPointer(), ".")
+	if field == "" {
+		field = "body"
+	}
+
+	return []FieldError{{Field: field, Message: schemaErr.Reason}}
+}
+
+// Helper to get the message without the parameter name, it is in the field
+func validationMessage(requestErr *openapi3filter.RequestError) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(requestErr.Err, &schemaErr) {
+		return schemaErr.Reason
+	}
+
+	var parseErr *openapi3filter.ParseError
+	if errors.As(requestErr.Err, &parseErr) && parseErr.Reason != "" {
+		return parseErr.Reason
+	}
+
+	if requestErr.Err != nil {
+		return requestErr.Err.Error()
+	}
+
+	return requestErr.Reason
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/makirill/sandbox-azure/internal/models"
+)
+
+func TestRequestValidatorRouting(t *testing.T) {
+	swagger, err := GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	swagger.Servers = nil
+
+	allow := func(ctx context.Context, input *openapi3filter.AuthenticationInput) error { return nil }
+
+	validator, err := NewRequestValidator(swagger, allow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := validator(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"unknown path", http.MethodGet, "/unknown", http.StatusNotFound},
+		{"unknown method", http.MethodPut, "/sandboxes", http.StatusMethodNotAllowed},
+		{"known operation", http.MethodGet, "/sandboxes", http.StatusNoContent},
+		{"name not matching the pattern", http.MethodGet, "/sandboxes/name/old%20name", http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Errorf("%s %s = %d, want %d: %s", tt.method, tt.path, w.Code, tt.status, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequestValidatorChallenges(t *testing.T) {
+	swagger, err := GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	swagger.Servers = nil
+
+	fa, err := NewFakeAuthenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := fa.CreateJSWWithClaims("reader", []string{models.PermissionRead}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := fa.CreateJSWWithClaims("reader", []string{models.PermissionRead}, -time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &Authenticator{Validator: fa}
+	validator, err := NewRequestValidator(swagger, a.Authenticate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := PrincipalContext(validator(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	tests := []struct {
+		name      string
+		method    string
+		header    string
+		status    int
+		challenge string
+	}{
+		{"granted", http.MethodGet, "Bearer " + string(reader), http.StatusNoContent, ""},
+		{"no token", http.MethodGet, "", http.StatusUnauthorized, `Bearer realm="sandbox"`},
+		{"other scheme", http.MethodGet, "Basic dXNlcjpwYXNz", http.StatusUnauthorized, `Bearer realm="sandbox"`},
+		{"malformed", http.MethodGet, "Bearer a b", http.StatusBadRequest, `Bearer realm="sandbox", error="invalid_request"`},
+		{"expired", http.MethodGet, "Bearer " + string(expired), http.StatusUnauthorized, `Bearer realm="sandbox", error="invalid_token", error_description="token is malformed, expired or revoked"`},
+		{"insufficient scope", http.MethodDelete, "Bearer " + string(reader), http.StatusForbidden, `Bearer realm="sandbox", error="insufficient_scope", scope="sandbox:w"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/sandboxes/065293e2-238c-49ff-8f65-8036bce30174", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != tt.challenge {
+				t.Errorf("WWW-Authenticate = %s, want %s", got, tt.challenge)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+const activityLogAPIVersion = "2015-04-01"
+
+// ActivityLog reads the write operations on the resource groups from the
+// Azure activity log of the subscription
+type ActivityLog struct {
+	subscriptionID string
+	client         *arm.Client
+	// The operations of the callers, like the identity of this service
+	// stopping the sandboxes on schedule, are not the activity of the users
+	ignoredCallers map[string]bool
+}
+
+type activityLogEvent struct {
+	EventTimestamp time.Time `json:"eventTimestamp"`
+	Caller         string    `json:"caller"`
+	Authorization  struct {
+		Action string `json:"action"`
+	} `json:"authorization"`
+	Status struct {
+		Value string `json:"value"`
+	} `json:"status"`
+}
+
+type activityLogPage struct {
+	Value    []activityLogEvent `json:"value"`
+	NextLink string             `json:"nextLink"`
+}
+
+func NewActivityLog(subscriptionID string, ignoredCallers []string) (*ActivityLog, error) {
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := arm.NewClient("azure.ActivityLog", "v1.0.0", cred, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	activityLog := &ActivityLog{
+		subscriptionID: subscriptionID,
+		client:         client,
+		ignoredCallers: make(map[string]bool, len(ignoredCallers)),
+	}
+	for _, caller := range ignoredCallers {
+		activityLog.ignoredCallers[strings.ToLower(caller)] = true
+	}
+
+	return activityLog, nil
+}
+
+// LastWrite returns the time of the latest succeeded write or delete on the
+// resource group since the given time, nil if there was none. The activity
+// log keeps the events for 90 days.
+func (a *ActivityLog) LastWrite(ctx context.Context, resourceGroup string, since time.Time) (*time.Time, error) {
+	filter := fmt.Sprintf("eventTimestamp ge '%s' and resourceGroupName eq '%s'",
+		since.UTC().Format(time.RFC3339), resourceGroup)
+
+	endpoint := runtime.JoinPaths(a.client.Endpoint(),
+		"subscriptions", a.subscriptionID, "providers/Microsoft.Insights/eventtypes/management/values")
+
+	var lastWrite *time.Time
+
+	for endpoint != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		// The next link carries the query already
+		if !strings.Contains(endpoint, "?") {
+			query := req.Raw().URL.Query()
+			query.Set("api-version", activityLogAPIVersion)
+			query.Set("$filter", filter)
+			query.Set("$select", "eventTimestamp,caller,authorization,status")
+			req.Raw().URL.RawQuery = query.Encode()
+		}
+
+		resp, err := a.client.Pipeline().Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if !runtime.HasStatusCode(resp, http.StatusOK) {
+			return nil, runtime.NewResponseError(resp)
+		}
+
+		page := activityLogPage{}
+		if err := runtime.UnmarshalAsJSON(resp, &page); err != nil {
+			return nil, err
+		}
+
+		for i := range page.Value {
+			event := page.Value[i]
+			if !isWriteEvent(event) || a.ignoredCallers[strings.ToLower(event.Caller)] {
+				continue
+			}
+			if lastWrite == nil || event.EventTimestamp.After(*lastWrite) {
+				lastWrite = &event.EventTimestamp
+			}
+		}
+
+		endpoint = page.NextLink
+	}
+
+	return lastWrite, nil
+}
+
+// Helper to tell the changes of the resources from the reads and the failures
+func isWriteEvent(event activityLogEvent) bool {
+	action := strings.ToLower(event.Authorization.Action)
+
+	return event.Status.Value == "Succeeded" &&
+		(strings.HasSuffix(action, "/write") || strings.HasSuffix(action, "/delete") || strings.HasSuffix(action, "/action"))
+}
@@ -2,6 +2,7 @@ package azure
 
 import (
 	"context"
+	"errors"
 	"log"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
@@ -53,6 +54,8 @@ var (
 	location = "eastus"
 )
 
+var ErrResourceGroupExists = errors.New("resource group already exists")
+
 func CreateSandbox(name string, subscriptionID string) (resources *AzureResources, err error) {
 
 	azureClient, err := newAzureClient(subscriptionID)
@@ -65,12 +68,15 @@ func CreateSandbox(name string, subscriptionID string) (resources *AzureResource
 		log.Fatal(err)
 	}
 
-	var resourceGroup *armresources.ResourceGroup
-	if !exist {
-		resourceGroup, err = azureClient.createResourceGroup(name, location)
-		if err != nil {
-			return nil, err
-		}
+	// The resource group could be left over from another sandbox, taking it
+	// over would mix up the resources of the two
+	if exist {
+		return nil, ErrResourceGroupExists
+	}
+
+	resourceGroup, err := azureClient.createResourceGroup(azureClient.ctx, name, location, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	appId, err := azureClient.RegisterApplication("test-sample-application")
@@ -101,3 +107,54 @@ func CreateSandbox(name string, subscriptionID string) (resources *AzureResource
 		ApplicationID:  appId,
 	}, nil
 }
+
+// ResourceGroups manages the resource groups of the sandboxes in the subscription
+type ResourceGroups struct {
+	client *azureClient
+}
+
+func NewResourceGroups(subscriptionID string) (*ResourceGroups, error) {
+	client, err := newAzureClient(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceGroups{client: client}, nil
+}
+
+// CreateResourceGroup creates the resource group of the sandbox, the labels of
+// the sandbox become the tags of the group, so the cost reports line up with them
+func (r *ResourceGroups) CreateResourceGroup(ctx context.Context, name string, tags map[string]string) error {
+	exist, err := r.client.checkExistenceResourceGroup(name)
+	if err != nil {
+		return err
+	}
+
+	// The resource group could be left over from another sandbox, taking it
+	// over would mix up the resources of the two
+	if exist {
+		return ErrResourceGroupExists
+	}
+
+	_, err = r.client.createResourceGroup(ctx, name, location, tags)
+
+	return err
+}
+
+// DeleteResourceGroup removes the resource group of the sandbox and waits
+// until it is gone, the group which doesn't exist is already removed
+func (r *ResourceGroups) DeleteResourceGroup(ctx context.Context, name string) error {
+	exist, err := r.client.checkExistenceResourceGroup(name)
+	if err != nil || !exist {
+		return err
+	}
+
+	return r.client.deleteResourceGroup(ctx, name)
+}
+
+// UpdateTags syncs the sandbox labels to the tags of the resource group
+func (r *ResourceGroups) UpdateTags(ctx context.Context, name string, tags map[string]string) error {
+	_, err := r.client.updateResourceGroupTags(ctx, name, tags)
+
+	return err
+}
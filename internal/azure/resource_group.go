@@ -1,16 +1,19 @@
 package azure
 
 import (
+	"context"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 )
 
-func (client *azureClient) createResourceGroup(resourceGroupName string, location string) (*armresources.ResourceGroup, error) {
+func (client *azureClient) createResourceGroup(ctx context.Context, resourceGroupName string, location string, tags map[string]string) (*armresources.ResourceGroup, error) {
 	resourceGroupResp, err := client.resourceGroupClient.CreateOrUpdate(
-		client.ctx,
+		ctx,
 		resourceGroupName,
 		armresources.ResourceGroup{
 			Location: to.Ptr(location),
+			Tags:     toAzureTags(tags),
 		},
 		nil)
 	if err != nil {
@@ -58,3 +61,40 @@ func (client *azureClient) checkExistenceResourceGroup(resourceGroupName string)
 	return boolResp.Success, nil
 
 }
+
+func (client *azureClient) deleteResourceGroup(ctx context.Context, resourceGroupName string) error {
+	poller, err := client.resourceGroupClient.BeginDelete(ctx, resourceGroupName, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+
+	return err
+}
+
+// updateResourceGroupTags replaces the tags of the resource group
+func (client *azureClient) updateResourceGroupTags(ctx context.Context, resourceGroupName string, tags map[string]string) (*armresources.ResourceGroup, error) {
+
+	resourceGroupResp, err := client.resourceGroupClient.Update(
+		ctx,
+		resourceGroupName,
+		armresources.ResourceGroupPatchable{
+			Tags: toAzureTags(tags),
+		},
+		nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resourceGroupResp.ResourceGroup, nil
+}
+
+// Helper to convert the sandbox labels to the resource group tags
+func toAzureTags(tags map[string]string) map[string]*string {
+	azureTags := make(map[string]*string, len(tags))
+	for name, value := range tags {
+		azureTags[name] = to.Ptr(value)
+	}
+
+	return azureTags
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// APITokenDetails is the personal access token, without the token itself
+type APITokenDetails struct {
+	UUID string
+	// Owner is the subject of the principal which created the token, the
+	// token acts on its behalf
+	Owner      string
+	Name       string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+type APITokenData interface {
+	Insert(owner string, name string, tokenHash []byte, scopes []string, expiresAt time.Time) (string, error)
+	GetByID(id string) (APITokenDetails, error)
+	// GetActiveByHash returns the token by the hash unless it is revoked or expired
+	GetActiveByHash(tokenHash []byte) (APITokenDetails, error)
+	// GetByOwner lists the tokens of the owner, newest first
+	GetByOwner(owner string) ([]APITokenDetails, error)
+	// Revoke returns false if the token is already revoked
+	Revoke(id string) (bool, error)
+	// Touch records the use of the token, at most once per the interval
+	Touch(id string, interval time.Duration) error
+}
+
+// TokenController manages the personal access tokens of the principals
+type TokenController interface {
+	// Create returns the new token along with its secret, the secret is not
+	// stored and can't be retrieved later
+	Create(principal Principal, name string, scopes []string, expiresAt time.Time) (APITokenDetails, string, error)
+	List(principal Principal) ([]APITokenDetails, error)
+	Revoke(id string, principal Principal) error
+	// Validate returns the active token by its secret
+	Validate(secret string) (APITokenDetails, error)
+}
@@ -0,0 +1,269 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+)
+
+// APITokenPrefix tells the personal access tokens apart from the JWTs, and
+// makes them easy to spot by the secret scanners
+const APITokenPrefix = "sbx_"
+
+const (
+	// MaxAPITokenLifetime is the longest lifetime of the token
+	MaxAPITokenLifetime = 365 * 24 * time.Hour
+	// MaxAPITokens is the limit of the active tokens per owner
+	MaxAPITokens          = 50
+	MaxAPITokenNameLength = 100
+)
+
+// How often the last use of the token is recorded
+const apiTokenTouchInterval = time.Minute
+
+// The number of random bytes of the token
+const apiTokenSecretLength = 32
+
+// The tokens act on the sandboxes of their owners only, so they can't be
+// granted the admin or the approve permissions. The write permission implies
+// the read one, like for the JWTs.
+var apiTokenScopes = map[string][]string{
+	PermissionRead:  {PermissionRead},
+	PermissionWrite: {PermissionWrite, PermissionRead},
+}
+
+// Make sure we conform to the TokenController interface
+var _ TokenController = (*APITokens)(nil)
+
+// APITokens manages the personal access tokens, the scripts and the CI use
+// them instead of the tokens of the identity provider
+type APITokens struct {
+	data APITokenData
+}
+
+func NewAPITokens(data APITokenData) *APITokens {
+
+	return &APITokens{
+		data: data,
+	}
+}
+
+// Create issues the token to the principal. The scopes have to be granted to
+// the principal itself, and the tokens can't be used to create more tokens.
+func (t *APITokens) Create(principal Principal, name string, scopes []string, expiresAt time.Time) (APITokenDetails, string, error) {
+	if err := canManageAPITokens(principal); err != nil {
+		return APITokenDetails{}, "", err
+	}
+
+	scopes, err := validateAPIToken(principal, name, scopes, expiresAt)
+	if err != nil {
+		return APITokenDetails{}, "", err
+	}
+
+	tokens, err := t.data.GetByOwner(principal.Subject)
+	if err != nil {
+		return APITokenDetails{}, "", err
+	}
+
+	if countActive(tokens, time.Now()) >= MaxAPITokens {
+		return APITokenDetails{}, "", ErrAPITokenLimit
+	}
+
+	secret, err := newAPITokenSecret()
+	if err != nil {
+		return APITokenDetails{}, "", err
+	}
+
+	id, err := t.data.Insert(principal.Subject, name, hashAPIToken(secret), scopes, expiresAt.UTC())
+	if err != nil {
+		return APITokenDetails{}, "", err
+	}
+
+	log.Logger.Info("API token created", "id", id, "owner", principal.Subject, "scopes", scopes)
+
+	token, err := t.data.GetByID(id)
+	if err != nil {
+		return APITokenDetails{}, "", err
+	}
+
+	return token, secret, nil
+}
+
+// List returns the tokens of the principal, the revoked and the expired ones
+// included
+func (t *APITokens) List(principal Principal) ([]APITokenDetails, error) {
+	if err := canManageAPITokens(principal); err != nil {
+		return nil, err
+	}
+
+	return t.data.GetByOwner(principal.Subject)
+}
+
+// Revoke revokes the token of the principal, the admins revoke anyone's.
+// Revoking the revoked token again is not an error.
+func (t *APITokens) Revoke(id string, principal Principal) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	if err := canManageAPITokens(principal); err != nil {
+		return err
+	}
+
+	token, err := t.data.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	// The tokens of the others don't exist as far as the principal knows
+	if !principal.Has(PermissionAdmin) && token.Owner != principal.Subject {
+		return ErrAPITokenNotFound
+	}
+
+	revoked, err := t.data.Revoke(id)
+	if err != nil {
+		return err
+	}
+
+	if revoked {
+		log.Logger.Info("API token revoked", "id", id, "owner", token.Owner, "by", principal.Subject)
+	}
+
+	return nil
+}
+
+// Validate returns the active token by its secret, the token acts as its
+// owner limited to the scopes of the token
+func (t *APITokens) Validate(secret string) (APITokenDetails, error) {
+	if !strings.HasPrefix(secret, APITokenPrefix) {
+		return APITokenDetails{}, ErrInvalidAPIToken
+	}
+
+	token, err := t.data.GetActiveByHash(hashAPIToken(secret))
+	if errors.Is(err, ErrAPITokenNotFound) {
+		return APITokenDetails{}, ErrInvalidAPIToken
+	}
+	if err != nil {
+		return APITokenDetails{}, err
+	}
+
+	// The failure to record the use doesn't fail the request
+	if err := t.data.Touch(token.UUID, apiTokenTouchInterval); err != nil {
+		log.Logger.Error("Failed to record use of API token", "id", token.UUID, "err", err)
+	}
+
+	return token, nil
+}
+
+// The tokens are managed by the principals authenticated by the identity
+// provider, the leaked token can't be used to issue more tokens
+func canManageAPITokens(principal Principal) error {
+	if principal.APITokenID != "" {
+		return ErrAPITokenNotAllowed
+	}
+
+	// The token is owned by the subject
+	if principal.Subject == "" {
+		return ErrAnonymousTokenOwner
+	}
+
+	return nil
+}
+
+// validateAPIToken checks the new token and returns its scopes along with the
+// implied ones
+func validateAPIToken(principal Principal, name string, scopes []string, expiresAt time.Time) ([]string, error) {
+	errs := fieldErrors{}
+
+	if strings.TrimSpace(name) == "" {
+		errs.addField("name", "must not be empty")
+	} else if utf8.RuneCountInString(name) > MaxAPITokenNameLength {
+		errs.addField("name", "must be at most 100 characters long")
+	}
+
+	granted := []string{}
+	if len(scopes) == 0 {
+		errs.addField("scopes", "must not be empty")
+	}
+	for _, scope := range scopes {
+		implied, ok := apiTokenScopes[scope]
+		if !ok {
+			errs.addField("scopes", "scope "+scope+" can not be granted to API tokens")
+			continue
+		}
+
+		granted = append(granted, implied...)
+	}
+
+	now := time.Now()
+	if !expiresAt.After(now) {
+		errs.addField("expiresAt", "must be in the future")
+	} else if expiresAt.Sub(now) > MaxAPITokenLifetime {
+		errs.addField("expiresAt", "must be at most "+MaxAPITokenLifetime.String()+" ahead")
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	granted = UniqueScopes(granted)
+
+	// The token can't do more than its owner
+	for _, scope := range granted {
+		if !principal.Has(scope) {
+			return nil, ErrScopeNotGranted
+		}
+	}
+
+	return granted, nil
+}
+
+// UniqueScopes drops the repeated scopes or permissions, keeping the order
+func UniqueScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	unique := make([]string, 0, len(scopes))
+
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+
+	return unique
+}
+
+// Helper to count the tokens which are neither revoked nor expired
+func countActive(tokens []APITokenDetails, now time.Time) int {
+	count := 0
+
+	for _, token := range tokens {
+		if token.RevokedAt == nil && token.ExpiresAt.After(now) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func newAPITokenSecret() (string, error) {
+	secret := make([]byte, apiTokenSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// The tokens are random, so the plain hash is enough, unlike for passwords
+func hashAPIToken(secret string) []byte {
+	hash := sha256.Sum256([]byte(secret))
+
+	return hash[:]
+}
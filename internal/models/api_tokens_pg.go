@@ -0,0 +1,99 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Make sure we conform to the APITokenData interface
+var _ APITokenData = (*APITokensPostgres)(nil)
+
+type APITokensPostgres struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewAPITokensPostgres(dbPool *pgxpool.Pool) *APITokensPostgres {
+
+	return &APITokensPostgres{
+		dbPool: dbPool,
+	}
+}
+
+func (p *APITokensPostgres) Insert(owner string, name string, tokenHash []byte, scopes []string, expiresAt time.Time) (string, error) {
+	id := ""
+
+	err := p.dbPool.QueryRow(context.Background(), "SELECT public.insert_api_token($1, $2, $3, $4, $5)",
+		owner, name, tokenHash, scopes, expiresAt).Scan(&id)
+
+	return id, err
+}
+
+func (p *APITokensPostgres) GetByID(id string) (APITokenDetails, error) {
+	return p.getOne("SELECT * FROM public.get_api_token_by_id($1)", id)
+}
+
+func (p *APITokensPostgres) GetActiveByHash(tokenHash []byte) (APITokenDetails, error) {
+	return p.getOne("SELECT * FROM public.get_active_api_token_by_hash($1)", tokenHash)
+}
+
+func (p *APITokensPostgres) getOne(query string, arg interface{}) (APITokenDetails, error) {
+	token := APITokenDetails{}
+
+	err := scanAPIToken(p.dbPool.QueryRow(context.Background(), query, arg), &token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return APITokenDetails{}, ErrAPITokenNotFound
+	}
+
+	return token, err
+}
+
+func (p *APITokensPostgres) GetByOwner(owner string) ([]APITokenDetails, error) {
+	rows, err := p.dbPool.Query(context.Background(), "SELECT * FROM public.get_api_tokens_by_owner($1)", owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]APITokenDetails, 0)
+	for rows.Next() {
+		var token APITokenDetails
+
+		if err := scanAPIToken(rows, &token); err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (p *APITokensPostgres) Revoke(id string) (bool, error) {
+	ok := false
+
+	err := p.dbPool.QueryRow(context.Background(), "SELECT public.revoke_api_token($1)", id).Scan(&ok)
+
+	return ok, err
+}
+
+func (p *APITokensPostgres) Touch(id string, interval time.Duration) error {
+	_, err := p.dbPool.Exec(context.Background(), "SELECT public.touch_api_token($1, $2)", id, int(interval.Seconds()))
+
+	return err
+}
+
+func scanAPIToken(row pgx.Row, token *APITokenDetails) error {
+	return row.Scan(
+		&token.UUID,
+		&token.Owner,
+		&token.Name,
+		&token.Scopes,
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.RevokedAt)
+}
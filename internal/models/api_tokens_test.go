@@ -0,0 +1,186 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+)
+
+// fakeAPITokenData keeps the tokens in memory, by the id
+type fakeAPITokenData struct {
+	tokens map[string]APITokenDetails
+	hashes map[string]string
+}
+
+func newFakeAPITokenData() *fakeAPITokenData {
+	return &fakeAPITokenData{tokens: map[string]APITokenDetails{}, hashes: map[string]string{}}
+}
+
+func (f *fakeAPITokenData) Insert(owner string, name string, tokenHash []byte, scopes []string, expiresAt time.Time) (string, error) {
+	id := fmt.Sprintf("00000000-0000-4000-8000-%012d", len(f.tokens)+1)
+
+	f.tokens[id] = APITokenDetails{UUID: id, Owner: owner, Name: name, Scopes: scopes, CreatedAt: time.Now(), ExpiresAt: expiresAt}
+	f.hashes[string(tokenHash)] = id
+
+	return id, nil
+}
+
+func (f *fakeAPITokenData) GetByID(id string) (APITokenDetails, error) {
+	token, ok := f.tokens[id]
+	if !ok {
+		return APITokenDetails{}, ErrAPITokenNotFound
+	}
+
+	return token, nil
+}
+
+func (f *fakeAPITokenData) GetActiveByHash(tokenHash []byte) (APITokenDetails, error) {
+	token, ok := f.tokens[f.hashes[string(tokenHash)]]
+	if !ok || token.RevokedAt != nil || !token.ExpiresAt.After(time.Now()) {
+		return APITokenDetails{}, ErrAPITokenNotFound
+	}
+
+	return token, nil
+}
+
+func (f *fakeAPITokenData) GetByOwner(owner string) ([]APITokenDetails, error) {
+	tokens := []APITokenDetails{}
+	for _, token := range f.tokens {
+		if token.Owner == owner {
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens, nil
+}
+
+func (f *fakeAPITokenData) Revoke(id string) (bool, error) {
+	token := f.tokens[id]
+	if token.RevokedAt != nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	token.RevokedAt = &now
+	f.tokens[id] = token
+
+	return true, nil
+}
+
+func (f *fakeAPITokenData) Touch(id string, interval time.Duration) error {
+	token := f.tokens[id]
+
+	now := time.Now()
+	token.LastUsedAt = &now
+	f.tokens[id] = token
+
+	return nil
+}
+
+func TestValidateAPIToken(t *testing.T) {
+	writer := Principal{Subject: "alice", Permissions: []string{PermissionWrite, PermissionRead}}
+	reader := Principal{Subject: "alice", Permissions: []string{PermissionRead}}
+	admin := Principal{Subject: "alice", Permissions: []string{PermissionAdmin, PermissionWrite, PermissionRead}}
+	expiresAt := time.Now().Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		principal Principal
+		tokenName string
+		scopes    []string
+		expiresAt time.Time
+		want      []string
+		err       error
+	}{
+		{"read", writer, "ci", []string{PermissionRead}, expiresAt, []string{PermissionRead}, nil},
+		{"write implies read", writer, "ci", []string{PermissionWrite}, expiresAt, []string{PermissionWrite, PermissionRead}, nil},
+		{"repeated scopes", writer, "ci", []string{PermissionRead, PermissionWrite, PermissionRead}, expiresAt, []string{PermissionRead, PermissionWrite}, nil},
+		{"scope not granted", reader, "ci", []string{PermissionWrite}, expiresAt, nil, ErrScopeNotGranted},
+		{"admin scope", admin, "ci", []string{PermissionAdmin}, expiresAt, nil, NewFieldError("scopes", "")},
+		{"approve scope", admin, "ci", []string{PermissionApprove}, expiresAt, nil, NewFieldError("scopes", "")},
+		{"no scopes", writer, "ci", nil, expiresAt, nil, NewFieldError("scopes", "")},
+		{"empty name", writer, " ", []string{PermissionRead}, expiresAt, nil, NewFieldError("name", "")},
+		{"long name", writer, strings.Repeat("a", 101), []string{PermissionRead}, expiresAt, nil, NewFieldError("name", "")},
+		{"expired", writer, "ci", []string{PermissionRead}, time.Now().Add(-time.Minute), nil, NewFieldError("expiresAt", "")},
+		{"too long", writer, "ci", []string{PermissionRead}, time.Now().Add(MaxAPITokenLifetime + time.Hour), nil, NewFieldError("expiresAt", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateAPIToken(tt.principal, tt.tokenName, tt.scopes, tt.expiresAt)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("validateAPIToken() error = %v, want %v", err, tt.err)
+			}
+
+			var domainErr, wantErr *Error
+			if errors.As(err, &domainErr) && errors.As(tt.err, &wantErr) && len(wantErr.Fields) > 0 {
+				if domainErr.Fields[0].Field != wantErr.Fields[0].Field {
+					t.Errorf("validateAPIToken() field = %s, want %s", domainErr.Fields[0].Field, wantErr.Fields[0].Field)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateAPIToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPITokensLifecycle(t *testing.T) {
+	log.InitLoggers(false)
+
+	data := newFakeAPITokenData()
+	tokens := NewAPITokens(data)
+	alice := Principal{Subject: "alice", Permissions: []string{PermissionWrite, PermissionRead}}
+
+	created, secret, err := tokens.Create(alice, "ci", []string{PermissionWrite}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !strings.HasPrefix(secret, APITokenPrefix) {
+		t.Errorf("Create() secret = %q, want the %s prefix", secret, APITokenPrefix)
+	}
+
+	validated, err := tokens.Validate(secret)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if validated.UUID != created.UUID || !reflect.DeepEqual(validated.Scopes, []string{PermissionWrite, PermissionRead}) {
+		t.Errorf("Validate() = %+v, want %+v", validated, created)
+	}
+	if data.tokens[created.UUID].LastUsedAt == nil {
+		t.Error("Validate() didn't record the use of the token")
+	}
+
+	principal := Principal{Subject: validated.Owner, Permissions: validated.Scopes, APITokenID: validated.UUID}
+
+	// The token can't be used to manage the tokens
+	if _, _, err := tokens.Create(principal, "more", []string{PermissionRead}, time.Now().Add(time.Hour)); !errors.Is(err, ErrAPITokenNotAllowed) {
+		t.Errorf("Create() with the API token error = %v, want %v", err, ErrAPITokenNotAllowed)
+	}
+
+	// The tokens of the others are not found
+	bob := Principal{Subject: "bob", Permissions: []string{PermissionWrite, PermissionRead}}
+	if err := tokens.Revoke(created.UUID, bob); !errors.Is(err, ErrAPITokenNotFound) {
+		t.Errorf("Revoke() by someone else error = %v, want %v", err, ErrAPITokenNotFound)
+	}
+
+	if err := tokens.Revoke(created.UUID, alice); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if err := tokens.Revoke(created.UUID, alice); err != nil {
+		t.Errorf("Revoke() again error = %v, want nil", err)
+	}
+
+	if _, err := tokens.Validate(secret); !errors.Is(err, ErrInvalidAPIToken) {
+		t.Errorf("Validate() of the revoked token error = %v, want %v", err, ErrInvalidAPIToken)
+	}
+	if _, err := tokens.Validate("not-a-token"); !errors.Is(err, ErrInvalidAPIToken) {
+		t.Errorf("Validate() of the malformed token error = %v, want %v", err, ErrInvalidAPIToken)
+	}
+}
@@ -0,0 +1,308 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+)
+
+// Actor of the decisions made by the scheduler
+const approvalExpiryActor = "approval-expiry"
+
+const (
+	// DefaultMaxLifetime is the longest lifetime of the sandbox created
+	// without approval
+	DefaultMaxLifetime = 30 * 24 * time.Hour
+	// DefaultApprovalTTL is how long the request waits for the decision
+	DefaultApprovalTTL = 72 * time.Hour
+)
+
+// DefaultPrivilegedRoles can grant access to someone else, so the sandboxes
+// assigned them wait for the approval
+var DefaultPrivilegedRoles = []string{RoleOwner, RoleUserAccessAdministrator}
+
+// ApprovalPolicy decides which sandboxes wait for the approval instead of
+// being provisioned right away
+type ApprovalPolicy struct {
+	// MaxLifetime is the longest lifetime without approval, 0 is unlimited
+	MaxLifetime time.Duration
+	// PrivilegedRoles are the roles assigned only with approval
+	PrivilegedRoles []string
+	// LargeTemplates are the templates deployed only with approval
+	LargeTemplates []string
+	// RequestTTL is how long the request waits for the decision before it expires
+	RequestTTL time.Duration
+}
+
+// check returns the rules of the policy the sandbox exceeds, none if it can
+// be provisioned without approval. The lifetime starts at startAt if set.
+func (p ApprovalPolicy) check(expiresAt time.Time, startAt *time.Time, options SandboxOptions) []string {
+	reasons := []string{}
+
+	if p.exceedsLifetime(expiresAt, startAt) {
+		reasons = append(reasons, "lifetime exceeds "+p.MaxLifetime.String())
+	}
+
+	role := options.Role
+	if role == "" {
+		role = DefaultRole
+	}
+
+	if contains(p.PrivilegedRoles, role) {
+		reasons = append(reasons, "role "+role+" is privileged")
+	}
+
+	if options.Template != "" && contains(p.LargeTemplates, options.Template) {
+		reasons = append(reasons, "template "+options.Template+" is large")
+	}
+
+	return reasons
+}
+
+// exceedsLifetime is the only rule checked once the sandbox exists, the role
+// and the template can't be changed
+func (p ApprovalPolicy) exceedsLifetime(expiresAt time.Time, startAt *time.Time) bool {
+	start := time.Now()
+	if startAt != nil {
+		start = *startAt
+	}
+
+	return p.MaxLifetime > 0 && expiresAt.Sub(start) > p.MaxLifetime
+}
+
+// Helper to look up the role or the template in the lists of the policy
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetApprovalPolicy replaces the default policy
+func (s *AzureSandbox) SetApprovalPolicy(policy ApprovalPolicy) {
+	s.policy = policy
+}
+
+// SetNotifier replaces the default notifier, which only logs
+func (s *AzureSandbox) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// requestApproval records the request for the sandbox waiting in PENDING_APPROVAL
+func (s *AzureSandbox) requestApproval(sandboxID string, name string, reasons []string, owner string) error {
+	id, err := s.approvals.Insert(sandboxID, name, reasons, owner, time.Now().UTC().Add(s.policy.RequestTTL))
+	if err != nil {
+		return err
+	}
+
+	log.Logger.Info("Sandbox waits for approval", "id", sandboxID, "approval", id, "reasons", reasons)
+
+	s.notify(Notification{
+		Event:       EventApprovalRequested,
+		ApprovalID:  id,
+		SandboxID:   sandboxID,
+		SandboxName: name,
+		RequestedBy: owner,
+		Reasons:     reasons,
+	})
+
+	return nil
+}
+
+func (s *AzureSandbox) ListApprovals(status string, limit int, offset int) ([]ApprovalDetails, error) {
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+
+	return s.approvals.GetAll(status, limit, offset)
+}
+
+func (s *AzureSandbox) GetApproval(id string) (ApprovalDetails, error) {
+	if err := validateID(id); err != nil {
+		return ApprovalDetails{}, err
+	}
+
+	return s.approvals.GetByID(id)
+}
+
+// Approve provisions the sandbox, or leaves it to the scheduler if its start
+// is still ahead
+func (s *AzureSandbox) Approve(id string, principal Principal, comment string) (ApprovalDetails, error) {
+	return s.decide(id, ApprovalApproved, principal.Subject, comment)
+}
+
+// Deny deletes the sandbox and fails its create operation
+func (s *AzureSandbox) Deny(id string, principal Principal, comment string) (ApprovalDetails, error) {
+	return s.decide(id, ApprovalDenied, principal.Subject, comment)
+}
+
+func (s *AzureSandbox) decide(id string, status string, approver string, comment string) (ApprovalDetails, error) {
+	if err := validateID(id); err != nil {
+		return ApprovalDetails{}, err
+	}
+
+	if len(comment) > MaxDescriptionLength {
+		return ApprovalDetails{}, NewFieldError("comment", "must be at most 1024 characters")
+	}
+
+	// The decision is recorded for the approver, and the anonymous approver
+	// could be the requester as well
+	if approver == "" {
+		return ApprovalDetails{}, ErrAnonymousApprover
+	}
+
+	approval, err := s.approvals.GetByID(id)
+	if err != nil {
+		return ApprovalDetails{}, err
+	}
+
+	if approval.Status != ApprovalPending {
+		return ApprovalDetails{}, ErrApprovalDecided
+	}
+
+	if approver == approval.RequestedBy {
+		return ApprovalDetails{}, ErrSelfApproval
+	}
+
+	if status == ApprovalApproved {
+		if err := s.checkApprovedCreate(approval); err != nil {
+			return ApprovalDetails{}, err
+		}
+	}
+
+	sandboxStatus, err := s.approvals.Decide(id, status, approver, comment)
+	if err != nil {
+		return ApprovalDetails{}, err
+	}
+
+	// Decided or expired meanwhile
+	if sandboxStatus == "" {
+		return ApprovalDetails{}, ErrApprovalDecided
+	}
+
+	s.applyDecision(approval, sandboxStatus, status, comment)
+
+	event := EventApprovalApproved
+	if status == ApprovalDenied {
+		event = EventApprovalDenied
+	}
+
+	log.Logger.Info("Approval request decided", "id", id, "sandbox", approval.SandboxID, "status", status, "approver", approver)
+
+	s.notify(Notification{
+		Event:       event,
+		ApprovalID:  id,
+		SandboxID:   approval.SandboxID,
+		SandboxName: approval.SandboxName,
+		RequestedBy: approval.RequestedBy,
+		Actor:       approver,
+		Comment:     comment,
+	})
+
+	return s.approvals.GetByID(id)
+}
+
+// checkApprovedCreate applies the limit of the pending creates to the
+// sandbox provisioned once approved, the same as if it was created without
+// approval. The scheduled sandbox is left to the scheduler. The permissions
+// of the requester are not known anymore, so the admins are limited as well.
+func (s *AzureSandbox) checkApprovedCreate(approval ApprovalDetails) error {
+	details, err := s.instances.GetByID(approval.SandboxID)
+	if err != nil {
+		return err
+	}
+
+	if details.StartAt != nil && details.StartAt.After(time.Now()) {
+		return nil
+	}
+
+	return s.checkPendingCreates(Principal{Subject: approval.RequestedBy})
+}
+
+// applyDecision moves on the create operation waiting for the decision
+func (s *AzureSandbox) applyDecision(approval ApprovalDetails, sandboxStatus string, status string, comment string) {
+	operation, err := s.operations.GetNotStarted(approval.SandboxID, OperationCreate)
+	if err != nil {
+		log.Logger.Error("Failed to get create operation for sandbox", "id", approval.SandboxID, "err", err)
+		return
+	}
+
+	switch {
+	case sandboxStatus == StatusPending:
+		s.provision(operation)
+	case sandboxStatus == StatusScheduled:
+		// The scheduler provisions it at the start
+	case status == ApprovalDenied:
+		message := "request was denied"
+		if comment != "" {
+			message += ": " + comment
+		}
+		_, err = s.operations.UpdateError(operation.UUID, OperationFailed, "ApprovalDenied", message)
+	case status == ApprovalExpired:
+		_, err = s.operations.UpdateError(operation.UUID, OperationFailed, "ApprovalExpired", "request was not decided in time")
+	default:
+		_, err = s.operations.UpdateError(operation.UUID, OperationCanceled, "OperationCanceled", "operation was canceled")
+	}
+
+	if err != nil {
+		log.Logger.Error("Failed to update create operation for sandbox", "id", approval.SandboxID, "err", err)
+	}
+}
+
+// cancelApproval withdraws the pending request of the removed sandbox. It
+// returns false if the request is decided meanwhile.
+func (s *AzureSandbox) cancelApproval(sandboxID string) (bool, error) {
+	approval, err := s.approvals.GetPendingBySandbox(sandboxID)
+	if errors.Is(err, ErrApprovalNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	sandboxStatus, err := s.approvals.Decide(approval.UUID, ApprovalCanceled, "", "sandbox was removed")
+	if err != nil || sandboxStatus == "" {
+		return false, err
+	}
+
+	s.applyDecision(approval, sandboxStatus, ApprovalCanceled, "")
+
+	return true, nil
+}
+
+// expireApprovals expires the requests nobody decided on in time
+func (s *AzureSandbox) expireApprovals() {
+	approvals, err := s.approvals.GetStale(scheduledBatchSize)
+	if err != nil {
+		log.Logger.Error("Failed to get stale approval requests", "err", err)
+		return
+	}
+
+	for _, approval := range approvals {
+		sandboxStatus, err := s.approvals.Decide(approval.UUID, ApprovalExpired, approvalExpiryActor, "")
+		if err != nil {
+			log.Logger.Error("Failed to expire approval request", "id", approval.UUID, "err", err)
+			continue
+		}
+		if sandboxStatus == "" {
+			continue
+		}
+
+		s.applyDecision(approval, sandboxStatus, ApprovalExpired, "")
+
+		log.Logger.Info("Approval request expired", "id", approval.UUID, "sandbox", approval.SandboxID)
+
+		s.notify(Notification{
+			Event:       EventApprovalExpired,
+			ApprovalID:  approval.UUID,
+			SandboxID:   approval.SandboxID,
+			SandboxName: approval.SandboxName,
+			RequestedBy: approval.RequestedBy,
+			Actor:       approvalExpiryActor,
+		})
+	}
+}
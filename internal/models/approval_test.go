@@ -0,0 +1,118 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApprovalPolicyCheck(t *testing.T) {
+	now := time.Now()
+	startAt := now.Add(30 * 24 * time.Hour)
+	policy := ApprovalPolicy{
+		MaxLifetime:     7 * 24 * time.Hour,
+		PrivilegedRoles: DefaultPrivilegedRoles,
+		LargeTemplates:  []string{"aks-cluster"},
+	}
+
+	tests := []struct {
+		name      string
+		policy    ApprovalPolicy
+		expiresAt time.Time
+		startAt   *time.Time
+		options   SandboxOptions
+		want      []string
+	}{
+		{"within the lifetime", policy, now.Add(24 * time.Hour), nil, SandboxOptions{}, []string{}},
+		{"exceeds the lifetime", policy, now.Add(8 * 24 * time.Hour), nil, SandboxOptions{}, []string{"lifetime exceeds 168h0m0s"}},
+		{"lifetime starts at startAt", policy, startAt.Add(24 * time.Hour), &startAt, SandboxOptions{}, []string{}},
+		{"unlimited", ApprovalPolicy{}, now.Add(365 * 24 * time.Hour), nil, SandboxOptions{Role: RoleOwner}, []string{}},
+		{"privileged role", policy, now.Add(24 * time.Hour), nil, SandboxOptions{Role: RoleOwner}, []string{"role Owner is privileged"}},
+		{"large template", policy, now.Add(24 * time.Hour), nil, SandboxOptions{Role: RoleReader, Template: "aks-cluster"}, []string{"template aks-cluster is large"}},
+		{"small template", policy, now.Add(24 * time.Hour), nil, SandboxOptions{Template: "storage-account"}, []string{}},
+		{
+			"every rule", policy, now.Add(8 * 24 * time.Hour), nil,
+			SandboxOptions{Role: RoleUserAccessAdministrator, Template: "aks-cluster"},
+			[]string{"lifetime exceeds 168h0m0s", "role User Access Administrator is privileged", "template aks-cluster is large"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.check(tt.expiresAt, tt.startAt, tt.options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// pendingApprovalData holds a single pending request, the decision is not
+// expected
+type pendingApprovalData struct {
+	ApprovalData
+	approval ApprovalDetails
+	decided  bool
+}
+
+func (p *pendingApprovalData) GetByID(id string) (ApprovalDetails, error) {
+	return p.approval, nil
+}
+
+func (p *pendingApprovalData) Decide(id string, status string, decidedBy string, comment string) (string, error) {
+	p.decided = true
+
+	return "", nil
+}
+
+func TestApproveChecksPendingCreates(t *testing.T) {
+	startAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		startAt *time.Time
+		err     error
+	}{
+		{"provisioned once approved", nil, ErrPendingCreateLimit},
+		{"scheduled", &startAt, ErrApprovalDecided},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &countingSandboxData{
+				SandboxData: &fakeSandboxData{sandbox: SandboxDetails{UUID: testSandboxID, Status: StatusPendingApproval, Owner: "alice", StartAt: tt.startAt}},
+				pending:     2,
+			}
+			approvals := &pendingApprovalData{approval: ApprovalDetails{
+				UUID:        "3f1c2a9e-8d4b-4c6e-a1f2-7b9d0e5c4a31",
+				SandboxID:   testSandboxID,
+				Status:      ApprovalPending,
+				RequestedBy: "alice",
+			}}
+			s := &AzureSandbox{instances: data, approvals: approvals, maxPendingCreates: 2}
+
+			_, err := s.Approve(approvals.approval.UUID, Principal{Subject: "bob", Permissions: []string{PermissionApprove}}, "")
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Approve() error = %v, want %v", err, tt.err)
+			}
+
+			// The request over the limit stays pending, so it can be approved later
+			if approvals.decided == (tt.err == ErrPendingCreateLimit) {
+				t.Errorf("Approve() decided the request = %v", approvals.decided)
+			}
+		})
+	}
+}
+
+func TestDecideAnonymousApprover(t *testing.T) {
+	s := &AzureSandbox{}
+
+	for _, status := range []string{ApprovalApproved, ApprovalDenied} {
+		t.Run(status, func(t *testing.T) {
+			_, err := s.decide("3f1c2a9e-8d4b-4c6e-a1f2-7b9d0e5c4a31", status, "", "")
+			if !errors.Is(err, ErrAnonymousApprover) {
+				t.Errorf("decide() error = %v, want %v", err, ErrAnonymousApprover)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Approval statuses as stored in the database
+const (
+	ApprovalPending  = "PENDING"
+	ApprovalApproved = "APPROVED"
+	ApprovalDenied   = "DENIED"
+	ApprovalExpired  = "EXPIRED"
+	ApprovalCanceled = "CANCELED"
+)
+
+// ApprovalDetails is the request to create the sandbox exceeding the policy
+type ApprovalDetails struct {
+	UUID        string
+	SandboxID   string
+	SandboxName string
+	Status      string
+	// Reasons tell which rules of the policy the sandbox exceeds
+	Reasons     []string
+	RequestedBy string
+	RequestedAt time.Time
+	// ExpiresAt is the time the pending request expires at
+	ExpiresAt time.Time
+	DecidedBy string
+	DecidedAt *time.Time
+	Comment   string
+}
+
+type ApprovalData interface {
+	Insert(sandboxID string, sandboxName string, reasons []string, requestedBy string, expiresAt time.Time) (string, error)
+	GetByID(id string) (ApprovalDetails, error)
+	GetPendingBySandbox(sandboxID string) (ApprovalDetails, error)
+	// GetAll lists the requests with the status, oldest first, empty status lists all of them
+	GetAll(status string, limit int, offset int) ([]ApprovalDetails, error)
+	// GetStale returns up to limit pending requests past their expiration
+	GetStale(limit int) ([]ApprovalDetails, error)
+	// Decide moves the pending request to the status and returns the new
+	// status of the sandbox, empty if the request is not pending anymore
+	Decide(id string, status string, decidedBy string, comment string) (string, error)
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Make sure we conform to the ApprovalData interface
+var _ ApprovalData = (*ApprovalsPostgres)(nil)
+
+type ApprovalsPostgres struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewApprovalsPostgres(dbPool *pgxpool.Pool) *ApprovalsPostgres {
+
+	return &ApprovalsPostgres{
+		dbPool: dbPool,
+	}
+}
+
+func (a *ApprovalsPostgres) Insert(sandboxID string, sandboxName string, reasons []string, requestedBy string, expiresAt time.Time) (string, error) {
+	id := ""
+
+	err := a.dbPool.QueryRow(context.Background(), "SELECT public.insert_approval($1, $2, $3, $4, $5)",
+		sandboxID, sandboxName, reasons, requestedBy, expiresAt).Scan(&id)
+
+	return id, err
+}
+
+func (a *ApprovalsPostgres) GetByID(id string) (ApprovalDetails, error) {
+	return a.getOne("SELECT * FROM public.get_approval_by_id($1)", id)
+}
+
+func (a *ApprovalsPostgres) GetPendingBySandbox(sandboxID string) (ApprovalDetails, error) {
+	return a.getOne("SELECT * FROM public.get_pending_approval_by_sandbox($1)", sandboxID)
+}
+
+func (a *ApprovalsPostgres) getOne(query string, id string) (ApprovalDetails, error) {
+	approval := ApprovalDetails{}
+
+	err := scanApproval(a.dbPool.QueryRow(context.Background(), query, id), &approval)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ApprovalDetails{}, ErrApprovalNotFound
+	}
+
+	return approval, err
+}
+
+func (a *ApprovalsPostgres) GetAll(status string, limit int, offset int) ([]ApprovalDetails, error) {
+	var nullableStatus *string
+	if status != "" {
+		nullableStatus = &status
+	}
+
+	return a.getMany("SELECT * FROM public.get_approvals($1, $2, $3)", nullableStatus, limit, offset)
+}
+
+func (a *ApprovalsPostgres) GetStale(limit int) ([]ApprovalDetails, error) {
+	return a.getMany("SELECT * FROM public.get_stale_approvals($1)", limit)
+}
+
+func (a *ApprovalsPostgres) getMany(query string, args ...interface{}) ([]ApprovalDetails, error) {
+	rows, err := a.dbPool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	approvals := make([]ApprovalDetails, 0)
+	for rows.Next() {
+		var approval ApprovalDetails
+
+		if err := scanApproval(rows, &approval); err != nil {
+			return nil, err
+		}
+
+		approvals = append(approvals, approval)
+	}
+
+	return approvals, rows.Err()
+}
+
+func (a *ApprovalsPostgres) Decide(id string, status string, decidedBy string, comment string) (string, error) {
+	var sandboxStatus *string
+
+	err := a.dbPool.QueryRow(context.Background(), "SELECT public.decide_approval($1, $2, $3, $4)",
+		id, status, decidedBy, comment).Scan(&sandboxStatus)
+	if err != nil || sandboxStatus == nil {
+		return "", err
+	}
+
+	return *sandboxStatus, nil
+}
+
+func scanApproval(row pgx.Row, approval *ApprovalDetails) error {
+	return row.Scan(
+		&approval.UUID,
+		&approval.SandboxID,
+		&approval.SandboxName,
+		&approval.Status,
+		&approval.Reasons,
+		&approval.RequestedBy,
+		&approval.RequestedAt,
+		&approval.ExpiresAt,
+		&approval.DecidedBy,
+		&approval.DecidedAt,
+		&approval.Comment)
+}
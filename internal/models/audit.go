@@ -0,0 +1,159 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+)
+
+// The events read from the store at once by the export
+const auditExportBatchSize = 1000
+
+// Make sure we conform to the AuditController interface
+var _ AuditController = (*AuditLog)(nil)
+
+// AuditLog is the tamper-evident log of the actions, the store chains the
+// events by their hashes
+type AuditLog struct {
+	data AuditData
+}
+
+func NewAuditLog(data AuditData) *AuditLog {
+
+	return &AuditLog{
+		data: data,
+	}
+}
+
+// Record appends the event to the log
+func (a *AuditLog) Record(event AuditEvent) (AuditEvent, error) {
+	if err := validateAuditOutcome(event.Outcome); err != nil {
+		return AuditEvent{}, err
+	}
+
+	return a.data.Insert(event)
+}
+
+// List returns a page of the events matching the filter, oldest first
+func (a *AuditLog) List(filter AuditFilter) (AuditPage, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultListLimit
+	}
+
+	if err := ValidateAuditFilter(filter); err != nil {
+		return AuditPage{}, err
+	}
+	if filter.Limit < 0 || filter.Limit > MaxListLimit {
+		return AuditPage{}, NewValidationError(fmt.Sprintf("limit must be between 1 and %d", MaxListLimit))
+	}
+
+	// One extra event tells if there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	events, err := a.data.GetAll(filter)
+	if err != nil {
+		return AuditPage{}, err
+	}
+
+	page := AuditPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.Next = page.Events[limit-1].ID
+	}
+
+	return page, nil
+}
+
+// Export reads the events in batches, so the whole log is never held in memory
+func (a *AuditLog) Export(filter AuditFilter, write func(AuditEvent) error) error {
+	if err := ValidateAuditFilter(filter); err != nil {
+		return err
+	}
+
+	filter.Limit = auditExportBatchSize
+
+	for {
+		events, err := a.data.GetAll(filter)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := write(event); err != nil {
+				return err
+			}
+		}
+
+		if len(events) < auditExportBatchSize {
+			return nil
+		}
+
+		filter.AfterID = events[len(events)-1].ID
+	}
+}
+
+// Verify checks the hash chain of the whole log
+func (a *AuditLog) Verify() (AuditVerification, error) {
+	verification, err := a.data.Verify()
+	if err != nil {
+		return AuditVerification{}, err
+	}
+
+	if verification.FirstInvalidID != 0 {
+		log.Logger.Error("Audit log chain is broken", "id", verification.FirstInvalidID)
+	}
+
+	return verification, nil
+}
+
+// DiffValues keeps only the fields changed between the before and the after
+// snapshots, the fields missing on one side are nil there
+func DiffValues(before map[string]interface{}, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+
+	for field, value := range before {
+		if !reflect.DeepEqual(value, after[field]) {
+			changedBefore[field] = value
+			changedAfter[field] = after[field]
+		}
+	}
+
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			changedBefore[field] = nil
+			changedAfter[field] = value
+		}
+	}
+
+	return changedBefore, changedAfter
+}
+
+// ValidateAuditFilter checks the filter, e.g. before the export starts
+func ValidateAuditFilter(filter AuditFilter) error {
+	errs := fieldErrors{}
+
+	if filter.Outcome != "" {
+		errs.add(validateAuditOutcome(filter.Outcome))
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		errs.addField("to", "must be after from")
+	}
+
+	if filter.AfterID < 0 {
+		errs.addField("after", "must not be negative")
+	}
+
+	return errs.err()
+}
+
+func validateAuditOutcome(outcome string) error {
+	if outcome != AuditOutcomeSuccess && outcome != AuditOutcomeFailure {
+		return NewFieldError("outcome", "must be either success or failure")
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Make sure we conform to the AuditData interface
+var _ AuditData = (*AuditPostgres)(nil)
+
+type AuditPostgres struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewAuditPostgres(dbPool *pgxpool.Pool) *AuditPostgres {
+
+	return &AuditPostgres{
+		dbPool: dbPool,
+	}
+}
+
+func (p *AuditPostgres) Insert(event AuditEvent) (AuditEvent, error) {
+	var statusCode *int
+	if event.StatusCode != 0 {
+		statusCode = &event.StatusCode
+	}
+
+	inserted := AuditEvent{}
+
+	err := scanAuditEvent(p.dbPool.QueryRow(context.Background(),
+		"SELECT * FROM public.insert_audit_event($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		event.Actor, event.Action, event.TargetType, event.TargetID, event.RequestID, event.Outcome,
+		statusCode, nullableValues(event.Before), nullableValues(event.After)), &inserted)
+
+	return inserted, err
+}
+
+func (p *AuditPostgres) GetAll(filter AuditFilter) ([]AuditEvent, error) {
+	var limit *int
+	if filter.Limit > 0 {
+		limit = &filter.Limit
+	}
+
+	rows, err := p.dbPool.Query(context.Background(),
+		"SELECT * FROM public.get_audit_events($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		nullableString(filter.Actor), nullableString(filter.Action), nullableString(filter.TargetType),
+		nullableString(filter.TargetID), nullableString(filter.RequestID), nullableString(filter.Outcome),
+		utcTime(filter.From), utcTime(filter.To), filter.AfterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]AuditEvent, 0)
+	for rows.Next() {
+		var event AuditEvent
+
+		if err := scanAuditEvent(rows, &event); err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (p *AuditPostgres) Verify() (AuditVerification, error) {
+	var verification AuditVerification
+	var firstInvalidID *int64
+
+	err := p.dbPool.QueryRow(context.Background(), "SELECT * FROM public.verify_audit_chain()").
+		Scan(&verification.Events, &firstInvalidID)
+	if err != nil {
+		return AuditVerification{}, err
+	}
+
+	if firstInvalidID != nil {
+		verification.FirstInvalidID = *firstInvalidID
+	}
+
+	return verification, nil
+}
+
+// Helper to store the missing values as NULL rather than the JSON null
+func nullableValues(values map[string]interface{}) interface{} {
+	if values == nil {
+		return nil
+	}
+
+	return values
+}
+
+func scanAuditEvent(row pgx.Row, event *AuditEvent) error {
+	var statusCode *int
+
+	err := row.Scan(
+		&event.ID,
+		&event.OccurredAt,
+		&event.Actor,
+		&event.Action,
+		&event.TargetType,
+		&event.TargetID,
+		&event.RequestID,
+		&event.Outcome,
+		&statusCode,
+		&event.Before,
+		&event.After,
+		&event.PrevHash,
+		&event.Hash)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != nil {
+		event.StatusCode = *statusCode
+	}
+
+	return nil
+}
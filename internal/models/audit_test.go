@@ -0,0 +1,175 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeAuditData keeps the events in memory, the hashes are not computed
+type fakeAuditData struct {
+	events []AuditEvent
+	// reads counts the calls of GetAll
+	reads int
+}
+
+func (f *fakeAuditData) Insert(event AuditEvent) (AuditEvent, error) {
+	event.ID = int64(len(f.events) + 1)
+	event.OccurredAt = time.Now()
+	f.events = append(f.events, event)
+
+	return event, nil
+}
+
+func (f *fakeAuditData) GetAll(filter AuditFilter) ([]AuditEvent, error) {
+	f.reads++
+
+	events := []AuditEvent{}
+	for _, event := range f.events {
+		if event.ID <= filter.AfterID || (filter.Actor != "" && event.Actor != filter.Actor) {
+			continue
+		}
+
+		events = append(events, event)
+		if filter.Limit > 0 && len(events) == filter.Limit {
+			break
+		}
+	}
+
+	return events, nil
+}
+
+func (f *fakeAuditData) Verify() (AuditVerification, error) {
+	return AuditVerification{Events: int64(len(f.events))}, nil
+}
+
+func TestDiffValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		before     map[string]interface{}
+		after      map[string]interface{}
+		wantBefore map[string]interface{}
+		wantAfter  map[string]interface{}
+	}{
+		{
+			name:       "changed",
+			before:     map[string]interface{}{"status": "RUNNING", "name": "sbx"},
+			after:      map[string]interface{}{"status": "STOPPED", "name": "sbx"},
+			wantBefore: map[string]interface{}{"status": "RUNNING"},
+			wantAfter:  map[string]interface{}{"status": "STOPPED"},
+		},
+		{
+			name:       "added and removed",
+			before:     map[string]interface{}{"notes": "old"},
+			after:      map[string]interface{}{"description": "new"},
+			wantBefore: map[string]interface{}{"notes": "old", "description": nil},
+			wantAfter:  map[string]interface{}{"notes": nil, "description": "new"},
+		},
+		{
+			name:       "nested",
+			before:     map[string]interface{}{"labels": map[string]interface{}{"team": "a"}},
+			after:      map[string]interface{}{"labels": map[string]interface{}{"team": "b"}},
+			wantBefore: map[string]interface{}{"labels": map[string]interface{}{"team": "a"}},
+			wantAfter:  map[string]interface{}{"labels": map[string]interface{}{"team": "b"}},
+		},
+		{
+			name:       "unchanged",
+			before:     map[string]interface{}{"name": "sbx"},
+			after:      map[string]interface{}{"name": "sbx"},
+			wantBefore: map[string]interface{}{},
+			wantAfter:  map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBefore, gotAfter := DiffValues(tt.before, tt.after)
+			if !reflect.DeepEqual(gotBefore, tt.wantBefore) || !reflect.DeepEqual(gotAfter, tt.wantAfter) {
+				t.Errorf("DiffValues() = %v, %v, want %v, %v", gotBefore, gotAfter, tt.wantBefore, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestAuditLogList(t *testing.T) {
+	data := &fakeAuditData{}
+	audit := NewAuditLog(data)
+
+	for _, actor := range []string{"alice", "bob", "alice", "alice"} {
+		if _, err := audit.Record(AuditEvent{Actor: actor, Action: "sandbox.stop", Outcome: AuditOutcomeSuccess}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	if _, err := audit.Record(AuditEvent{Actor: "alice", Action: "sandbox.stop", Outcome: "done"}); !errors.Is(err, NewFieldError("outcome", "")) {
+		t.Errorf("Record() of the unknown outcome error = %v, want the outcome field error", err)
+	}
+
+	from := time.Now()
+	to := from.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		filter AuditFilter
+		want   []int64
+		next   int64
+		err    bool
+	}{
+		{"first page", AuditFilter{Actor: "alice", Limit: 2}, []int64{1, 3}, 3, false},
+		{"last page", AuditFilter{Actor: "alice", Limit: 2, AfterID: 3}, []int64{4}, 0, false},
+		{"default limit", AuditFilter{}, []int64{1, 2, 3, 4}, 0, false},
+		{"limit too big", AuditFilter{Limit: MaxListLimit + 1}, nil, 0, true},
+		{"unknown outcome", AuditFilter{Outcome: "done"}, nil, 0, true},
+		{"empty time range", AuditFilter{From: &from, To: &to}, nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := audit.List(tt.filter)
+			if (err != nil) != tt.err {
+				t.Fatalf("List() error = %v, want error %v", err, tt.err)
+			}
+
+			ids := []int64(nil)
+			for _, event := range page.Events {
+				ids = append(ids, event.ID)
+			}
+
+			if !reflect.DeepEqual(ids, tt.want) || page.Next != tt.next {
+				t.Errorf("List() = %v next %d, want %v next %d", ids, page.Next, tt.want, tt.next)
+			}
+		})
+	}
+}
+
+func TestAuditLogExport(t *testing.T) {
+	data := &fakeAuditData{}
+	for i := 0; i < auditExportBatchSize*2+1; i++ {
+		_, _ = data.Insert(AuditEvent{Actor: "alice", Outcome: AuditOutcomeSuccess})
+	}
+
+	audit := NewAuditLog(data)
+
+	exported := int64(0)
+	err := audit.Export(AuditFilter{Limit: 1}, func(event AuditEvent) error {
+		exported++
+		if event.ID != exported {
+			return errors.New("events out of order")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if exported != int64(len(data.events)) || data.reads != 3 {
+		t.Errorf("Export() exported %d events in %d reads, want %d in 3", exported, data.reads, len(data.events))
+	}
+
+	stop := errors.New("stop")
+	if err := audit.Export(AuditFilter{}, func(AuditEvent) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("Export() error = %v, want the error of the write", err)
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Outcomes of the audited actions
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEvent is a single entry of the audit log. Before and After hold only
+// the fields changed by the action. The Hash covers the fields of the event
+// and the PrevHash, the hash of the previous event.
+type AuditEvent struct {
+	ID         int64
+	OccurredAt time.Time
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	Outcome    string
+	// StatusCode is the HTTP status of the response, 0 for the events not
+	// caused by a request, e.g. the status transitions
+	StatusCode int
+	Before     map[string]interface{}
+	After      map[string]interface{}
+	PrevHash   []byte
+	Hash       []byte
+}
+
+// AuditFilter selects the events, the empty fields don't filter
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	Outcome    string
+	From       *time.Time
+	To         *time.Time
+	// AfterID continues the listing after the event
+	AfterID int64
+	Limit   int
+}
+
+// AuditPage is a single page of the audit log, oldest first
+type AuditPage struct {
+	Events []AuditEvent
+	// Next is the id to continue the listing after, 0 on the last page
+	Next int64
+}
+
+// AuditVerification is the result of the check of the hash chain
+type AuditVerification struct {
+	Events int64
+	// FirstInvalidID is the first event not matching the chain, 0 if intact
+	FirstInvalidID int64
+}
+
+type AuditData interface {
+	// Insert appends the event to the chain, the id, the time and the hashes
+	// are set by the store
+	Insert(event AuditEvent) (AuditEvent, error)
+	// GetAll lists the events, oldest first, 0 limit lists all of them
+	GetAll(filter AuditFilter) ([]AuditEvent, error)
+	Verify() (AuditVerification, error)
+}
+
+// AuditController records the actions and lets the admins read them back
+type AuditController interface {
+	Record(event AuditEvent) (AuditEvent, error)
+	List(filter AuditFilter) (AuditPage, error)
+	// Export passes every event matching the filter to the write function,
+	// oldest first, the limit of the filter is ignored
+	Export(filter AuditFilter, write func(AuditEvent) error) error
+	Verify() (AuditVerification, error)
+}
@@ -1,7 +1,9 @@
 package models
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -10,118 +12,951 @@ import (
 )
 
 type AzureSandbox struct {
-	instances SandboxData
+	instances  SandboxData
+	operations OperationData
+	schedules  ScheduleData
+	approvals  ApprovalData
+
+	policy    ApprovalPolicy
+	notifier  Notifier
+	resources ResourceProvider
+
+	// The sandboxes of the owner being provisioned at once, 0 is unlimited
+	maxPendingCreates int
+
+	cancelLock sync.Mutex
+	cancels    map[string]runningOperation
+
 	sync.WaitGroup
 }
 
+// canActOn is Principal.canActOn for the actions which don't get the sandbox otherwise
+func (s *AzureSandbox) canActOn(principal Principal, id string) error {
+	if principal.Has(PermissionAdmin) {
+		return nil
+	}
+
+	details, err := s.instances.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	return principal.canActOn(details)
+}
+
+// runningOperation is the operation run by this instance, done is closed once
+// the operation is finished
+type runningOperation struct {
+	sandboxID string
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// operationStep is a single unit of work of a long-running operation
+type operationStep struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
 func NewAzureSandbox(dbPool *pgxpool.Pool) *AzureSandbox {
 	pgData := NewAzureSandboxesPostgres(dbPool)
+	pgOperations := NewOperationsPostgres(dbPool)
+	pgSchedules := NewSchedulesPostgres(dbPool)
+	pgApprovals := NewApprovalsPostgres(dbPool)
 
 	return &AzureSandbox{
-		instances: pgData,
+		instances:  pgData,
+		operations: pgOperations,
+		schedules:  pgSchedules,
+		approvals:  pgApprovals,
+		policy:     ApprovalPolicy{MaxLifetime: DefaultMaxLifetime, PrivilegedRoles: DefaultPrivilegedRoles, RequestTTL: DefaultApprovalTTL},
+		notifier:   LogNotifier{},
+		resources:  FakeResourceProvider{},
+		cancels:    make(map[string]runningOperation),
 	}
 }
 
-func (s *AzureSandbox) Create(name string, expireTime time.Time) (SandboxDetails, error) {
+// SetResourceProvider replaces the default provider, which only simulates the work
+func (s *AzureSandbox) SetResourceProvider(resources ResourceProvider) {
+	s.resources = resources
+}
+
+// PendingCreatesRetryAfter is how long the creates refused by the limit of the
+// pending ones should wait, about the time the provisioning takes
+const PendingCreatesRetryAfter = 30 * time.Second
 
-	c := make(chan string)
-	e := make(chan error)
-	s.Add(2)
-	defer s.Done()
+// SetMaxPendingCreates limits the sandboxes of the owner being provisioned at
+// once, 0 doesn't limit them
+func (s *AzureSandbox) SetMaxPendingCreates(limit int) {
+	s.maxPendingCreates = limit
+}
 
+// simulateWork stands in for the Azure calls which are not wired yet
+func simulateWork(ctx context.Context) error {
+	select {
+	case <-time.After(10 * time.Second): //TODO: replace it with the actual Azure calls
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startOperation records a new operation for the sandbox and runs its steps
+// in the background, see runOperation
+func (s *AzureSandbox) startOperation(sandboxID string, kind string, steps []operationStep, onSuccess func() error, onFailure func()) (OperationDetails, error) {
+	operation, err := s.insertOperation(sandboxID, kind)
+	if err != nil {
+		return OperationDetails{}, err
+	}
+
+	s.runOperation(operation, steps, onSuccess, onFailure)
+
+	return operation, nil
+}
+
+// insertOperation records a new operation, which is NOT_STARTED until it is
+// passed to runOperation
+func (s *AzureSandbox) insertOperation(sandboxID string, kind string) (OperationDetails, error) {
+	id, err := s.operations.Insert(sandboxID, kind)
+	if err != nil {
+		return OperationDetails{}, err
+	}
+
+	return s.operations.GetByID(id)
+}
+
+// runOperation runs the steps of the operation in the background. onSuccess
+// is called once all the steps are done, onFailure is called if any step fails
+// or the operation is canceled.
+func (s *AzureSandbox) runOperation(operation OperationDetails, steps []operationStep, onSuccess func() error, onFailure func()) {
+	id, sandboxID, kind := operation.UUID, operation.SandboxID, operation.Kind
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+
+	s.cancelLock.Lock()
+	s.cancels[id] = runningOperation{sandboxID: sandboxID, cancel: cancel, done: done}
+	s.cancelLock.Unlock()
+
+	s.Add(1)
 	go func() {
 		defer s.Done()
-		id, err := s.instances.Insert(name, expireTime)
-		if err != nil {
-			e <- err
-			return
+		defer func() {
+			s.cancelLock.Lock()
+			delete(s.cancels, id)
+			s.cancelLock.Unlock()
+			cancel()
+			close(done)
+		}()
+
+		step, err := s.runSteps(ctx, id, steps)
+		if err == nil && onSuccess != nil {
+			err = onSuccess()
 		}
 
-		c <- id
+		if err == nil {
+			_, err = s.operations.UpdateProgress(id, OperationSucceeded, 100, "")
+			if err != nil {
+				log.Logger.Error("Failed to update operation", "id", id, "err", err)
+			}
+			return
+		}
 
-		time.Sleep(30 * time.Second) //TODO: replace it with the actual Azure sandbox creation
+		if onFailure != nil {
+			onFailure()
+		}
 
-		_, err = s.instances.UpdateStatus(id, "Running")
+		// The operation is visible to the API clients, so only domain errors
+		// are reported as is. The rest goes to the logs.
+		var domainErr *Error
+		switch {
+		case errors.Is(err, context.Canceled):
+			_, err = s.operations.UpdateError(id, OperationCanceled, "OperationCanceled", "operation was canceled")
+		case errors.As(err, &domainErr):
+			_, err = s.operations.UpdateError(id, OperationFailed, domainErr.Code, domainErr.Message)
+		default:
+			log.Logger.Error("Operation failed", "id", id, "kind", kind, "sandbox", sandboxID, "step", step, "err", err)
+			_, err = s.operations.UpdateError(id, OperationFailed, "OperationFailed", "operation failed at step: "+step)
+		}
 		if err != nil {
-			log.Logger.Error("Failed to update status for sandbox: ", id, err)
+			log.Logger.Error("Failed to update operation", "id", id, "err", err)
 		}
 	}()
+}
 
-	select {
-	case id := <-c:
-		return s.instances.GetByID(id)
-	case err := <-e:
-		return SandboxDetails{}, err
+// runSteps executes the steps one by one, the name of the failed step is
+// returned along with the error
+func (s *AzureSandbox) runSteps(ctx context.Context, id string, steps []operationStep) (string, error) {
+	for i, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return step.name, err
+		}
+
+		_, err := s.operations.UpdateProgress(id, OperationRunning, i*100/len(steps), step.name)
+		if err != nil {
+			return step.name, err
+		}
+
+		err = step.run(ctx)
+		if err != nil {
+			return step.name, err
+		}
 	}
+
+	return "", nil
 }
 
-func (s *AzureSandbox) Remove(id string) (SandboxDetails, error) {
-	c := make(chan bool)
-	e := make(chan error)
-	s.Add(2)
-	defer s.Done()
+// Create records the sandbox and provisions it right away, or at startAt if
+// it is set. The creation of the scheduled sandbox is tracked by the same
+// operation, which is NOT_STARTED until the scheduler picks it up. The
+// sandboxes exceeding the policy wait for the approval first.
+func (s *AzureSandbox) Create(name string, expireTime time.Time, startAt *time.Time, principal Principal, labels map[string]string, options SandboxOptions) (SandboxDetails, OperationDetails, error) {
+	owner := principal.Subject
 
-	go func() {
-		defer s.Done()
+	// The timestamp columns keep the wall clock time only
+	expireTime = expireTime.UTC()
+	startAt = utcTime(startAt)
 
-		details, err := s.instances.GetByID(id)
-		if err != nil {
-			e <- err
-			return
+	errs := fieldErrors{}
+	errs.add(ValidateName(name))
+	errs.add(validateExpiresAt(expireTime))
+	errs.add(validateLabels(labels))
+	validateOptions(&errs, options)
+	if startAt != nil {
+		errs.add(validateStartAt(*startAt, expireTime))
+	}
+	if err := errs.err(); err != nil {
+		return SandboxDetails{}, OperationDetails{}, err
+	}
+
+	if options.Role == "" {
+		options.Role = DefaultRole
+	}
+
+	reasons := s.policy.check(expireTime, startAt, options)
+	pendingApproval := len(reasons) > 0
+
+	// Only the sandboxes provisioned right away fan out to Azure, the approved
+	// ones are checked once approved
+	if startAt == nil && !pendingApproval {
+		if err := s.checkPendingCreates(principal); err != nil {
+			return SandboxDetails{}, OperationDetails{}, err
+		}
+	}
+
+	id, err := s.instances.Insert(name, expireTime, startAt, owner, labels, options, pendingApproval)
+	if errors.Is(err, ErrNameTaken) {
+		return SandboxDetails{}, OperationDetails{}, s.nameTaken(name)
+	}
+	if err != nil {
+		return SandboxDetails{}, OperationDetails{}, err
+	}
+
+	s.inheritSchedule(id, labels)
+
+	operation, err := s.insertOperation(id, OperationCreate)
+	if err != nil {
+		return SandboxDetails{}, OperationDetails{}, err
+	}
+
+	switch {
+	case pendingApproval:
+		if err := s.requestApproval(id, name, reasons, owner); err != nil {
+			return SandboxDetails{}, OperationDetails{}, err
 		}
+	case startAt == nil:
+		s.provision(operation)
+	}
 
-		if details.Status == "Deleted" || details.Status == "Pending" {
-			e <- errors.New(error_wrong_status)
+	details, err := s.instances.GetByID(id)
+
+	return details, operation, err
+}
+
+// Helper to pass the time to the timestamp columns, which drop the offset
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	utc := t.UTC()
+
+	return &utc
+}
+
+// checkPendingCreates refuses to provision one more sandbox of the principal
+// while too many of its sandboxes are being provisioned. The admins are not
+// limited, e.g. for the batches. The check is not atomic, the concurrent
+// creates may exceed the limit by a few.
+func (s *AzureSandbox) checkPendingCreates(principal Principal) error {
+	if s.maxPendingCreates <= 0 || principal.Has(PermissionAdmin) {
+		return nil
+	}
+
+	pending, err := s.instances.Count(SandboxFilter{Owner: principal.Subject, Statuses: []string{StatusPending}})
+	if err != nil {
+		return err
+	}
+
+	if pending >= s.maxPendingCreates {
+		return ErrPendingCreateLimit
+	}
+
+	return nil
+}
+
+// nameTaken tells the name held by the sandbox being deleted from the one in
+// use. The resource group of the sandbox being deleted still exists, so the
+// name is released only once the teardown succeeds.
+func (s *AzureSandbox) nameTaken(name string) error {
+	holder, err := s.instances.GetActiveByName(name)
+	if err == nil && holder.Status == StatusDeleting {
+		return ErrNameDeleting
+	}
+
+	return ErrNameTaken
+}
+
+// provision creates the Azure resources of the sandbox, the progress is
+// reported by the create operation
+func (s *AzureSandbox) provision(operation OperationDetails) {
+	id := operation.SandboxID
+
+	s.runOperation(operation,
+		[]operationStep{
+			{
+				name: "Creating resource group",
+				run: func(ctx context.Context) error {
+					// The labels could be changed since the sandbox was requested
+					sandbox, err := s.instances.GetByID(id)
+					if err != nil {
+						return err
+					}
+
+					return s.resources.CreateResourceGroup(ctx, sandbox.Name, sandbox.Labels)
+				},
+			},
+			{name: "Registering application", run: simulateWork},
+			{name: "Assigning roles", run: simulateWork},
+		},
+		func() error {
+			_, err := s.instances.UpdateStatus(id, StatusRunning, 0)
+			return err
+		},
+		func() {
+			_, err := s.instances.UpdateStatus(id, StatusFailed, 0)
+			if err != nil {
+				log.Logger.Error("Failed to update status for sandbox", "id", id, "err", err)
+			}
+		})
+}
+
+// RunScheduler provisions the scheduled sandboxes, runs the stop/start
+// schedules once they are due and expires the stale approval requests. It
+// checks every interval until the context is done.
+func (s *AzureSandbox) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
 			return
+		case <-ticker.C:
+			s.startScheduled()
+			s.runSchedules()
+			s.expireApprovals()
 		}
+	}
+}
+
+// How many scheduled sandboxes are started at once
+const scheduledBatchSize = 10
+
+// The claimed sandbox is provisioned right away, so the claim this old means
+// the scheduler stopped before starting the create operation
+const scheduledClaimTimeout = 5 * time.Minute
+
+func (s *AzureSandbox) startScheduled() {
+	ids, err := s.instances.ClaimScheduled(scheduledBatchSize, scheduledClaimTimeout)
+	if err != nil {
+		log.Logger.Error("Failed to claim scheduled sandboxes", "err", err)
+		return
+	}
 
-		ok, err := s.instances.UpdateStatus(id, "Deleted")
+	for _, id := range ids {
+		operation, err := s.operations.GetNotStarted(id, OperationCreate)
 		if err != nil {
-			e <- err
-			return
+			log.Logger.Error("Failed to get create operation for scheduled sandbox", "id", id, "err", err)
+
+			if _, err := s.instances.UpdateStatus(id, StatusFailed, 0); err != nil {
+				log.Logger.Error("Failed to update status for sandbox", "id", id, "err", err)
+			}
+			continue
 		}
 
-		c <- ok
+		log.Logger.Info("Scheduled sandbox started", "id", id, "operation", operation.UUID)
 
-		time.Sleep(30 * time.Second) //TODO: replace it with the actual Azure sandbox deletion
+		s.provision(operation)
+	}
+}
 
-		s.instances.Delete(id)
-	}()
+func (s *AzureSandbox) Remove(id string, principal Principal, ifMatch int) (OperationDetails, error) {
+	if err := validateID(id); err != nil {
+		return OperationDetails{}, err
+	}
 
-	select {
-	case <-c:
-		return s.instances.GetByID(id)
-	case err := <-e:
-		return SandboxDetails{}, err
+	details, err := s.instances.GetByID(id)
+	if err != nil {
+		return OperationDetails{}, err
+	}
+
+	if err := principal.canActOn(details); err != nil {
+		return OperationDetails{}, err
+	}
+
+	if ifMatch != 0 && details.Version != ifMatch {
+		return OperationDetails{}, ErrPreconditionFailed
+	}
+
+	// The resources are not removed while they are being changed
+	switch details.Status {
+	case StatusDeleted, StatusDeleting, StatusPending, StatusStopping, StatusStarting:
+		return OperationDetails{}, ErrWrongStatus
+	}
+
+	deleteRecord := func() error {
+		_, err := s.instances.Delete(id)
+		return err
+	}
+
+	// Nothing is provisioned for the sandbox waiting for the approval, so only
+	// the record is deleted once the request is withdrawn
+	if details.Status == StatusPendingApproval {
+		ok, err := s.cancelApproval(id)
+		if err != nil {
+			return OperationDetails{}, err
+		}
+
+		if !ok {
+			return OperationDetails{}, ErrWrongStatus
+		}
+
+		return s.startOperation(id, OperationDelete, nil, deleteRecord, nil)
+	}
+
+	// The scheduled sandbox must not be picked up by the scheduler while it is
+	// deleted, so its version is always checked
+	scheduled := details.Status == StatusScheduled
+
+	version := ifMatch
+	if scheduled {
+		version = details.Version
+	}
+
+	// Nothing is provisioned for the scheduled sandbox yet, so it is deleted
+	// right away. The others are DELETING until the resources are removed,
+	// the record is kept FAILED if the teardown fails, so it can be retried.
+	status := StatusDeleting
+	if scheduled {
+		status = StatusDeleted
+	}
+
+	// The version check is repeated by the update, in case of a concurrent change
+	ok, err := s.instances.UpdateStatus(id, status, version)
+	if err != nil {
+		return OperationDetails{}, err
+	}
+
+	if !ok {
+		switch {
+		case ifMatch != 0:
+			return OperationDetails{}, ErrPreconditionFailed
+		case scheduled:
+			return OperationDetails{}, ErrWrongStatus
+		}
+		return OperationDetails{}, ErrSandboxNotFound
+	}
+
+	if scheduled {
+		s.cancelNotStarted(id, OperationCreate)
+
+		return s.startOperation(id, OperationDelete, nil, deleteRecord, nil)
+	}
+
+	return s.startOperation(id, OperationDelete,
+		[]operationStep{
+			{name: "Removing application", run: simulateWork},
+			{
+				name: "Deleting resource group",
+				run: func(ctx context.Context) error {
+					return s.resources.DeleteResourceGroup(ctx, details.Name)
+				},
+			},
+		},
+		deleteRecord,
+		func() {
+			_, err := s.instances.UpdateStatus(id, StatusFailed, 0)
+			if err != nil {
+				log.Logger.Error("Failed to update status for sandbox", "id", id, "err", err)
+			}
+		})
+}
+
+// ForceRemove deletes the sandbox in any status, e.g. the one stuck while its
+// operation was lost. The operations of the sandbox run by this instance are
+// canceled first. The record is deleted even if the resources can't be, the
+// leftovers are only logged. It is for the admins only.
+func (s *AzureSandbox) ForceRemove(id string, principal Principal) (OperationDetails, error) {
+	if err := validateID(id); err != nil {
+		return OperationDetails{}, err
+	}
+
+	if !principal.Has(PermissionAdmin) {
+		return OperationDetails{}, ErrAdminRequired
+	}
+
+	details, err := s.instances.GetByID(id)
+	if err != nil {
+		return OperationDetails{}, err
 	}
+
+	if details.Status == StatusDeleted {
+		return OperationDetails{}, ErrWrongStatus
+	}
+
+	s.cancelRunning(id)
+
+	if details.Status == StatusPendingApproval {
+		if _, err := s.cancelApproval(id); err != nil {
+			return OperationDetails{}, err
+		}
+	}
+	s.cancelNotStarted(id, OperationCreate)
+
+	// Read again, the canceled operations could have moved the sandbox on
+	details, err = s.instances.GetByID(id)
+	if err != nil {
+		return OperationDetails{}, err
+	}
+
+	ok, err := s.instances.UpdateStatus(id, StatusDeleting, details.Version)
+	if err != nil {
+		return OperationDetails{}, err
+	}
+
+	if !ok {
+		return OperationDetails{}, ErrWrongStatus
+	}
+
+	return s.startOperation(id, OperationDelete,
+		[]operationStep{
+			{name: "Removing application", run: simulateWork},
+			{
+				name: "Deleting resource group",
+				run: func(ctx context.Context) error {
+					if err := s.resources.DeleteResourceGroup(ctx, details.Name); err != nil {
+						log.Logger.Warn("Resource group of force deleted sandbox is left behind", "id", id, "name", details.Name, "err", err)
+					}
+					return nil
+				},
+			},
+		},
+		func() error {
+			_, err := s.instances.Delete(id)
+			return err
+		},
+		func() {
+			_, err := s.instances.UpdateStatus(id, StatusFailed, 0)
+			if err != nil {
+				log.Logger.Error("Failed to update status for sandbox", "id", id, "err", err)
+			}
+		})
 }
 
-func (s *AzureSandbox) ListAll(limit int, offset int) ([]SandboxDetails, error) {
-	return s.instances.GetAll(limit, offset)
+// cancelRunning cancels the operations of the sandbox run by this instance
+// and waits for them to finish
+func (s *AzureSandbox) cancelRunning(sandboxID string) {
+	running := []runningOperation{}
+
+	s.cancelLock.Lock()
+	for _, operation := range s.cancels {
+		if operation.sandboxID == sandboxID {
+			running = append(running, operation)
+		}
+	}
+	s.cancelLock.Unlock()
+
+	for _, operation := range running {
+		operation.cancel()
+		<-operation.done
+	}
 }
 
-func (s *AzureSandbox) GetByName(name string) ([]SandboxDetails, error) {
-	return s.instances.GetByName(name)
+func (s *AzureSandbox) Stop(id string, principal Principal) (OperationDetails, error) {
+	return s.powerAction(id, principal, OperationStop, StatusRunning, StatusStopping, StatusStopped,
+		[]operationStep{
+			{name: "Deallocating resources", run: simulateWork},
+			{name: "Locking resource group", run: simulateWork},
+		})
 }
 
-func (s *AzureSandbox) GetByUUID(id string) (SandboxDetails, error) {
-	return s.instances.GetByID(id)
+func (s *AzureSandbox) Start(id string, principal Principal) (OperationDetails, error) {
+	return s.powerAction(id, principal, OperationStart, StatusStopped, StatusStarting, StatusRunning,
+		[]operationStep{
+			{name: "Unlocking resource group", run: simulateWork},
+			{name: "Starting resources", run: simulateWork},
+		})
 }
 
-func (s *AzureSandbox) UpdateExpiration(id string, expiresAt time.Time) (SandboxDetails, error) {
+// powerAction moves the sandbox from the from status through the transitional
+// one to the to status once the steps are done. The version is checked by the
+// move, so only one of the concurrent requests starts the operation, the
+// sandbox is back in the from status if the operation fails.
+func (s *AzureSandbox) powerAction(id string, principal Principal, kind string, from string, transitional string, to string, steps []operationStep) (OperationDetails, error) {
+	if err := validateID(id); err != nil {
+		return OperationDetails{}, err
+	}
+
 	details, err := s.instances.GetByID(id)
 	if err != nil {
-		return SandboxDetails{}, err
+		return OperationDetails{}, err
+	}
+
+	if err := principal.canActOn(details); err != nil {
+		return OperationDetails{}, err
+	}
+
+	if details.Status != from {
+		return OperationDetails{}, ErrWrongStatus
+	}
+
+	ok, err := s.instances.UpdateStatus(id, transitional, details.Version)
+	if err != nil {
+		return OperationDetails{}, err
+	}
+
+	if !ok {
+		return OperationDetails{}, ErrWrongStatus
 	}
 
-	if details.Status == "Deleted" {
-		return SandboxDetails{}, errors.New(error_already_deleted)
+	restore := func() {
+		_, err := s.instances.UpdateStatus(id, from, 0)
+		if err != nil {
+			log.Logger.Error("Failed to update status for sandbox", "id", id, "err", err)
+		}
 	}
 
-	_, err = s.instances.UpdateExpiration(id, expiresAt)
+	operation, err := s.startOperation(id, kind, steps,
+		func() error {
+			_, err := s.instances.UpdateStatus(id, to, 0)
+			return err
+		},
+		restore)
 	if err != nil {
+		restore()
+		return OperationDetails{}, err
+	}
+
+	return operation, nil
+}
+
+func (s *AzureSandbox) ListAll(filter SandboxFilter) (SandboxPage, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultListLimit
+	}
+	if filter.SortBy == "" {
+		filter.SortBy = SortByCreatedAt
+	}
+	if filter.SortOrder == "" {
+		filter.SortOrder = SortDesc
+	}
+
+	if filter.Limit < 0 || filter.Limit > MaxListLimit {
+		return SandboxPage{}, NewValidationError(fmt.Sprintf("limit must be between 1 and %d", MaxListLimit))
+	}
+	if filter.Offset < 0 {
+		return SandboxPage{}, NewValidationError("offset must not be negative")
+	}
+	switch filter.SortBy {
+	case SortByCreatedAt, SortByUpdatedAt, SortByExpiresAt, SortByName:
+	default:
+		return SandboxPage{}, NewValidationError("sandboxes can't be sorted by " + filter.SortBy)
+	}
+	if filter.SortOrder != SortAsc && filter.SortOrder != SortDesc {
+		return SandboxPage{}, NewValidationError("sort order must be either asc or desc")
+	}
+	if filter.ExpiringWithin < 0 {
+		return SandboxPage{}, NewValidationError("expiring window must not be negative")
+	}
+	if filter.After != nil {
+		if filter.Offset != 0 {
+			return SandboxPage{}, NewValidationError("offset can't be combined with cursor")
+		}
+		if filter.After.SortBy != filter.SortBy || filter.After.SortOrder != filter.SortOrder {
+			return SandboxPage{}, ErrInvalidCursor
+		}
+	}
+
+	filter.CreatedAfter = utcTime(filter.CreatedAfter)
+	filter.CreatedBefore = utcTime(filter.CreatedBefore)
+	filter.ExpiresAfter = utcTime(filter.ExpiresAfter)
+	filter.ExpiresBefore = utcTime(filter.ExpiresBefore)
+
+	page := SandboxPage{Total: -1}
+
+	// One extra sandbox tells if there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	sandboxes, err := s.instances.GetAll(filter)
+	if err != nil {
+		return SandboxPage{}, err
+	}
+
+	if len(sandboxes) > limit {
+		sandboxes = sandboxes[:limit]
+		next := cursorAfter(sandboxes[limit-1], filter.SortBy, filter.SortOrder)
+		page.Next = &next
+	}
+	page.Sandboxes = sandboxes
+
+	if filter.WithTotal {
+		page.Total, err = s.instances.Count(filter)
+		if err != nil {
+			return SandboxPage{}, err
+		}
+	}
+
+	return page, nil
+}
+
+func (s *AzureSandbox) GetByUUID(id string) (SandboxDetails, error) {
+	if err := validateID(id); err != nil {
 		return SandboxDetails{}, err
 	}
 
 	return s.instances.GetByID(id)
 }
+
+// ResolveByName returns the sandbox which currently holds the name
+func (s *AzureSandbox) ResolveByName(name string) (SandboxDetails, error) {
+	return s.instances.GetActiveByName(name)
+}
+
+// Update applies the patch of the sandbox metadata. The record is updated
+// right away, so the caller gets the new version, the changes which must reach
+// Azure are synced by the operation.
+func (s *AzureSandbox) Update(id string, patch SandboxPatch, principal Principal, ifMatch int) (SandboxDetails, OperationDetails, error) {
+	if err := validateID(id); err != nil {
+		return SandboxDetails{}, OperationDetails{}, err
+	}
+
+	if patch.Empty() {
+		return SandboxDetails{}, OperationDetails{}, NewValidationError("patch doesn't change any field")
+	}
+
+	details, err := s.instances.GetByID(id)
+	if err != nil {
+		return SandboxDetails{}, OperationDetails{}, err
+	}
+
+	if err := principal.canActOn(details); err != nil {
+		return SandboxDetails{}, OperationDetails{}, err
+	}
+
+	if ifMatch != 0 && details.Version != ifMatch {
+		return SandboxDetails{}, OperationDetails{}, ErrPreconditionFailed
+	}
+
+	if details.Status == StatusDeleted || details.Status == StatusDeleting {
+		return SandboxDetails{}, OperationDetails{}, ErrAlreadyDeleted
+	}
+
+	if patch.ClearLabels {
+		patch.Labels = clearLabels(details.Labels, patch.Labels)
+	}
+
+	patch.ExpiresAt = utcTime(patch.ExpiresAt)
+
+	labels := mergeLabels(details.Labels, patch.Labels)
+
+	if err := validatePatch(patch, labels, details.StartAt); err != nil {
+		return SandboxDetails{}, OperationDetails{}, err
+	}
+
+	// The resource group of the PENDING sandbox is being created with the
+	// labels read at the start, so the new ones could be lost. The scheduled
+	// sandbox gets them once it is provisioned, unless the scheduler claims it
+	// meanwhile, so its version is checked.
+	version := ifMatch
+	if !equalLabels(details.Labels, labels) {
+		switch details.Status {
+		case StatusPending:
+			return SandboxDetails{}, OperationDetails{}, ErrWrongStatus
+		case StatusScheduled:
+			version = details.Version
+		}
+	}
+
+	// The approval is requested on create only, so the lifetime can't be
+	// extended past the policy afterwards. The pending request is decided on
+	// the expiration it was made for, so it is frozen until then.
+	if patch.ExpiresAt != nil && details.Status == StatusPendingApproval {
+		return SandboxDetails{}, OperationDetails{}, NewFieldError("expiresAt", "can not be changed while the sandbox waits for approval")
+	}
+
+	if patch.ExpiresAt != nil && s.policy.exceedsLifetime(*patch.ExpiresAt, nil) {
+		return SandboxDetails{}, OperationDetails{}, NewFieldError("expiresAt", "exceeds the lifetime allowed without approval")
+	}
+
+	ok, err := s.instances.Update(id, patch, principal.Subject, version)
+	if err != nil {
+		return SandboxDetails{}, OperationDetails{}, err
+	}
+
+	if !ok {
+		switch {
+		case ifMatch != 0:
+			return SandboxDetails{}, OperationDetails{}, ErrPreconditionFailed
+		case version != 0:
+			return SandboxDetails{}, OperationDetails{}, ErrWrongStatus
+		}
+		return SandboxDetails{}, OperationDetails{}, ErrSandboxNotFound
+	}
+
+	updated, err := s.instances.GetByID(id)
+	if err != nil {
+		return SandboxDetails{}, OperationDetails{}, err
+	}
+
+	if catalogOf(details.Labels) != catalogOf(updated.Labels) {
+		s.inheritSchedule(id, updated.Labels)
+	}
+
+	// Description, cost center and notes are kept in the database only. The
+	// scheduled sandboxes and the ones waiting for the approval get the
+	// changes once they are provisioned.
+	steps := []operationStep{}
+	provisioned := updated.Status != StatusScheduled && updated.Status != StatusPendingApproval && updated.Status != StatusPending
+	if patch.ExpiresAt != nil && provisioned {
+		steps = append(steps, operationStep{name: "Updating resource group expiration", run: simulateWork})
+	}
+	if !equalLabels(details.Labels, updated.Labels) && provisioned {
+		steps = append(steps, operationStep{
+			name: "Updating resource group tags",
+			run: func(ctx context.Context) error {
+				return s.resources.UpdateTags(ctx, updated.Name, updated.Labels)
+			},
+		})
+	}
+
+	// Changing just the expiration is still reported as an extension
+	kind := OperationUpdate
+	if patch.ExpiresAt != nil && patch.Description == nil && patch.CostCenter == nil && patch.Notes == nil &&
+		patch.KeepWhenIdle == nil && len(patch.Labels) == 0 {
+		kind = OperationExtend
+	}
+
+	operation, err := s.startOperation(id, kind, steps, nil, nil)
+
+	return updated, operation, err
+}
+
+func (s *AzureSandbox) GetOperation(id string) (OperationDetails, error) {
+	if err := validateID(id); err != nil {
+		return OperationDetails{}, err
+	}
+
+	return s.operations.GetByID(id)
+}
+
+// CancelOperation requests cancellation of a running operation. The operation
+// is marked as CANCELED asynchronously, once its current step is interrupted.
+func (s *AzureSandbox) CancelOperation(id string, principal Principal) (OperationDetails, error) {
+	if err := validateID(id); err != nil {
+		return OperationDetails{}, err
+	}
+
+	operation, err := s.operations.GetByID(id)
+	if err != nil {
+		return OperationDetails{}, err
+	}
+
+	// Partially removed resources can't be restored, so deletion runs to the end
+	if operation.Done() || operation.Kind == OperationDelete {
+		return OperationDetails{}, ErrNotCancelable
+	}
+
+	if err := s.canActOn(principal, operation.SandboxID); err != nil {
+		return OperationDetails{}, err
+	}
+
+	if operation.Status == OperationNotStarted && operation.Kind == OperationCreate {
+		canceled, err := s.cancelScheduled(operation)
+		if err != nil {
+			return OperationDetails{}, err
+		}
+
+		if canceled {
+			return s.operations.GetByID(id)
+		}
+	}
+
+	s.cancelLock.Lock()
+	running, ok := s.cancels[id]
+	s.cancelLock.Unlock()
+
+	if !ok {
+		return OperationDetails{}, ErrNotCancelable
+	}
+
+	running.cancel()
+
+	return s.operations.GetByID(id)
+}
+
+// cancelScheduled cancels the creation of the scheduled sandbox, or the one
+// waiting for the approval, before it is provisioned, the sandbox is deleted
+// without touching Azure. It returns false if the scheduler has already
+// picked the sandbox up or the request is decided.
+func (s *AzureSandbox) cancelScheduled(operation OperationDetails) (bool, error) {
+	sandbox, err := s.instances.GetByID(operation.SandboxID)
+	if err != nil {
+		return false, err
+	}
+
+	if sandbox.Status == StatusPendingApproval {
+		return s.cancelApproval(sandbox.UUID)
+	}
+
+	if sandbox.Status != StatusScheduled {
+		return false, nil
+	}
+
+	ok, err := s.instances.UpdateStatus(sandbox.UUID, StatusDeleted, sandbox.Version)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	_, err = s.operations.UpdateError(operation.UUID, OperationCanceled, "OperationCanceled", "operation was canceled")
+
+	return err == nil, err
+}
+
+// cancelNotStarted marks the operation waiting for the sandbox as canceled
+func (s *AzureSandbox) cancelNotStarted(sandboxID string, kind string) {
+	operation, err := s.operations.GetNotStarted(sandboxID, kind)
+	if errors.Is(err, ErrOperationNotFound) {
+		return
+	}
+
+	if err == nil {
+		_, err = s.operations.UpdateError(operation.UUID, OperationCanceled, "OperationCanceled", "operation was canceled")
+	}
+
+	if err != nil {
+		log.Logger.Error("Failed to cancel operation", "sandbox", sandboxID, "kind", kind, "err", err)
+	}
+}
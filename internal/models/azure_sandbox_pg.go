@@ -2,8 +2,14 @@ package models
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -22,10 +28,86 @@ func NewAzureSandboxesPostgres(dbPool *pgxpool.Pool) *AzureSandboxPostgres {
 
 }
 
-func (s *AzureSandboxPostgres) Insert(name string, expireTime time.Time) (string, error) {
+// PostgreSQL error codes of the constraint violations
+const (
+	uniqueViolation = "23505"
+	checkViolation  = "23514"
+)
+
+// Columns of the sandbox record, in the order scanSandbox expects them
+const sandboxColumns = "s.id, s.name, s.created_at, s.updated_at, s.expires_at, s.status, s.version, s.owner, s.labels, " +
+	"s.description, s.cost_center, s.notes, s.start_at, s.keep_when_idle, s.idle_warned_at, s.role, s.template"
+
+// Columns to sort the sandbox listing by
+var sortColumns = map[string]string{
+	SortByCreatedAt: "s.created_at",
+	SortByUpdatedAt: "s.updated_at",
+	SortByExpiresAt: "s.expires_at",
+	SortByName:      "s.name",
+}
+
+func scanSandbox(row pgx.Row, sandbox *SandboxDetails) error {
+	return row.Scan(
+		&sandbox.UUID,
+		&sandbox.Name,
+		&sandbox.CreatedAt,
+		&sandbox.UpdatedAt,
+		&sandbox.ExpiresAt,
+		&sandbox.Status,
+		&sandbox.Version,
+		&sandbox.Owner,
+		&sandbox.Labels,
+		&sandbox.Description,
+		&sandbox.CostCenter,
+		&sandbox.Notes,
+		&sandbox.StartAt,
+		&sandbox.KeepWhenIdle,
+		&sandbox.IdleWarnedAt,
+		&sandbox.Role,
+		&sandbox.Template)
+}
+
+func scanSandboxes(rows pgx.Rows) ([]SandboxDetails, error) {
+	sandboxes := make([]SandboxDetails, 0)
+
+	for rows.Next() {
+		var sandbox SandboxDetails
+
+		err := scanSandbox(rows, &sandbox)
+		if err != nil {
+			return nil, err
+		}
+
+		sandboxes = append(sandboxes, sandbox)
+	}
+
+	return sandboxes, rows.Err()
+}
+
+// Helper to match the prefix with LIKE, the wildcards of the prefix are matched literally
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix) + "%"
+}
+
+func (s *AzureSandboxPostgres) Insert(name string, expireTime time.Time, startAt *time.Time, owner string, labels map[string]string, options SandboxOptions, pendingApproval bool) (string, error) {
 	id := ""
 
-	err := s.dbPool.QueryRow(context.Background(), "SELECT public.insert_sandbox($1, $2)", name, expireTime).Scan(&id)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	err := s.dbPool.QueryRow(context.Background(), "SELECT public.insert_sandbox($1, $2, $3, $4, $5, $6, $7, $8)",
+		name, expireTime, owner, labels, startAt, pendingApproval, options.Role, options.Template).Scan(&id)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == uniqueViolation && pgErr.ConstraintName == "sandboxes_active_name_idx":
+			return "", ErrNameTaken
+		case pgErr.Code == checkViolation && pgErr.ConstraintName == "sandboxes_name_check":
+			return "", NewFieldError("name", "is not a valid sandbox name")
+		}
+	}
 
 	return id, err
 }
@@ -38,81 +120,234 @@ func (s *AzureSandboxPostgres) Delete(id string) (bool, error) {
 	return ok, err
 }
 
-func (s *AzureSandboxPostgres) GetAll(limit int, offset int) ([]SandboxDetails, error) {
+// sandboxQuery collects the conditions and the arguments of the listing query
+type sandboxQuery struct {
+	conditions []string
+	args       []interface{}
+}
 
-	rows, err := s.dbPool.Query(context.Background(), "SELECT * FROM public.get_sandbox_all($1, $2)", limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+func (q *sandboxQuery) arg(value interface{}) string {
+	q.args = append(q.args, value)
 
-	sandboxes := make([]SandboxDetails, 0)
+	return "$" + strconv.Itoa(len(q.args))
+}
 
-	for rows.Next() {
-		var sandbox SandboxDetails
+func (q *sandboxQuery) where() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
 
-		err := rows.Scan(&sandbox.UUID, &sandbox.Name, &sandbox.CreatedAt, &sandbox.UpdatedAt, &sandbox.ExpiresAt, &sandbox.Status)
-		if err != nil {
-			return nil, err
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// selector adds the conditions of the label selector. The equality
+// requirements are combined into a single containment check, which is served
+// by the GIN index of the labels.
+func (q *sandboxQuery) selector(selector LabelSelector) {
+	equals := map[string]string{}
+
+	for _, requirement := range selector {
+		key := requirement.Key
+
+		switch requirement.Operator {
+		case SelectorEquals:
+			equals[key] = requirement.Values[0]
+		case SelectorNotEquals:
+			q.conditions = append(q.conditions, "NOT s.labels @> "+q.arg(map[string]string{key: requirement.Values[0]})+"::jsonb")
+		case SelectorIn:
+			q.conditions = append(q.conditions, "s.labels ->> "+q.arg(key)+" = ANY("+q.arg(requirement.Values)+"::text[])")
+		case SelectorNotIn:
+			q.conditions = append(q.conditions, "NOT coalesce(s.labels ->> "+q.arg(key)+" = ANY("+q.arg(requirement.Values)+"::text[]), false)")
+		case SelectorExists:
+			q.conditions = append(q.conditions, "s.labels ? "+q.arg(key))
+		case SelectorDoesNotExist:
+			q.conditions = append(q.conditions, "NOT s.labels ? "+q.arg(key))
 		}
+	}
 
-		sandboxes = append(sandboxes, sandbox)
+	if len(equals) > 0 {
+		q.conditions = append(q.conditions, "s.labels @> "+q.arg(equals)+"::jsonb")
 	}
+}
 
-	return sandboxes, nil
+// newSandboxQuery builds the conditions out of the filter, so the planner can
+// use the indexes matching them. The cursor and the paging are left out.
+func newSandboxQuery(filter SandboxFilter) *sandboxQuery {
+	q := &sandboxQuery{}
+
+	if len(filter.Statuses) > 0 {
+		q.conditions = append(q.conditions, "s.status = ANY("+q.arg(filter.Statuses)+"::text[]::public.status[])")
+	}
+	if filter.Owner != "" {
+		q.conditions = append(q.conditions, "s.owner = "+q.arg(filter.Owner))
+	}
+	if filter.Name != "" {
+		q.conditions = append(q.conditions, "s.name = "+q.arg(filter.Name))
+	}
+	if filter.NamePrefix != "" {
+		q.conditions = append(q.conditions, "s.name LIKE "+q.arg(likePrefix(filter.NamePrefix)))
+	}
+	q.selector(filter.Selector)
+	if filter.CreatedAfter != nil {
+		q.conditions = append(q.conditions, "s.created_at >= "+q.arg(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		q.conditions = append(q.conditions, "s.created_at < "+q.arg(*filter.CreatedBefore))
+	}
+	if filter.ExpiresAfter != nil {
+		q.conditions = append(q.conditions, "s.expires_at >= "+q.arg(*filter.ExpiresAfter))
+	}
+	if filter.ExpiresBefore != nil {
+		q.conditions = append(q.conditions, "s.expires_at < "+q.arg(*filter.ExpiresBefore))
+	}
+	if filter.ExpiringWithin > 0 {
+		q.conditions = append(q.conditions, "s.expires_at >= now() AND s.expires_at < now() + make_interval(secs => "+
+			q.arg(filter.ExpiringWithin.Seconds())+")")
+	}
+
+	return q
 }
 
-func (s *AzureSandboxPostgres) GetByName(name string) ([]SandboxDetails, error) {
+// GetAll returns a page of the sandboxes. The pages are either continued after
+// the cursor, comparing the (sort column, id) pairs, or skipped by the offset.
+func (s *AzureSandboxPostgres) GetAll(filter SandboxFilter) ([]SandboxDetails, error) {
+	q := newSandboxQuery(filter)
+
+	column := sortColumns[filter.SortBy]
 
-	rows, err := s.dbPool.Query(context.Background(), "SELECT * FROM public.get_sandbox_by_name($1)", name)
+	direction, comparison := "DESC", "<"
+	if filter.SortOrder == SortAsc {
+		direction, comparison = "ASC", ">"
+	}
+
+	if filter.After != nil {
+		value, err := filter.After.sortValue()
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+
+		q.conditions = append(q.conditions,
+			fmt.Sprintf("(%s, s.id) %s (%s, %s::uuid)", column, comparison, q.arg(value), q.arg(filter.After.ID)))
+	}
+
+	// The id makes the order stable for the sandboxes with the same sort value
+	query := fmt.Sprintf("SELECT %s FROM sandboxes s%s ORDER BY %s %s, s.id %s LIMIT %s OFFSET %s",
+		sandboxColumns, q.where(), column, direction, direction, q.arg(filter.Limit), q.arg(filter.Offset))
+
+	rows, err := s.dbPool.Query(context.Background(), query, q.args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	sandboxes := make([]SandboxDetails, 0)
+	return scanSandboxes(rows)
+}
 
-	for rows.Next() {
-		var sandbox SandboxDetails
+func (s *AzureSandboxPostgres) Count(filter SandboxFilter) (int, error) {
+	count := 0
 
-		err := rows.Scan(&sandbox.UUID, &sandbox.Name, &sandbox.CreatedAt, &sandbox.UpdatedAt, &sandbox.ExpiresAt, &sandbox.Status)
-		if err != nil {
-			return nil, err
-		}
+	q := newSandboxQuery(filter)
 
-		sandboxes = append(sandboxes, sandbox)
-	}
+	err := s.dbPool.QueryRow(context.Background(), "SELECT count(*) FROM sandboxes s"+q.where(), q.args...).Scan(&count)
 
-	return sandboxes, nil
+	return count, err
 }
 
 func (s *AzureSandboxPostgres) GetByID(id string) (SandboxDetails, error) {
 
 	sandbox := SandboxDetails{}
 
-	err := s.dbPool.QueryRow(context.Background(), "SELECT * FROM public.get_sandbox_by_id($1)", id).Scan(
-		&sandbox.UUID,
-		&sandbox.Name,
-		&sandbox.CreatedAt,
-		&sandbox.UpdatedAt,
-		&sandbox.ExpiresAt,
-		&sandbox.Status)
+	err := scanSandbox(s.dbPool.QueryRow(context.Background(), "SELECT * FROM public.get_sandbox_by_id($1)", id), &sandbox)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return SandboxDetails{}, ErrSandboxNotFound
+	}
 
 	return sandbox, err
 }
 
-func (s *AzureSandboxPostgres) UpdateExpiration(id string, expiresAt time.Time) (bool, error) {
+func (s *AzureSandboxPostgres) GetActiveByName(name string) (SandboxDetails, error) {
+
+	sandbox := SandboxDetails{}
+
+	err := scanSandbox(s.dbPool.QueryRow(context.Background(), "SELECT * FROM public.get_active_sandbox_by_name($1)", name), &sandbox)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return SandboxDetails{}, ErrSandboxNotFound
+	}
+
+	return sandbox, err
+}
+
+// Helper to pass the expected version, 0 is passed as NULL to skip the check
+func nullableVersion(version int) *int {
+	if version == 0 {
+		return nil
+	}
+
+	return &version
+}
+
+// Update applies the patch and records the changed fields in the history
+func (s *AzureSandboxPostgres) Update(id string, patch SandboxPatch, actor string, version int) (bool, error) {
+	ok := false
+
+	// NULL leaves the labels as they are, a nil map would be encoded as JSON null
+	var labels interface{}
+	if len(patch.Labels) > 0 {
+		labels = patch.Labels
+	}
+
+	err := s.dbPool.QueryRow(context.Background(), "SELECT public.update_sandbox_metadata($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		id, actor, patch.ExpiresAt, patch.Description, patch.CostCenter, patch.Notes, labels, patch.KeepWhenIdle,
+		nullableVersion(version)).Scan(&ok)
+
+	return ok, err
+}
+
+func (s *AzureSandboxPostgres) UpdateStatus(id string, status string, version int) (bool, error) {
 	ok := false
 
-	err := s.dbPool.QueryRow(context.Background(), "SELECT public.update_sandbox_expires_at($1, $2)", id, expiresAt).Scan(&ok)
+	err := s.dbPool.QueryRow(context.Background(), "SELECT public.update_sandbox_status($1, $2, $3)",
+		id, status, nullableVersion(version)).Scan(&ok)
 
 	return ok, err
 }
 
-func (s *AzureSandboxPostgres) UpdateStatus(id string, status string) (bool, error) {
+func (s *AzureSandboxPostgres) UpdateIdleWarning(id string, warnedAt *time.Time, actor string) (bool, error) {
 	ok := false
 
-	err := s.dbPool.QueryRow(context.Background(), "SELECT public.update_sandbox_status($1, $2)", id, status).Scan(&ok)
+	err := s.dbPool.QueryRow(context.Background(), "SELECT public.update_sandbox_idle_warning($1, $2, $3)",
+		id, warnedAt, actor).Scan(&ok)
 
 	return ok, err
 }
+
+func (s *AzureSandboxPostgres) ExpireIdle(id string, warnedAt time.Time, actor string) (bool, error) {
+	ok := false
+
+	err := s.dbPool.QueryRow(context.Background(), "SELECT public.expire_idle_sandbox($1, $2, $3)",
+		id, warnedAt, actor).Scan(&ok)
+
+	return ok, err
+}
+
+func (s *AzureSandboxPostgres) ClaimScheduled(limit int, claimTimeout time.Duration) ([]string, error) {
+	rows, err := s.dbPool.Query(context.Background(), "SELECT * FROM public.claim_scheduled_sandboxes($1, $2)",
+		limit, int(claimTimeout.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,209 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeSandboxData holds a single sandbox, the status moves check the version
+// like the database does. The rest of the interface is not used.
+type fakeSandboxData struct {
+	SandboxData
+	sandbox  SandboxDetails
+	statuses []string
+	updates  []SandboxPatch
+}
+
+func (f *fakeSandboxData) GetByID(id string) (SandboxDetails, error) {
+	if id != f.sandbox.UUID {
+		return SandboxDetails{}, ErrSandboxNotFound
+	}
+
+	return f.sandbox, nil
+}
+
+func (f *fakeSandboxData) UpdateStatus(id string, status string, version int) (bool, error) {
+	if id != f.sandbox.UUID || (version != 0 && version != f.sandbox.Version) {
+		return false, nil
+	}
+
+	f.sandbox.Status = status
+	f.sandbox.Version++
+	f.statuses = append(f.statuses, status)
+
+	return true, nil
+}
+
+func (f *fakeSandboxData) Update(id string, patch SandboxPatch, actor string, version int) (bool, error) {
+	if id != f.sandbox.UUID || (version != 0 && version != f.sandbox.Version) {
+		return false, nil
+	}
+
+	f.sandbox.Version++
+	f.updates = append(f.updates, patch)
+
+	return true, nil
+}
+
+// failingOperationData fails to record the operations, so nothing runs in the
+// background
+type failingOperationData struct {
+	OperationData
+}
+
+func (failingOperationData) Insert(sandboxID string, kind string) (string, error) {
+	return "", errors.New("database is down")
+}
+
+const testSandboxID = "0b3f1e2c-6a8d-4f5e-9c7b-1d2e3f4a5b6c"
+
+func TestStopMovesToStopping(t *testing.T) {
+	owner := Principal{Subject: "alice", Permissions: []string{PermissionWrite, PermissionRead}}
+
+	data := &fakeSandboxData{sandbox: SandboxDetails{UUID: testSandboxID, Status: StatusRunning, Version: 1, Owner: "alice"}}
+	s := &AzureSandbox{instances: data, operations: failingOperationData{}}
+
+	if _, err := s.Stop(testSandboxID, owner); err == nil {
+		t.Fatal("Stop() error = nil, want the failure to record the operation")
+	}
+
+	// The sandbox is STOPPING while the operation is started, and back
+	// RUNNING once it couldn't be
+	want := []string{StatusStopping, StatusRunning}
+	if len(data.statuses) != len(want) || data.statuses[0] != want[0] || data.statuses[1] != want[1] {
+		t.Errorf("Stop() moved the sandbox through %v, want %v", data.statuses, want)
+	}
+
+	// The second stop sees the sandbox being stopped
+	data.sandbox.Status = StatusStopping
+	data.statuses = nil
+
+	if _, err := s.Stop(testSandboxID, owner); !errors.Is(err, ErrWrongStatus) {
+		t.Errorf("Stop() of the stopping sandbox error = %v, want %v", err, ErrWrongStatus)
+	}
+	if len(data.statuses) != 0 {
+		t.Errorf("Stop() of the stopping sandbox moved it through %v", data.statuses)
+	}
+}
+
+func TestUpdateLabelsBeforeProvisioning(t *testing.T) {
+	owner := Principal{Subject: "alice", Permissions: []string{PermissionWrite, PermissionRead}}
+	team := "platform"
+	startAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		status string
+		// The scheduler claims the sandbox between the read and the update
+		claimed bool
+		err     error
+	}{
+		{"pending", StatusPending, false, ErrWrongStatus},
+		{"scheduled and claimed", StatusScheduled, true, ErrWrongStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &fakeSandboxData{sandbox: SandboxDetails{
+				UUID:      testSandboxID,
+				Status:    tt.status,
+				Version:   1,
+				Owner:     "alice",
+				ExpiresAt: startAt.Add(time.Hour),
+				StartAt:   &startAt,
+			}}
+			s := &AzureSandbox{instances: &claimingSandboxData{fakeSandboxData: data, claim: tt.claimed}}
+
+			_, _, err := s.Update(testSandboxID, SandboxPatch{Labels: map[string]*string{"team": &team}}, owner, 0)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.err)
+			}
+
+			if len(data.updates) != 0 {
+				t.Errorf("Update() applied %+v", data.updates)
+			}
+		})
+	}
+}
+
+// claimingSandboxData moves the sandbox on to PENDING before the update, as
+// the scheduler would
+type claimingSandboxData struct {
+	*fakeSandboxData
+	claim bool
+}
+
+func (c *claimingSandboxData) Update(id string, patch SandboxPatch, actor string, version int) (bool, error) {
+	if c.claim {
+		c.sandbox.Status = StatusPending
+		c.sandbox.Version++
+	}
+
+	return c.fakeSandboxData.Update(id, patch, actor, version)
+}
+
+// recordingSandboxData keeps the times passed to the store and fails the
+// insert, so nothing else is done
+type recordingSandboxData struct {
+	SandboxData
+	expireTime time.Time
+	startAt    *time.Time
+	filter     SandboxFilter
+}
+
+func (r *recordingSandboxData) Insert(name string, expireTime time.Time, startAt *time.Time, owner string, labels map[string]string, options SandboxOptions, pendingApproval bool) (string, error) {
+	r.expireTime, r.startAt = expireTime, startAt
+
+	return "", errors.New("not stored")
+}
+
+func (r *recordingSandboxData) GetAll(filter SandboxFilter) ([]SandboxDetails, error) {
+	r.filter = filter
+
+	return nil, nil
+}
+
+func TestTimesStoredInUTC(t *testing.T) {
+	berlin := time.FixedZone("UTC+2", 2*60*60)
+	startAt := time.Now().Add(time.Hour).In(berlin)
+	expiresAt := startAt.Add(2 * time.Hour)
+
+	data := &recordingSandboxData{}
+	s := &AzureSandbox{instances: data, policy: ApprovalPolicy{MaxLifetime: DefaultMaxLifetime}}
+
+	_, _, _ = s.Create("utc", expiresAt, &startAt, Principal{Subject: "alice"}, nil, SandboxOptions{})
+
+	if data.expireTime.Location() != time.UTC || !data.expireTime.Equal(expiresAt) {
+		t.Errorf("Create() stored expiresAt %v, want %v", data.expireTime, expiresAt.UTC())
+	}
+	if data.startAt == nil || data.startAt.Location() != time.UTC || !data.startAt.Equal(startAt) {
+		t.Errorf("Create() stored startAt %v, want %v", data.startAt, startAt.UTC())
+	}
+
+	createdAfter := time.Date(2024, 5, 1, 9, 0, 0, 0, berlin)
+	if _, err := s.ListAll(SandboxFilter{CreatedAfter: &createdAfter}); err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+
+	if got := data.filter.CreatedAfter; got == nil || got.Location() != time.UTC || got.Hour() != 7 {
+		t.Errorf("ListAll() filtered by createdAfter %v, want 07:00 UTC", got)
+	}
+}
+
+func TestUpdateStoresExpiresAtInUTC(t *testing.T) {
+	berlin := time.FixedZone("UTC+2", 2*60*60)
+	expiresAt := time.Now().Add(time.Hour).In(berlin)
+
+	data := &fakeSandboxData{sandbox: SandboxDetails{UUID: testSandboxID, Status: StatusRunning, Version: 1, Owner: "alice"}}
+	s := &AzureSandbox{instances: data, operations: failingOperationData{}, policy: ApprovalPolicy{MaxLifetime: DefaultMaxLifetime}}
+
+	_, _, _ = s.Update(testSandboxID, SandboxPatch{ExpiresAt: &expiresAt}, Principal{Subject: "alice"}, 0)
+
+	if len(data.updates) != 1 {
+		t.Fatalf("Update() applied %d patches, want 1", len(data.updates))
+	}
+	if got := data.updates[0].ExpiresAt; got.Location() != time.UTC || !got.Equal(expiresAt) {
+		t.Errorf("Update() stored expiresAt %v, want %v", got, expiresAt.UTC())
+	}
+}
@@ -0,0 +1,209 @@
+package models
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Actions of the batch items
+const (
+	BatchCreate = "create"
+	BatchExtend = "extend"
+	BatchDelete = "delete"
+)
+
+const (
+	// MaxBatchItems limits the items of a single batch, including the
+	// sandboxes matched by the selector
+	MaxBatchItems = 500
+	// BatchConcurrency is the number of the items run at once, so a large
+	// batch doesn't flood the database and Azure
+	BatchConcurrency = 8
+)
+
+// BatchItem is a single action of the batch. ID is used by the extend and
+// delete actions, Name and Labels by the create action.
+type BatchItem struct {
+	Action    string
+	ID        string
+	Name      string
+	ExpiresAt time.Time
+	StartAt   *time.Time
+	Labels    map[string]string
+	Options   SandboxOptions
+}
+
+// BatchResult is the outcome of the item, Err is nil if the operation is
+// started, or in the dry run, if the item would be accepted
+type BatchResult struct {
+	Item      BatchItem
+	SandboxID string
+	Operation OperationDetails
+	Err       error
+}
+
+// SelectBatchItems turns the sandboxes matching the filter into the items
+// with the same action. The filter without any condition is rejected, so a
+// forgotten filter doesn't select every sandbox.
+func SelectBatchItems(controller SandboxController, filter SandboxFilter, action string, expiresAt time.Time) ([]BatchItem, error) {
+	errs := fieldErrors{}
+
+	if len(filter.Statuses) == 0 && filter.Owner == "" && filter.NamePrefix == "" && len(filter.Selector) == 0 {
+		errs.addField("selector", "must set at least one filter")
+	}
+
+	switch {
+	case action != BatchExtend && action != BatchDelete:
+		errs.addField("action", "must be extend or delete")
+	case action == BatchExtend && expiresAt.IsZero():
+		errs.addField("expiresAt", "is required by the extend action")
+	case action == BatchExtend:
+		errs.add(validateExpiresAt(expiresAt))
+	}
+
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	filter.Limit = MaxListLimit
+	filter.SortBy = SortByCreatedAt
+	filter.SortOrder = SortAsc
+
+	items := []BatchItem{}
+
+	for {
+		page, err := controller.ListAll(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sandbox := range page.Sandboxes {
+			// Deleted sandboxes are history, selecting them would only fail
+			if sandbox.Status == StatusDeleted || sandbox.Status == StatusDeleting {
+				continue
+			}
+
+			items = append(items, BatchItem{Action: action, ID: sandbox.UUID, ExpiresAt: expiresAt})
+		}
+
+		if len(items) > MaxBatchItems {
+			return nil, NewFieldError("selector", "matches more than 500 sandboxes")
+		}
+
+		if page.Next == nil {
+			return items, nil
+		}
+
+		filter.After = page.Next
+	}
+}
+
+// RunBatch runs the items on the controller, at most BatchConcurrency at
+// once. The results are in the order of the items. In the dry run the items
+// are only checked. The items are run on behalf of the principal.
+func RunBatch(controller SandboxController, items []BatchItem, principal Principal, dryRun bool) []BatchResult {
+	results := make([]BatchResult, len(items))
+
+	semaphore := make(chan struct{}, BatchConcurrency)
+	wg := sync.WaitGroup{}
+
+	for i, item := range items {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(i int, item BatchItem) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			if dryRun {
+				results[i] = checkBatchItem(controller, item)
+			} else {
+				results[i] = runBatchItem(controller, item, principal)
+			}
+		}(i, item)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func runBatchItem(controller SandboxController, item BatchItem, principal Principal) BatchResult {
+	result := BatchResult{Item: item, SandboxID: item.ID}
+
+	switch item.Action {
+	case BatchCreate:
+		var sandbox SandboxDetails
+		sandbox, result.Operation, result.Err = controller.Create(item.Name, item.ExpiresAt, item.StartAt, principal, item.Labels, item.Options)
+		result.SandboxID = sandbox.UUID
+	case BatchExtend:
+		_, result.Operation, result.Err = controller.Update(item.ID, SandboxPatch{ExpiresAt: &item.ExpiresAt}, principal, 0)
+	case BatchDelete:
+		result.Operation, result.Err = controller.Remove(item.ID, principal, 0)
+	default:
+		result.Err = NewFieldError("action", "must be create, extend or delete")
+	}
+
+	return result
+}
+
+// checkBatchItem reports the errors the item would fail with, as far as they
+// can be told without running it
+func checkBatchItem(controller SandboxController, item BatchItem) BatchResult {
+	result := BatchResult{Item: item, SandboxID: item.ID}
+
+	switch item.Action {
+	case BatchCreate:
+		errs := fieldErrors{}
+		errs.add(ValidateName(item.Name))
+		errs.add(validateExpiresAt(item.ExpiresAt))
+		errs.add(validateLabels(item.Labels))
+		validateOptions(&errs, item.Options)
+		if item.StartAt != nil {
+			errs.add(validateStartAt(*item.StartAt, item.ExpiresAt))
+		}
+		if result.Err = errs.err(); result.Err != nil {
+			return result
+		}
+
+		holder, err := controller.ResolveByName(item.Name)
+		switch {
+		case err == nil && holder.Status == StatusDeleting:
+			result.Err = ErrNameDeleting
+		case err == nil:
+			result.Err = ErrNameTaken
+		case !errors.Is(err, ErrSandboxNotFound):
+			result.Err = err
+		}
+	case BatchExtend:
+		if result.Err = validateExpiresAt(item.ExpiresAt); result.Err != nil {
+			return result
+		}
+
+		result.Err = checkBatchSandbox(controller, item.ID, []string{StatusDeleted, StatusDeleting}, ErrAlreadyDeleted)
+	case BatchDelete:
+		result.Err = checkBatchSandbox(controller, item.ID, []string{StatusDeleted, StatusDeleting, StatusPending, StatusStopping, StatusStarting}, ErrWrongStatus)
+	default:
+		result.Err = NewFieldError("action", "must be create, extend or delete")
+	}
+
+	return result
+}
+
+// Helper to check the sandbox exists and is not in one of the statuses, the
+// error is the one the controller would fail with
+func checkBatchSandbox(controller SandboxController, id string, notAllowed []string, statusErr error) error {
+	sandbox, err := controller.GetByUUID(id)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range notAllowed {
+		if sandbox.Status == status {
+			return statusErr
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSelectBatchItemsRejected(t *testing.T) {
+	teams := SandboxFilter{Selector: LabelSelector{{Key: "team", Operator: SelectorEquals, Values: []string{"payments"}}}}
+
+	tests := []struct {
+		name      string
+		filter    SandboxFilter
+		action    string
+		expiresAt time.Time
+		fields    []string
+	}{
+		{"empty selector", SandboxFilter{}, BatchDelete, time.Time{}, []string{"selector"}},
+		{"unknown action", teams, BatchCreate, time.Time{}, []string{"action"}},
+		{"extend without expiresAt", teams, BatchExtend, time.Time{}, []string{"expiresAt"}},
+		{"extend to the past", teams, BatchExtend, time.Now().Add(-time.Hour), []string{"expiresAt"}},
+		{"empty selector and action", SandboxFilter{}, "", time.Time{}, []string{"selector", "action"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The request is rejected before any sandbox is listed
+			_, err := SelectBatchItems(nil, tt.filter, tt.action, tt.expiresAt)
+
+			var domainErr *Error
+			if !errors.As(err, &domainErr) || domainErr.Kind != KindValidation {
+				t.Fatalf("SelectBatchItems() error = %v, want validation error", err)
+			}
+
+			if len(domainErr.Fields) != len(tt.fields) {
+				t.Fatalf("SelectBatchItems() fields = %v, want %v", domainErr.Fields, tt.fields)
+			}
+			for i, field := range tt.fields {
+				if domainErr.Fields[i].Field != field {
+					t.Errorf("SelectBatchItems() fields = %v, want %v", domainErr.Fields, tt.fields)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// Cursor is the position in the sandbox listing, the next page starts right
+// after the sandbox the cursor points to. It is sent to the clients as an
+// opaque token.
+type Cursor struct {
+	SortBy    string `json:"s"`
+	SortOrder string `json:"o"`
+	Value     string `json:"v"`
+	ID        string `json:"id"`
+}
+
+// cursorAfter points the cursor to the sandbox
+func cursorAfter(sandbox SandboxDetails, sortBy string, sortOrder string) Cursor {
+	cursor := Cursor{
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
+		ID:        sandbox.UUID,
+	}
+
+	switch sortBy {
+	case SortByUpdatedAt:
+		cursor.Value = sandbox.UpdatedAt.Format(time.RFC3339Nano)
+	case SortByExpiresAt:
+		cursor.Value = sandbox.ExpiresAt.Format(time.RFC3339Nano)
+	case SortByName:
+		cursor.Value = sandbox.Name
+	default:
+		cursor.Value = sandbox.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	return cursor
+}
+
+// sortValue returns the cursor value in the type of the sort column
+func (c Cursor) sortValue() (interface{}, error) {
+	if c.SortBy == SortByName {
+		return c.Value, nil
+	}
+
+	return time.Parse(time.RFC3339Nano, c.Value)
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(token string) (Cursor, error) {
+	cursor := Cursor{}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	if err := validateID(cursor.ID); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	if _, err := cursor.sortValue(); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
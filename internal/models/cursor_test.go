@@ -0,0 +1,95 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorAfter(t *testing.T) {
+	created := time.Date(2023, 5, 1, 10, 0, 0, 123456789, time.UTC)
+	updated := created.Add(time.Hour)
+	expires := created.Add(24 * time.Hour)
+
+	sandbox := SandboxDetails{
+		UUID:      "0c5e8c8a-5f43-4a4c-9a1e-7d0b1f2b3c4d",
+		Name:      "workshop-1",
+		CreatedAt: created,
+		UpdatedAt: updated,
+		ExpiresAt: expires,
+	}
+
+	tests := []struct {
+		sortBy string
+		value  string
+	}{
+		{SortByCreatedAt, created.Format(time.RFC3339Nano)},
+		{SortByUpdatedAt, updated.Format(time.RFC3339Nano)},
+		{SortByExpiresAt, expires.Format(time.RFC3339Nano)},
+		{SortByName, "workshop-1"},
+		{"", created.Format(time.RFC3339Nano)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			cursor := cursorAfter(sandbox, tt.sortBy, SortAsc)
+
+			if cursor.Value != tt.value {
+				t.Errorf("value = %q, want %q", cursor.Value, tt.value)
+			}
+			if cursor.ID != sandbox.UUID || cursor.SortBy != tt.sortBy || cursor.SortOrder != SortAsc {
+				t.Errorf("cursor = %+v doesn't point to the sandbox", cursor)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []Cursor{
+		{SortBy: SortByCreatedAt, SortOrder: SortDesc, Value: "2023-05-01T10:00:00.123456789Z", ID: "0c5e8c8a-5f43-4a4c-9a1e-7d0b1f2b3c4d"},
+		{SortBy: SortByName, SortOrder: SortAsc, Value: "workshop-1", ID: "0c5e8c8a-5f43-4a4c-9a1e-7d0b1f2b3c4d"},
+		{SortBy: SortByName, SortOrder: SortAsc, Value: "", ID: "0c5e8c8a-5f43-4a4c-9a1e-7d0b1f2b3c4d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.SortBy+"/"+tt.Value, func(t *testing.T) {
+			decoded, err := DecodeCursor(tt.Encode())
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+
+			if decoded != tt {
+				t.Errorf("DecodeCursor() = %+v, want %+v", decoded, tt)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "%%%"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"s":"name","o":"asc","v":"a","id":"0c5e8c8a-5f43-4a4c-9a1e-7d0b1f2b3c4d"}`))},
+		{"not json", encode("not json")},
+		{"missing id", encode(`{"s":"name","o":"asc","v":"a"}`)},
+		{"invalid id", encode(`{"s":"name","o":"asc","v":"a","id":"1"}`)},
+		{"invalid time", encode(`{"s":"createdAt","o":"asc","v":"yesterday","id":"0c5e8c8a-5f43-4a4c-9a1e-7d0b1f2b3c4d"}`)},
+		{"empty", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.token)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor() error = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package models
+
+import "github.com/google/uuid"
+
+// ErrorKind classifies errors of the models layer, so the callers can react on
+// them without parsing the messages
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindQuotaExceeded
+	KindUnauthorized
+	KindPreconditionFailed
+)
+
+// Error is a domain error. Code and Message are safe to be shown to the API
+// clients, the wrapped error is for the logs only.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// FieldError points to the invalid field of the request
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches domain errors by the code, so the wrapped copies of the errors
+// below are equal to the originals
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	return e.Kind == t.Kind && e.Code == t.Code
+}
+
+// Wrap returns a copy of the error with the underlying cause attached
+func (e *Error) Wrap(err error) *Error {
+	return &Error{
+		Kind:    e.Kind,
+		Code:    e.Code,
+		Message: e.Message,
+		Fields:  e.Fields,
+		Err:     err,
+	}
+}
+
+var (
+	ErrSandboxNotFound     = &Error{Kind: KindNotFound, Code: "SandboxNotFound", Message: "sandbox not found"}
+	ErrOperationNotFound   = &Error{Kind: KindNotFound, Code: "OperationNotFound", Message: "operation not found"}
+	ErrApprovalNotFound    = &Error{Kind: KindNotFound, Code: "ApprovalNotFound", Message: "approval request not found"}
+	ErrApprovalDecided     = &Error{Kind: KindConflict, Code: "ApprovalDecided", Message: "approval request is already decided"}
+	ErrSelfApproval        = &Error{Kind: KindUnauthorized, Code: "SelfApproval", Message: "requester can not decide on their own request"}
+	ErrNotOwner            = &Error{Kind: KindUnauthorized, Code: "NotOwner", Message: "sandbox is owned by someone else"}
+	ErrAnonymousApprover   = &Error{Kind: KindUnauthorized, Code: "AnonymousApprover", Message: "token has no subject to record the decision for"}
+	ErrAPITokenNotFound    = &Error{Kind: KindNotFound, Code: "APITokenNotFound", Message: "API token not found"}
+	ErrInvalidAPIToken     = &Error{Kind: KindUnauthorized, Code: "InvalidAPIToken", Message: "API token is unknown, revoked or expired"}
+	ErrAPITokenNotAllowed  = &Error{Kind: KindUnauthorized, Code: "APITokenNotAllowed", Message: "API tokens can not be managed with an API token"}
+	ErrAnonymousTokenOwner = &Error{Kind: KindUnauthorized, Code: "AnonymousTokenOwner", Message: "token has no subject to own the API token"}
+	ErrScopeNotGranted     = &Error{Kind: KindUnauthorized, Code: "ScopeNotGranted", Message: "API token can not be granted the scopes the caller doesn't have"}
+	ErrAPITokenLimit       = &Error{Kind: KindQuotaExceeded, Code: "APITokenLimit", Message: "too many active API tokens, revoke some first"}
+	ErrPendingCreateLimit  = &Error{Kind: KindQuotaExceeded, Code: "PendingCreateLimit", Message: "too many sandboxes are being created, retry once some of them are ready"}
+	ErrIdempotencyNotFound = &Error{Kind: KindNotFound, Code: "IdempotencyKeyNotFound", Message: "idempotency key not found or expired"}
+	ErrScheduleNotFound    = &Error{Kind: KindNotFound, Code: "ScheduleNotFound", Message: "sandbox has no schedule"}
+	ErrScheduleInherited   = &Error{Kind: KindConflict, Code: "ScheduleInherited", Message: "schedule is inherited from the catalog, change the catalog schedule instead"}
+	ErrAdminRequired       = &Error{Kind: KindUnauthorized, Code: "AdminRequired", Message: "action requires the sandbox:admin scope"}
+	ErrNameTaken           = &Error{Kind: KindConflict, Code: "SandboxNameTaken", Message: "sandbox with the same name already exists"}
+	ErrNameDeleting        = &Error{Kind: KindConflict, Code: "SandboxNameDeleting", Message: "sandbox with the same name is being deleted, retry once it is gone"}
+	ErrWrongStatus         = &Error{Kind: KindConflict, Code: "WrongStatus", Message: "action is not allowed in the current sandbox status"}
+	ErrAlreadyDeleted      = &Error{Kind: KindConflict, Code: "AlreadyDeleted", Message: "sandbox is already deleted"}
+	ErrScheduleChanged     = &Error{Kind: KindConflict, Code: "ScheduleChanged", Message: "schedule was changed meanwhile, retry the request"}
+	ErrNotCancelable       = &Error{Kind: KindConflict, Code: "OperationNotCancelable", Message: "operation can not be canceled"}
+	ErrInvalidID           = &Error{Kind: KindValidation, Code: "InvalidID", Message: "id is not a valid UUID"}
+	ErrInvalidCursor       = &Error{Kind: KindValidation, Code: "InvalidCursor", Message: "cursor is malformed or doesn't match the sort order"}
+	ErrPreconditionFailed  = &Error{Kind: KindPreconditionFailed, Code: "PreconditionFailed", Message: "sandbox was modified, version does not match"}
+)
+
+// NewValidationError creates the validation error with a specific message
+func NewValidationError(message string) *Error {
+	return &Error{Kind: KindValidation, Code: "ValidationFailed", Message: message}
+}
+
+// NewFieldError creates the validation error of a single field
+func NewFieldError(field string, message string) *Error {
+	return &Error{
+		Kind:    KindValidation,
+		Code:    "ValidationFailed",
+		Message: field + " " + message,
+		Fields:  []FieldError{{Field: field, Message: message}},
+	}
+}
+
+// Helper to check the id before it gets to the database
+func validateID(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrInvalidID
+	}
+
+	return nil
+}
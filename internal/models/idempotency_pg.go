@@ -0,0 +1,66 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Make sure we conform to the IdempotencyData interface
+var _ IdempotencyData = (*IdempotencyPostgres)(nil)
+
+type IdempotencyPostgres struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewIdempotencyPostgres(dbPool *pgxpool.Pool) *IdempotencyPostgres {
+
+	return &IdempotencyPostgres{
+		dbPool: dbPool,
+	}
+}
+
+func (i *IdempotencyPostgres) Reserve(owner string, key string, requestHash string, ttl time.Duration, lockTimeout time.Duration) (bool, error) {
+	ok := false
+
+	err := i.dbPool.QueryRow(context.Background(), "SELECT public.reserve_idempotency_key($1, $2, $3, $4, $5)",
+		owner, key, requestHash, int(ttl.Seconds()), int(lockTimeout.Seconds())).Scan(&ok)
+
+	return ok, err
+}
+
+func (i *IdempotencyPostgres) Get(owner string, key string) (IdempotencyRecord, error) {
+	record := IdempotencyRecord{}
+
+	err := i.dbPool.QueryRow(context.Background(), "SELECT * FROM public.get_idempotency_key($1, $2)", owner, key).Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.Headers,
+		&record.Body)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return IdempotencyRecord{}, ErrIdempotencyNotFound
+	}
+
+	return record, err
+}
+
+func (i *IdempotencyPostgres) Complete(owner string, key string, statusCode int, headers map[string][]string, body []byte) (bool, error) {
+	ok := false
+
+	err := i.dbPool.QueryRow(context.Background(), "SELECT public.complete_idempotency_key($1, $2, $3, $4, $5)",
+		owner, key, statusCode, headers, body).Scan(&ok)
+
+	return ok, err
+}
+
+func (i *IdempotencyPostgres) Release(owner string, key string) (bool, error) {
+	ok := false
+
+	err := i.dbPool.QueryRow(context.Background(), "SELECT public.release_idempotency_key($1, $2)", owner, key).Scan(&ok)
+
+	return ok, err
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is the stored outcome of a request made with an Idempotency-Key
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	StatusCode  int // 0 while the original request is in flight
+	Headers     map[string][]string
+	Body        []byte
+}
+
+// IdempotencyData stores the records by the owner, the caller of the request,
+// and the key
+type IdempotencyData interface {
+	// Reserve takes the key for the ttl. The key of the request still in flight
+	// after the lockTimeout can be taken over, the request is considered lost.
+	Reserve(owner string, key string, requestHash string, ttl time.Duration, lockTimeout time.Duration) (bool, error)
+	// Get fails with ErrIdempotencyNotFound once the key is expired
+	Get(owner string, key string) (IdempotencyRecord, error)
+	Complete(owner string, key string, statusCode int, headers map[string][]string, body []byte) (bool, error)
+	Release(owner string, key string) (bool, error)
+}
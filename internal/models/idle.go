@@ -0,0 +1,203 @@
+package models
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+)
+
+// Actor of the history records made by the idle detector
+const idleDetectorActor = "idle-detector"
+
+// ActivityProvider reports the write operations on the resource groups of the
+// sandboxes, e.g. from the Azure activity log
+type ActivityProvider interface {
+	// LastWrite returns the time of the latest write operation on the
+	// resource group since the given time, nil if there was none
+	LastWrite(ctx context.Context, resourceGroup string, since time.Time) (*time.Time, error)
+}
+
+// IdleConfig configures the idle detector. The sandboxes without writes for
+// the Threshold are warned first, and expired if they are still idle after
+// the WarningPeriod.
+type IdleConfig struct {
+	Interval      time.Duration
+	Threshold     time.Duration
+	WarningPeriod time.Duration
+}
+
+// RunIdleDetector checks the running and the stopped sandboxes for activity
+// every interval until the context is done
+func (s *AzureSandbox) RunIdleDetector(ctx context.Context, provider ActivityProvider, config IdleConfig) {
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkIdle(ctx, provider, config)
+		}
+	}
+}
+
+func (s *AzureSandbox) checkIdle(ctx context.Context, provider ActivityProvider, config IdleConfig) {
+	filter := SandboxFilter{
+		Statuses:  []string{StatusRunning, StatusStopped},
+		SortBy:    SortByCreatedAt,
+		SortOrder: SortAsc,
+		Limit:     MaxListLimit,
+	}
+
+	for {
+		page, err := s.ListAll(filter)
+		if err != nil {
+			log.Logger.Error("Failed to list sandboxes for idle check", "err", err)
+			return
+		}
+
+		for _, sandbox := range page.Sandboxes {
+			if ctx.Err() != nil {
+				return
+			}
+
+			s.checkSandboxIdle(ctx, provider, config, sandbox)
+		}
+
+		if page.Next == nil {
+			return
+		}
+
+		filter.After = page.Next
+	}
+}
+
+// checkSandboxIdle warns the owner of the idle sandbox, expires the sandbox
+// which is still idle after the warning period and clears the warning of the
+// sandbox which is active again
+func (s *AzureSandbox) checkSandboxIdle(ctx context.Context, provider ActivityProvider, config IdleConfig, sandbox SandboxDetails) {
+	if sandbox.KeepWhenIdle {
+		return
+	}
+
+	now := time.Now().UTC()
+	idleSince := now.Add(-config.Threshold)
+
+	// A new sandbox is not idle, even if nothing is written to it yet
+	if sandbox.CreatedAt.After(idleSince) {
+		return
+	}
+
+	// The resource group is named after the sandbox
+	lastWrite, err := provider.LastWrite(ctx, sandbox.Name, idleSince)
+	if err != nil {
+		log.Logger.Error("Failed to get activity of sandbox", "id", sandbox.UUID, "err", err)
+		return
+	}
+
+	switch {
+	case lastWrite != nil && sandbox.IdleWarnedAt != nil:
+		if _, err := s.instances.UpdateIdleWarning(sandbox.UUID, nil, idleDetectorActor); err != nil {
+			log.Logger.Error("Failed to clear idle warning of sandbox", "id", sandbox.UUID, "err", err)
+			return
+		}
+
+		log.Logger.Info("Sandbox is active again", "id", sandbox.UUID, "lastWrite", *lastWrite)
+	case lastWrite != nil:
+		// Active, nothing to do
+	case sandbox.IdleWarnedAt == nil:
+		ok, err := s.instances.UpdateIdleWarning(sandbox.UUID, &now, idleDetectorActor)
+		if err != nil {
+			log.Logger.Error("Failed to warn about idle sandbox", "id", sandbox.UUID, "err", err)
+			return
+		}
+
+		if ok {
+			expiresAt := now.Add(config.WarningPeriod)
+
+			log.Logger.Warn("Sandbox is idle and will be expired unless used or kept",
+				"id", sandbox.UUID, "owner", sandbox.Owner, "expiresAt", expiresAt)
+
+			s.notify(Notification{
+				Event:       EventSandboxIdle,
+				SandboxID:   sandbox.UUID,
+				SandboxName: sandbox.Name,
+				Owner:       sandbox.Owner,
+				Actor:       idleDetectorActor,
+				ExpiresAt:   &expiresAt,
+			})
+		}
+	case !sandbox.IdleWarnedAt.After(now.Add(-config.WarningPeriod)):
+		s.expireIdle(sandbox)
+	}
+}
+
+func (s *AzureSandbox) expireIdle(sandbox SandboxDetails) {
+	id := sandbox.UUID
+
+	// Fails if the sandbox was used, kept or changed since the warning
+	ok, err := s.instances.ExpireIdle(id, *sandbox.IdleWarnedAt, idleDetectorActor)
+	if err != nil {
+		log.Logger.Error("Failed to expire idle sandbox", "id", id, "err", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	// The expired sandbox is kept until deleted, only its resources are released
+	operation, err := s.startOperation(id, OperationExpire,
+		[]operationStep{
+			{name: "Deallocating resources", run: simulateWork},
+			{name: "Locking resource group", run: simulateWork},
+		},
+		nil,
+		nil)
+	if err != nil {
+		log.Logger.Error("Failed to start expire operation for sandbox", "id", id, "err", err)
+		return
+	}
+
+	log.Logger.Info("Idle sandbox expired", "id", id, "owner", sandbox.Owner, "operation", operation.UUID)
+}
+
+// Make sure we conform to the ActivityProvider interface
+var _ ActivityProvider = (*FakeActivityProvider)(nil)
+
+// FakeActivityProvider keeps the writes in memory, for the local runs where
+// the sandboxes are not provisioned in Azure
+type FakeActivityProvider struct {
+	lock   sync.Mutex
+	writes map[string]time.Time
+}
+
+func NewFakeActivityProvider() *FakeActivityProvider {
+	return &FakeActivityProvider{
+		writes: make(map[string]time.Time),
+	}
+}
+
+// Record adds the write operation on the resource group
+func (f *FakeActivityProvider) Record(resourceGroup string, at time.Time) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if at.After(f.writes[resourceGroup]) {
+		f.writes[resourceGroup] = at
+	}
+}
+
+func (f *FakeActivityProvider) LastWrite(ctx context.Context, resourceGroup string, since time.Time) (*time.Time, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	at, ok := f.writes[resourceGroup]
+	if !ok || at.Before(since) {
+		return nil, nil
+	}
+
+	return &at, nil
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Operators of the label selector requirements
+const (
+	SelectorEquals       = "="
+	SelectorNotEquals    = "!="
+	SelectorIn           = "in"
+	SelectorNotIn        = "notin"
+	SelectorExists       = "exists"
+	SelectorDoesNotExist = "!"
+)
+
+// LabelRequirement is a single condition of the label selector
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	Values   []string
+}
+
+// LabelSelector selects the sandboxes matching all the requirements
+type LabelSelector []LabelRequirement
+
+var setRequirementPattern = regexp.MustCompile(`^(\S+)\s+(in|notin)\s+\((.*)\)$`)
+
+// ParseLabelSelector parses the selector in the format of Kubernetes, e.g.
+// "team=payments,env!=prod,tier in (web,api),!legacy". The requirements are
+// separated by commas, the commas within the parentheses separate the values.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	requirements := LabelSelector{}
+
+	for _, part := range splitSelector(selector) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		requirement, err := parseLabelRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+
+		requirements = append(requirements, requirement)
+	}
+
+	return requirements, nil
+}
+
+// Helper to split the selector by the commas outside of the parentheses
+func splitSelector(selector string) []string {
+	parts := []string{}
+
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, selector[start:])
+}
+
+func parseLabelRequirement(part string) (LabelRequirement, error) {
+	invalid := NewFieldError("labelSelector", "invalid requirement: "+part)
+
+	var requirement LabelRequirement
+
+	if match := setRequirementPattern.FindStringSubmatch(part); match != nil {
+		requirement = LabelRequirement{Key: match[1], Operator: match[2]}
+
+		for _, value := range strings.Split(match[3], ",") {
+			requirement.Values = append(requirement.Values, strings.TrimSpace(value))
+		}
+	} else if key, value, ok := strings.Cut(part, "!="); ok {
+		requirement = LabelRequirement{Key: strings.TrimSpace(key), Operator: SelectorNotEquals, Values: []string{strings.TrimSpace(value)}}
+	} else if key, value, ok := strings.Cut(part, "="); ok {
+		// Both = and == mean equality
+		value = strings.TrimPrefix(value, "=")
+		requirement = LabelRequirement{Key: strings.TrimSpace(key), Operator: SelectorEquals, Values: []string{strings.TrimSpace(value)}}
+	} else if key, ok := strings.CutPrefix(part, "!"); ok {
+		requirement = LabelRequirement{Key: strings.TrimSpace(key), Operator: SelectorDoesNotExist}
+	} else {
+		requirement = LabelRequirement{Key: part, Operator: SelectorExists}
+	}
+
+	if requirement.Key == "" || strings.ContainsAny(requirement.Key, forbiddenLabelKeyChars+" !=(),") {
+		return LabelRequirement{}, invalid
+	}
+
+	for _, value := range requirement.Values {
+		if strings.ContainsAny(value, "!=(),") {
+			return LabelRequirement{}, invalid
+		}
+	}
+
+	return requirement, nil
+}
+
+// mergeLabels returns the labels as they are after the merge of the patch,
+// nil values of the patch remove the labels
+func mergeLabels(labels map[string]string, patch map[string]*string) map[string]string {
+	merged := make(map[string]string, len(labels)+len(patch))
+
+	for key, value := range labels {
+		merged[key] = value
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+
+		merged[key] = *value
+	}
+
+	return merged
+}
+
+// clearLabels extends the patch to remove all the existing labels, the labels
+// set by the patch are kept
+func clearLabels(labels map[string]string, patch map[string]*string) map[string]*string {
+	cleared := make(map[string]*string, len(labels)+len(patch))
+
+	for key := range labels {
+		cleared[key] = nil
+	}
+
+	for key, value := range patch {
+		cleared[key] = value
+	}
+
+	return cleared
+}
+
+func equalLabels(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     LabelSelector
+	}{
+		{"", LabelSelector{}},
+		{"team=payments", LabelSelector{{Key: "team", Operator: SelectorEquals, Values: []string{"payments"}}}},
+		{"team==payments", LabelSelector{{Key: "team", Operator: SelectorEquals, Values: []string{"payments"}}}},
+		{"env!=prod", LabelSelector{{Key: "env", Operator: SelectorNotEquals, Values: []string{"prod"}}}},
+		{"team = payments , env != prod", LabelSelector{
+			{Key: "team", Operator: SelectorEquals, Values: []string{"payments"}},
+			{Key: "env", Operator: SelectorNotEquals, Values: []string{"prod"}},
+		}},
+		{"tier in (web, api)", LabelSelector{{Key: "tier", Operator: SelectorIn, Values: []string{"web", "api"}}}},
+		{"tier notin (web,api),team=payments", LabelSelector{
+			{Key: "tier", Operator: SelectorNotIn, Values: []string{"web", "api"}},
+			{Key: "team", Operator: SelectorEquals, Values: []string{"payments"}},
+		}},
+		{"legacy", LabelSelector{{Key: "legacy", Operator: SelectorExists}}},
+		{"!legacy", LabelSelector{{Key: "legacy", Operator: SelectorDoesNotExist}}},
+		{"team=", LabelSelector{{Key: "team", Operator: SelectorEquals, Values: []string{""}}}},
+		{"team=payments,,", LabelSelector{{Key: "team", Operator: SelectorEquals, Values: []string{"payments"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			got, err := ParseLabelSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("ParseLabelSelector() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLabelSelector() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLabelSelectorInvalid(t *testing.T) {
+	tests := []string{
+		"=payments",
+		"!=prod",
+		"!",
+		"team=pay=ments",
+		"team/name=payments",
+		"tier in (web,(api))",
+		"tier in web",
+		"tier in (web",
+	}
+
+	for _, selector := range tests {
+		t.Run(selector, func(t *testing.T) {
+			_, err := ParseLabelSelector(selector)
+
+			var domainErr *Error
+			if !errors.As(err, &domainErr) || domainErr.Kind != KindValidation {
+				t.Fatalf("ParseLabelSelector() error = %v, want validation error", err)
+			}
+			if domainErr.Fields[0].Field != "labelSelector" {
+				t.Errorf("ParseLabelSelector() field = %s, want labelSelector", domainErr.Fields[0].Field)
+			}
+		})
+	}
+}
+
+func TestMergeLabels(t *testing.T) {
+	value := func(s string) *string { return &s }
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		patch  map[string]*string
+		want   map[string]string
+	}{
+		{"add", map[string]string{"a": "1"}, map[string]*string{"b": value("2")}, map[string]string{"a": "1", "b": "2"}},
+		{"replace", map[string]string{"a": "1"}, map[string]*string{"a": value("2")}, map[string]string{"a": "2"}},
+		{"remove", map[string]string{"a": "1", "b": "2"}, map[string]*string{"a": nil}, map[string]string{"b": "2"}},
+		{"remove missing", nil, map[string]*string{"a": nil}, map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeLabels(tt.labels, tt.patch); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+)
+
+// Events the notifications are sent for
+const (
+	EventApprovalRequested = "approval.requested"
+	EventApprovalApproved  = "approval.approved"
+	EventApprovalDenied    = "approval.denied"
+	EventApprovalExpired   = "approval.expired"
+	EventSandboxIdle       = "sandbox.idle"
+)
+
+// How long the notifier gets to deliver a notification
+const notifyTimeout = 10 * time.Second
+
+// Notification tells the approvers and the requesters about the approval
+// requests, and the owners about their idle sandboxes
+type Notification struct {
+	Event       string     `json:"event"`
+	ApprovalID  string     `json:"approvalId,omitempty"`
+	SandboxID   string     `json:"sandboxId"`
+	SandboxName string     `json:"sandboxName"`
+	RequestedBy string     `json:"requestedBy,omitempty"`
+	Owner       string     `json:"owner,omitempty"`
+	Actor       string     `json:"actor,omitempty"`
+	Reasons     []string   `json:"reasons,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// Notifier is the hook the notifications are delivered through, e.g. to chat
+// or email
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier only logs the notifications, for the setups without any hook
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	log.Logger.Info("Notification", "event", notification.Event, "approval", notification.ApprovalID,
+		"sandbox", notification.SandboxID, "owner", notification.Owner, "actor", notification.Actor)
+
+	return nil
+}
+
+// WebhookNotifier posts the notifications as JSON to the URL
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:    url,
+		Client: &http.Client{Timeout: notifyTimeout},
+	}
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+
+	return nil
+}
+
+// notify delivers the notification in the background, so the slow hook
+// doesn't hold the request. Failed deliveries are only logged.
+func (s *AzureSandbox) notify(notification Notification) {
+	notification.CreatedAt = time.Now().UTC()
+
+	s.Add(1)
+	go func() {
+		defer s.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+			log.Logger.Error("Failed to deliver notification", "event", notification.Event,
+				"approval", notification.ApprovalID, "sandbox", notification.SandboxID, "err", err)
+		}
+	}()
+}
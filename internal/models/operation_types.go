@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Kinds of long-running operations
+const (
+	OperationCreate = "CREATE"
+	OperationDelete = "DELETE"
+	OperationStop   = "STOP"
+	OperationExtend = "EXTEND"
+	OperationUpdate = "UPDATE"
+	OperationStart  = "START"
+	OperationExpire = "EXPIRE"
+)
+
+// Operation statuses as stored in the database
+const (
+	OperationNotStarted = "NOT_STARTED"
+	OperationRunning    = "RUNNING"
+	OperationSucceeded  = "SUCCEEDED"
+	OperationFailed     = "FAILED"
+	OperationCanceled   = "CANCELED"
+)
+
+type OperationDetails struct {
+	UUID            string
+	SandboxID       string
+	Kind            string
+	Status          string
+	PercentComplete int
+	Step            string
+	ErrorCode       string
+	ErrorMessage    string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Done reports whether the operation reached a terminal status
+func (o OperationDetails) Done() bool {
+	return o.Status == OperationSucceeded || o.Status == OperationFailed || o.Status == OperationCanceled
+}
+
+type OperationData interface {
+	Insert(sandboxID string, kind string) (string, error)
+	GetByID(id string) (OperationDetails, error)
+	// GetNotStarted returns the operation of the kind waiting for the sandbox to be started
+	GetNotStarted(sandboxID string, kind string) (OperationDetails, error)
+	// GetStale returns up to limit running operations not updated for the
+	// staleAfter, oldest first
+	GetStale(limit int, staleAfter time.Duration) ([]OperationDetails, error)
+	UpdateProgress(id string, status string, percentComplete int, step string) (bool, error)
+	UpdateError(id string, status string, code string, message string) (bool, error)
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Make sure we conform to the OperationData interface
+var _ OperationData = (*OperationsPostgres)(nil)
+
+type OperationsPostgres struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewOperationsPostgres(dbPool *pgxpool.Pool) *OperationsPostgres {
+
+	return &OperationsPostgres{
+		dbPool: dbPool,
+	}
+}
+
+func (o *OperationsPostgres) Insert(sandboxID string, kind string) (string, error) {
+	id := ""
+
+	err := o.dbPool.QueryRow(context.Background(), "SELECT public.insert_operation($1, $2)", sandboxID, kind).Scan(&id)
+
+	return id, err
+}
+
+func (o *OperationsPostgres) GetByID(id string) (OperationDetails, error) {
+	var percent int16
+
+	operation := OperationDetails{}
+
+	err := o.dbPool.QueryRow(context.Background(), "SELECT * FROM public.get_operation_by_id($1)", id).Scan(
+		&operation.UUID,
+		&operation.SandboxID,
+		&operation.Kind,
+		&operation.Status,
+		&percent,
+		&operation.Step,
+		&operation.ErrorCode,
+		&operation.ErrorMessage,
+		&operation.CreatedAt,
+		&operation.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return OperationDetails{}, ErrOperationNotFound
+	}
+
+	operation.PercentComplete = int(percent)
+
+	return operation, err
+}
+
+func (o *OperationsPostgres) GetNotStarted(sandboxID string, kind string) (OperationDetails, error) {
+	var id *string
+
+	err := o.dbPool.QueryRow(context.Background(), "SELECT public.get_not_started_operation_id($1, $2)", sandboxID, kind).Scan(&id)
+	if err != nil {
+		return OperationDetails{}, err
+	}
+
+	if id == nil {
+		return OperationDetails{}, ErrOperationNotFound
+	}
+
+	return o.GetByID(*id)
+}
+
+func (o *OperationsPostgres) GetStale(limit int, staleAfter time.Duration) ([]OperationDetails, error) {
+	rows, err := o.dbPool.Query(context.Background(), "SELECT * FROM public.get_stale_operation_ids($1, $2)",
+		limit, int(staleAfter.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	operations := make([]OperationDetails, 0, len(ids))
+
+	for _, id := range ids {
+		operation, err := o.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		operations = append(operations, operation)
+	}
+
+	return operations, nil
+}
+
+func (o *OperationsPostgres) UpdateProgress(id string, status string, percentComplete int, step string) (bool, error) {
+	ok := false
+
+	err := o.dbPool.QueryRow(context.Background(), "SELECT public.update_operation_progress($1, $2, $3, $4)",
+		id, status, int16(percentComplete), step).Scan(&ok)
+
+	return ok, err
+}
+
+func (o *OperationsPostgres) UpdateError(id string, status string, code string, message string) (bool, error) {
+	ok := false
+
+	err := o.dbPool.QueryRow(context.Background(), "SELECT public.update_operation_error($1, $2, $3, $4)",
+		id, status, code, message).Scan(&ok)
+
+	return ok, err
+}
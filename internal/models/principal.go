@@ -0,0 +1,49 @@
+package models
+
+// Permissions of the API, the scopes of the operations in the OpenAPI spec
+const (
+	PermissionRead    = "sandbox:r"
+	PermissionWrite   = "sandbox:w"
+	PermissionApprove = "sandbox:approve"
+	PermissionAdmin   = "sandbox:admin"
+)
+
+// Principal is the authenticated caller, the sandboxes it creates are owned
+// by its Subject and the changes it makes are recorded for it
+type Principal struct {
+	Subject string
+	// Name is the display name, e.g. the user principal name, if the token has one
+	Name string
+	// Tenant is the directory the principal comes from, e.g. the tid of Entra ID
+	Tenant      string
+	Permissions []string
+	// APITokenID is set if the principal is authenticated by the personal
+	// access token, see APITokens
+	APITokenID string
+}
+
+// systemPrincipal acts on behalf of the service itself, e.g. the scheduler
+func systemPrincipal(name string) Principal {
+	return Principal{Subject: name, Permissions: []string{PermissionAdmin}}
+}
+
+// Has checks the permission of the principal
+func (p Principal) Has(permission string) bool {
+	for _, granted := range p.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canActOn allows the principal to act on its own sandboxes, the admins act
+// on anyone's
+func (p Principal) canActOn(details SandboxDetails) error {
+	if p.Has(PermissionAdmin) || (p.Subject != "" && p.Subject == details.Owner) {
+		return nil
+	}
+
+	return ErrNotOwner
+}
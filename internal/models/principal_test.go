@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrincipalCanActOn(t *testing.T) {
+	sandbox := SandboxDetails{Owner: "alice"}
+
+	tests := []struct {
+		name      string
+		principal Principal
+		details   SandboxDetails
+		allowed   bool
+	}{
+		{"owner", Principal{Subject: "alice", Permissions: []string{PermissionWrite}}, sandbox, true},
+		{"someone else", Principal{Subject: "bob", Permissions: []string{PermissionWrite}}, sandbox, false},
+		{"admin", Principal{Subject: "bob", Permissions: []string{PermissionAdmin}}, sandbox, true},
+		{"system", systemPrincipal(schedulerActor), sandbox, true},
+		{"no subject", Principal{Permissions: []string{PermissionWrite}}, SandboxDetails{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.principal.canActOn(tt.details)
+
+			if tt.allowed && err != nil {
+				t.Errorf("canActOn() error = %v, want nil", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrNotOwner) {
+				t.Errorf("canActOn() error = %v, want %v", err, ErrNotOwner)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Classes of the routes limited separately, so the reads of a busy dashboard
+// don't starve the writes of the same principal
+const (
+	RateClassRead  = "read"
+	RateClassWrite = "write"
+	RateClassAdmin = "admin"
+)
+
+// RateLimit is the token bucket of the class, Requests are allowed per Period
+// on average, all of them at once at most. The zero limit is not enforced.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// RateLimitResult is the state of the bucket after the request
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next request is allowed, 0 if it is
+	// allowed right away
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again
+	ResetAfter time.Duration
+}
+
+// RateLimitData keeps the buckets by the key, the instances sharing the
+// store share the limits
+type RateLimitData interface {
+	// Take takes a token from the bucket refilled by rate tokens a second up
+	// to burst, and returns whether it was taken and the tokens left
+	Take(key string, rate float64, burst int) (bool, float64, error)
+	// DeleteIdle forgets the buckets untouched for the interval
+	DeleteIdle(interval time.Duration) error
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+)
+
+// DefaultRateLimits are used for the classes without a limit set
+var DefaultRateLimits = map[string]RateLimit{
+	RateClassRead:  {Requests: 300, Period: time.Minute},
+	RateClassWrite: {Requests: 60, Period: time.Minute},
+	RateClassAdmin: {Requests: 120, Period: time.Minute},
+}
+
+// RateLimiter limits the requests of every principal by the token buckets of
+// the route classes. The bucket holds the Requests of the limit and is
+// refilled evenly over its Period.
+type RateLimiter struct {
+	data   RateLimitData
+	limits map[string]RateLimit
+}
+
+func NewRateLimiter(data RateLimitData, limits map[string]RateLimit) *RateLimiter {
+	merged := make(map[string]RateLimit, len(DefaultRateLimits))
+	for class, limit := range DefaultRateLimits {
+		merged[class] = limit
+	}
+	for class, limit := range limits {
+		merged[class] = limit
+	}
+
+	return &RateLimiter{
+		data:   data,
+		limits: merged,
+	}
+}
+
+// Limit returns the limit of the class, the unknown classes are limited as
+// the reads
+func (l *RateLimiter) Limit(class string) RateLimit {
+	limit, ok := l.limits[class]
+	if !ok {
+		limit = l.limits[RateClassRead]
+	}
+
+	return limit
+}
+
+// Allow takes a token from the bucket of the principal for the class. The
+// API tokens share the buckets of their owners, so more tokens don't raise
+// the limits.
+func (l *RateLimiter) Allow(principal Principal, class string) (RateLimitResult, error) {
+	limit := l.Limit(class)
+	if limit.Requests <= 0 || limit.Period <= 0 {
+		return RateLimitResult{Allowed: true}, nil
+	}
+
+	rate := float64(limit.Requests) / limit.Period.Seconds()
+
+	allowed, tokens, err := l.data.Take(rateLimitKey(principal, class), rate, limit.Requests)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	result := RateLimitResult{
+		Allowed:    allowed,
+		Limit:      limit.Requests,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: secondsDuration((float64(limit.Requests) - tokens) / rate),
+	}
+
+	if !allowed {
+		result.RetryAfter = secondsDuration((1 - tokens) / rate)
+	}
+
+	return result, nil
+}
+
+// Run forgets the idle buckets every interval until the context is canceled.
+// The bucket idle for the longest period is full, so it is the same as none.
+func (l *RateLimiter) Run(ctx context.Context, interval time.Duration) {
+	idle := time.Duration(0)
+	for _, limit := range l.limits {
+		if limit.Period > idle {
+			idle = limit.Period
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.data.DeleteIdle(idle); err != nil {
+				log.Logger.Error("Failed to delete idle rate limit buckets", "err", err)
+			}
+		}
+	}
+}
+
+// The anonymous principals share a single bucket of the class
+func rateLimitKey(principal Principal, class string) string {
+	return class + ":" + principal.Subject
+}
+
+// Helper to round the fractional seconds up, so the clients retrying after
+// the duration are not limited again
+func secondsDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+
+	return time.Duration(math.Ceil(seconds)) * time.Second
+}
+
+// Make sure we conform to the RateLimitData interface
+var _ RateLimitData = (*RateLimitsMemory)(nil)
+
+// RateLimitsMemory keeps the buckets in memory, the limits are per instance
+type RateLimitsMemory struct {
+	lock    sync.Mutex
+	buckets map[string]*rateLimitBucket
+
+	// now is replaced by the tests
+	now func() time.Time
+}
+
+type rateLimitBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func NewRateLimitsMemory() *RateLimitsMemory {
+
+	return &RateLimitsMemory{
+		buckets: make(map[string]*rateLimitBucket),
+		now:     time.Now,
+	}
+}
+
+func (m *RateLimitsMemory) Take(key string, rate float64, burst int) (bool, float64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.now()
+
+	bucket, ok := m.buckets[key]
+	if !ok {
+		bucket = &rateLimitBucket{tokens: float64(burst), updatedAt: now}
+		m.buckets[key] = bucket
+	}
+
+	if elapsed := now.Sub(bucket.updatedAt).Seconds(); elapsed > 0 {
+		bucket.tokens += elapsed * rate
+	}
+	bucket.tokens = math.Min(bucket.tokens, float64(burst))
+	bucket.updatedAt = now
+
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens--
+	}
+
+	return allowed, bucket.tokens, nil
+}
+
+func (m *RateLimitsMemory) DeleteIdle(interval time.Duration) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.now()
+
+	for key, bucket := range m.buckets {
+		if now.Sub(bucket.updatedAt) > interval {
+			delete(m.buckets, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Make sure we conform to the RateLimitData interface
+var _ RateLimitData = (*RateLimitsPostgres)(nil)
+
+// RateLimitsPostgres keeps the buckets in the database, the limits are shared
+// by all the instances
+type RateLimitsPostgres struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewRateLimitsPostgres(dbPool *pgxpool.Pool) *RateLimitsPostgres {
+
+	return &RateLimitsPostgres{
+		dbPool: dbPool,
+	}
+}
+
+func (p *RateLimitsPostgres) Take(key string, rate float64, burst int) (bool, float64, error) {
+	var allowed bool
+	var tokens float64
+
+	err := p.dbPool.QueryRow(context.Background(), "SELECT * FROM public.take_rate_limit_token($1, $2, $3)",
+		key, rate, burst).Scan(&allowed, &tokens)
+
+	return allowed, tokens, err
+}
+
+func (p *RateLimitsPostgres) DeleteIdle(interval time.Duration) error {
+	_, err := p.dbPool.Exec(context.Background(), "SELECT public.delete_idle_rate_limit_buckets($1)",
+		int(interval.Seconds()))
+
+	return err
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	data := NewRateLimitsMemory()
+	data.now = func() time.Time { return now }
+
+	limiter := NewRateLimiter(data, map[string]RateLimit{
+		RateClassWrite: {Requests: 2, Period: 10 * time.Second},
+		RateClassAdmin: {},
+	})
+	alice := Principal{Subject: "alice"}
+
+	tests := []struct {
+		name      string
+		principal Principal
+		class     string
+		advance   time.Duration
+		want      RateLimitResult
+	}{
+		{"full bucket", alice, RateClassWrite, 0, RateLimitResult{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: 5 * time.Second}},
+		{"last token", alice, RateClassWrite, 0, RateLimitResult{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: 10 * time.Second}},
+		{"empty bucket", alice, RateClassWrite, 0, RateLimitResult{Allowed: false, Limit: 2, Remaining: 0, RetryAfter: 5 * time.Second, ResetAfter: 10 * time.Second}},
+		{"partly refilled", alice, RateClassWrite, 3 * time.Second, RateLimitResult{Allowed: false, Limit: 2, Remaining: 0, RetryAfter: 2 * time.Second, ResetAfter: 7 * time.Second}},
+		{"refilled", alice, RateClassWrite, 2 * time.Second, RateLimitResult{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: 10 * time.Second}},
+		{"other principal", Principal{Subject: "bob"}, RateClassWrite, 0, RateLimitResult{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: 5 * time.Second}},
+		{"API token of the owner", Principal{Subject: "alice", APITokenID: "token"}, RateClassWrite, 0, RateLimitResult{Allowed: false, Limit: 2, Remaining: 0, RetryAfter: 5 * time.Second, ResetAfter: 10 * time.Second}},
+		{"other class", alice, RateClassRead, 0, RateLimitResult{Allowed: true, Limit: 300, Remaining: 299, ResetAfter: time.Second}},
+		{"limit turned off", alice, RateClassAdmin, 0, RateLimitResult{Allowed: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.advance)
+
+			got, err := limiter.Allow(tt.principal, tt.class)
+			if err != nil {
+				t.Fatalf("Allow() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Allow() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitsMemoryDeleteIdle(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	data := NewRateLimitsMemory()
+	data.now = func() time.Time { return now }
+
+	if _, _, err := data.Take("idle", 1, 5); err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, _, err := data.Take("busy", 1, 5); err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+
+	if err := data.DeleteIdle(30 * time.Second); err != nil {
+		t.Fatalf("DeleteIdle() error = %v", err)
+	}
+
+	if _, ok := data.buckets["idle"]; ok {
+		t.Error("DeleteIdle() kept the idle bucket")
+	}
+	if _, ok := data.buckets["busy"]; !ok {
+		t.Error("DeleteIdle() deleted the busy bucket")
+	}
+}
+
+// countingSandboxData counts the sandboxes of any filter as pending, the rest
+// of the interface is not used
+type countingSandboxData struct {
+	SandboxData
+	pending int
+	filter  SandboxFilter
+}
+
+func (c *countingSandboxData) Count(filter SandboxFilter) (int, error) {
+	c.filter = filter
+
+	return c.pending, nil
+}
+
+func TestCheckPendingCreates(t *testing.T) {
+	writer := Principal{Subject: "alice", Permissions: []string{PermissionWrite, PermissionRead}}
+	admin := Principal{Subject: "admin", Permissions: []string{PermissionAdmin}}
+
+	tests := []struct {
+		name      string
+		limit     int
+		pending   int
+		principal Principal
+		err       error
+	}{
+		{"under the limit", 2, 1, writer, nil},
+		{"at the limit", 2, 2, writer, ErrPendingCreateLimit},
+		{"no limit", 0, 10, writer, nil},
+		{"admin", 2, 10, admin, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &countingSandboxData{pending: tt.pending}
+			s := &AzureSandbox{instances: data, maxPendingCreates: tt.limit}
+
+			err := s.checkPendingCreates(tt.principal)
+			if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+				t.Fatalf("checkPendingCreates() error = %v, want %v", err, tt.err)
+			}
+
+			if tt.err != nil && (data.filter.Owner != tt.principal.Subject || len(data.filter.Statuses) != 1 || data.filter.Statuses[0] != StatusPending) {
+				t.Errorf("checkPendingCreates() counted %+v, want the pending sandboxes of %s", data.filter, tt.principal.Subject)
+			}
+		})
+	}
+}
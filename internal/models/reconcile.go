@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+)
+
+const (
+	// ReconcileStaleAfter is how long the running operation goes without
+	// progress before it is considered lost with the instance running it
+	ReconcileStaleAfter = time.Hour
+	// Lost operations reconciled at once
+	reconcileBatchSize = 100
+)
+
+// Transitional statuses of the sandboxes by the kind of the operation moving
+// them, and the statuses they are left in once the operation fails
+var reconciledStatuses = map[string]struct{ from, to string }{
+	OperationCreate: {StatusPending, StatusFailed},
+	OperationDelete: {StatusDeleting, StatusFailed},
+	OperationStop:   {StatusStopping, StatusRunning},
+	OperationStart:  {StatusStarting, StatusStopped},
+}
+
+// ReconcileResult is the lost operation and the status its sandbox is moved to
+type ReconcileResult struct {
+	Operation OperationDetails
+	// Status of the sandbox before the reconciliation, empty if it is gone
+	Status string
+	// ReconciledStatus is the status the sandbox is moved to, empty if it is
+	// left as it is
+	ReconciledStatus string
+	Err              error
+}
+
+// Reconcile fails the operations lost with the instances which stopped while
+// running them, and moves their sandboxes out of the transitional statuses,
+// as if the operations failed. In the dry run the results are only reported.
+// It is for the admins only.
+func (s *AzureSandbox) Reconcile(principal Principal, dryRun bool) ([]ReconcileResult, error) {
+	if !principal.Has(PermissionAdmin) {
+		return nil, ErrAdminRequired
+	}
+
+	operations, err := s.operations.GetStale(reconcileBatchSize, ReconcileStaleAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ReconcileResult, 0, len(operations))
+
+	for _, operation := range operations {
+		// Still run by this instance, only slow
+		s.cancelLock.Lock()
+		_, running := s.cancels[operation.UUID]
+		s.cancelLock.Unlock()
+
+		if running {
+			continue
+		}
+
+		result := ReconcileResult{Operation: operation}
+
+		sandbox, err := s.instances.GetByID(operation.SandboxID)
+		if err != nil && !errors.Is(err, ErrSandboxNotFound) {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+
+		result.Status = sandbox.Status
+		if statuses, ok := reconciledStatuses[operation.Kind]; ok && sandbox.Status == statuses.from {
+			result.ReconciledStatus = statuses.to
+		}
+
+		if !dryRun {
+			result.Err = s.reconcile(operation, sandbox, result.ReconciledStatus)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// reconcile moves the sandbox to the status, empty leaves it as it is, and
+// fails the lost operation
+func (s *AzureSandbox) reconcile(operation OperationDetails, sandbox SandboxDetails, status string) error {
+	if status != "" {
+		ok, err := s.instances.UpdateStatus(sandbox.UUID, status, sandbox.Version)
+		if err != nil {
+			return err
+		}
+
+		// Moved on meanwhile, so the operation is not lost after all
+		if !ok {
+			return ErrWrongStatus
+		}
+	}
+
+	if _, err := s.operations.UpdateError(operation.UUID, OperationFailed, "OperationLost", "operation was interrupted and reconciled"); err != nil {
+		return err
+	}
+
+	log.Logger.Info("Lost operation reconciled", "id", operation.UUID, "sandbox", operation.SandboxID, "kind", operation.Kind, "status", status)
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeOperationData holds the lost operations and records the failed ones.
+// New operations can't be recorded, so nothing runs in the background.
+type fakeOperationData struct {
+	OperationData
+	stale  []OperationDetails
+	failed map[string]string
+}
+
+func (f *fakeOperationData) Insert(sandboxID string, kind string) (string, error) {
+	return "", errors.New("database is down")
+}
+
+func (f *fakeOperationData) GetNotStarted(sandboxID string, kind string) (OperationDetails, error) {
+	return OperationDetails{}, ErrOperationNotFound
+}
+
+func (f *fakeOperationData) GetStale(limit int, staleAfter time.Duration) ([]OperationDetails, error) {
+	return f.stale, nil
+}
+
+func (f *fakeOperationData) UpdateError(id string, status string, code string, message string) (bool, error) {
+	if f.failed == nil {
+		f.failed = map[string]string{}
+	}
+	f.failed[id] = code
+
+	return true, nil
+}
+
+func TestReconcile(t *testing.T) {
+	admin := Principal{Subject: "admin", Permissions: []string{PermissionAdmin}}
+
+	tests := []struct {
+		name   string
+		kind   string
+		status string
+		want   string
+	}{
+		{"lost stop", OperationStop, StatusStopping, StatusRunning},
+		{"lost start", OperationStart, StatusStarting, StatusStopped},
+		{"lost create", OperationCreate, StatusPending, StatusFailed},
+		{"lost delete", OperationDelete, StatusDeleting, StatusFailed},
+		{"lost update", OperationUpdate, StatusRunning, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &fakeSandboxData{sandbox: SandboxDetails{UUID: testSandboxID, Status: tt.status, Version: 1}}
+			operations := &fakeOperationData{stale: []OperationDetails{
+				{UUID: "op-1", SandboxID: testSandboxID, Kind: tt.kind, Status: OperationRunning},
+			}}
+			s := &AzureSandbox{instances: data, operations: operations, cancels: map[string]runningOperation{}}
+
+			results, err := s.Reconcile(admin, true)
+			if err != nil {
+				t.Fatalf("Reconcile() dry run error = %v", err)
+			}
+			if len(results) != 1 || results[0].ReconciledStatus != tt.want || results[0].Status != tt.status {
+				t.Fatalf("Reconcile() dry run = %+v, want %s moved to %q", results, tt.status, tt.want)
+			}
+			if len(data.statuses) != 0 || len(operations.failed) != 0 {
+				t.Fatalf("Reconcile() dry run moved the sandbox through %v, failed %v", data.statuses, operations.failed)
+			}
+
+			results, err = s.Reconcile(admin, false)
+			if err != nil || results[0].Err != nil {
+				t.Fatalf("Reconcile() error = %v, result error = %v", err, results[0].Err)
+			}
+
+			if want := tt.want; want != "" && (len(data.statuses) != 1 || data.statuses[0] != want) {
+				t.Errorf("Reconcile() moved the sandbox through %v, want %s", data.statuses, want)
+			}
+			if tt.want == "" && len(data.statuses) != 0 {
+				t.Errorf("Reconcile() moved the sandbox through %v", data.statuses)
+			}
+			if operations.failed["op-1"] != "OperationLost" {
+				t.Errorf("Reconcile() failed the operations %v", operations.failed)
+			}
+		})
+	}
+}
+
+func TestReconcileSkipsRunning(t *testing.T) {
+	data := &fakeSandboxData{sandbox: SandboxDetails{UUID: testSandboxID, Status: StatusStopping, Version: 1}}
+	operations := &fakeOperationData{stale: []OperationDetails{{UUID: "op-1", SandboxID: testSandboxID, Kind: OperationStop}}}
+	s := &AzureSandbox{instances: data, operations: operations, cancels: map[string]runningOperation{
+		"op-1": {sandboxID: testSandboxID},
+	}}
+
+	results, err := s.Reconcile(Principal{Subject: "admin", Permissions: []string{PermissionAdmin}}, false)
+	if err != nil || len(results) != 0 {
+		t.Errorf("Reconcile() = %v, %v, want the operation run here left alone", results, err)
+	}
+}
+
+func TestAdminActionsRequireAdmin(t *testing.T) {
+	writer := Principal{Subject: "alice", Permissions: []string{PermissionWrite, PermissionRead}}
+	s := &AzureSandbox{instances: &fakeSandboxData{sandbox: SandboxDetails{UUID: testSandboxID, Owner: "alice"}}}
+
+	if _, err := s.Reconcile(writer, true); !errors.Is(err, ErrAdminRequired) {
+		t.Errorf("Reconcile() error = %v, want %v", err, ErrAdminRequired)
+	}
+
+	if _, err := s.ForceRemove(testSandboxID, writer); !errors.Is(err, ErrAdminRequired) {
+		t.Errorf("ForceRemove() of own sandbox error = %v, want %v", err, ErrAdminRequired)
+	}
+}
+
+func TestForceRemoveStuckSandbox(t *testing.T) {
+	admin := Principal{Subject: "admin", Permissions: []string{PermissionAdmin}}
+
+	data := &fakeSandboxData{sandbox: SandboxDetails{UUID: testSandboxID, Status: StatusStopping, Version: 3}}
+	s := &AzureSandbox{instances: data, operations: &fakeOperationData{}, cancels: map[string]runningOperation{}}
+
+	// The delete operation can't be recorded, the sandbox is DELETING already
+	if _, err := s.ForceRemove(testSandboxID, admin); err == nil {
+		t.Fatal("ForceRemove() error = nil, want the failure to record the operation")
+	}
+
+	if len(data.statuses) == 0 || data.statuses[0] != StatusDeleting {
+		t.Errorf("ForceRemove() moved the sandbox through %v, want %s", data.statuses, StatusDeleting)
+	}
+
+	data.sandbox.Status = StatusDeleted
+	if _, err := s.ForceRemove(testSandboxID, admin); !errors.Is(err, ErrWrongStatus) {
+		t.Errorf("ForceRemove() of the deleted sandbox error = %v, want %v", err, ErrWrongStatus)
+	}
+}
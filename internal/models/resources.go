@@ -0,0 +1,33 @@
+package models
+
+import "context"
+
+// ResourceProvider manages the Azure resources of the sandboxes, the resource
+// group of the sandbox is named after it
+type ResourceProvider interface {
+	// CreateResourceGroup creates the resource group tagged with the labels
+	CreateResourceGroup(ctx context.Context, resourceGroup string, tags map[string]string) error
+	// DeleteResourceGroup removes the resource group with all its resources
+	DeleteResourceGroup(ctx context.Context, resourceGroup string) error
+	// UpdateTags replaces the tags of the resource group with the labels
+	UpdateTags(ctx context.Context, resourceGroup string, tags map[string]string) error
+}
+
+// Make sure we conform to the ResourceProvider interface
+var _ ResourceProvider = FakeResourceProvider{}
+
+// FakeResourceProvider only simulates the work, for the local runs where the
+// sandboxes are not provisioned in Azure
+type FakeResourceProvider struct{}
+
+func (FakeResourceProvider) CreateResourceGroup(ctx context.Context, resourceGroup string, tags map[string]string) error {
+	return simulateWork(ctx)
+}
+
+func (FakeResourceProvider) DeleteResourceGroup(ctx context.Context, resourceGroup string) error {
+	return simulateWork(ctx)
+}
+
+func (FakeResourceProvider) UpdateTags(ctx context.Context, resourceGroup string, tags map[string]string) error {
+	return simulateWork(ctx)
+}
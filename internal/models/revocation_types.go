@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// RevocationDetails revokes either the token by its TokenID, the jti of the
+// JWT or the id of the API token, or every token of the Subject issued
+// before RevokedAt
+type RevocationDetails struct {
+	UUID      string
+	TokenID   string
+	Subject   string
+	RevokedBy string
+	RevokedAt time.Time
+	// ExpiresAt is the expiry of the revoked token, the revocation is dropped
+	// afterwards. Nil for the subjects.
+	ExpiresAt *time.Time
+	Reason    string
+}
+
+type RevocationData interface {
+	Insert(tokenID string, subject string, revokedBy string, reason string, revokedAt time.Time, expiresAt *time.Time) (RevocationDetails, error)
+	// GetAll lists the revocations, oldest first
+	GetAll() ([]RevocationDetails, error)
+	// DeleteExpired removes the revocations of the tokens expired before now
+	DeleteExpired(now time.Time) (int, error)
+}
+
+// RevocationController manages the deny-list of the tokens
+type RevocationController interface {
+	// expiresAt is the expiry of the revoked token, nil for the subjects
+	Revoke(principal Principal, tokenID string, subject string, reason string, expiresAt *time.Time) (RevocationDetails, error)
+	List() ([]RevocationDetails, error)
+}
@@ -0,0 +1,191 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+)
+
+// Make sure we conform to the RevocationController interface
+var _ RevocationController = (*Revocations)(nil)
+
+// Revocations is the deny-list of the tokens. It is kept in memory for the
+// authentication of every request and reloaded periodically, so the tokens
+// revoked by the other instances are denied within the reload interval.
+type Revocations struct {
+	data RevocationData
+
+	lock sync.RWMutex
+	// The revoked tokens by the id
+	tokens map[string]bool
+	// The latest revocation of every token of the subject
+	subjects map[string]time.Time
+}
+
+func NewRevocations(data RevocationData) *Revocations {
+
+	return &Revocations{
+		data:     data,
+		tokens:   make(map[string]bool),
+		subjects: make(map[string]time.Time),
+	}
+}
+
+// Load replaces the cached list with the stored one, without the expired
+// tokens
+func (r *Revocations) Load() error {
+	revocations, err := r.data.GetAll()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tokens := make(map[string]bool)
+	subjects := make(map[string]time.Time)
+
+	for _, revocation := range revocations {
+		if revocation.ExpiresAt != nil && !revocation.ExpiresAt.After(now) {
+			continue
+		}
+
+		addRevocation(tokens, subjects, revocation)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.tokens = tokens
+	r.subjects = subjects
+
+	return nil
+}
+
+// Prune removes the revocations of the expired tokens from the store, the
+// expired tokens are denied anyway
+func (r *Revocations) Prune() error {
+	deleted, err := r.data.DeleteExpired(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		log.Logger.Info("Revocations of expired tokens removed", "count", deleted)
+	}
+
+	return nil
+}
+
+// Run prunes and reloads the list every interval until the context is
+// canceled. The failed reload keeps the previous list.
+func (r *Revocations) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Prune(); err != nil {
+				log.Logger.Error("Failed to remove revocations of expired tokens", "err", err)
+			}
+			if err := r.Load(); err != nil {
+				log.Logger.Error("Failed to reload revoked tokens", "err", err)
+			}
+		}
+	}
+}
+
+// Revoke denies the token by its id until it expires, or every token of the
+// subject issued until now. Exactly one of them has to be set. The token is
+// denied for the longest lifetime of the API tokens if its expiry is not
+// known, the JWTs are shorter.
+func (r *Revocations) Revoke(principal Principal, tokenID string, subject string, reason string, expiresAt *time.Time) (RevocationDetails, error) {
+	errs := fieldErrors{}
+
+	tokenID = strings.TrimSpace(tokenID)
+	subject = strings.TrimSpace(subject)
+
+	if (tokenID == "") == (subject == "") {
+		errs.addField("jti", "either jti or subject must be set")
+	}
+
+	if len(tokenID) > 255 {
+		errs.addField("jti", "must be at most 255 characters long")
+	}
+
+	if len(subject) > 255 {
+		errs.addField("subject", "must be at most 255 characters long")
+	}
+
+	if len(reason) > MaxDescriptionLength {
+		errs.addField("reason", "must be at most 1024 characters long")
+	}
+
+	// The timestamp columns keep the wall clock time only, the issue times of
+	// the tokens are compared in UTC
+	revokedAt := time.Now().UTC()
+
+	switch {
+	case expiresAt != nil && subject != "":
+		errs.addField("expiresAt", "can be set only along with jti")
+	case expiresAt != nil && !expiresAt.After(revokedAt):
+		errs.addField("expiresAt", "must be in the future")
+	case expiresAt != nil:
+		expiresAt = utcTime(expiresAt)
+	case tokenID != "":
+		defaultExpiry := revokedAt.Add(MaxAPITokenLifetime)
+		expiresAt = &defaultExpiry
+	}
+
+	if err := errs.err(); err != nil {
+		return RevocationDetails{}, err
+	}
+
+	revocation, err := r.data.Insert(tokenID, subject, principal.Subject, reason, revokedAt, expiresAt)
+	if err != nil {
+		return RevocationDetails{}, err
+	}
+
+	// Denied by this instance right away, by the others on the reload
+	r.lock.Lock()
+	addRevocation(r.tokens, r.subjects, revocation)
+	r.lock.Unlock()
+
+	log.Logger.Info("Token revoked", "id", revocation.UUID, "jti", tokenID, "subject", subject, "by", principal.Subject)
+
+	return revocation, nil
+}
+
+// List returns the revocations, oldest first
+func (r *Revocations) List() ([]RevocationDetails, error) {
+	return r.data.GetAll()
+}
+
+// IsRevoked checks the token by its id and by its subject. The token without
+// the issue time is revoked along with every other token of the subject.
+func (r *Revocations) IsRevoked(tokenID string, subject string, issuedAt time.Time) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if tokenID != "" && r.tokens[tokenID] {
+		return true
+	}
+
+	revokedAt, ok := r.subjects[subject]
+
+	return ok && subject != "" && !issuedAt.After(revokedAt)
+}
+
+func addRevocation(tokens map[string]bool, subjects map[string]time.Time, revocation RevocationDetails) {
+	if revocation.TokenID != "" {
+		tokens[revocation.TokenID] = true
+	}
+
+	if revocation.Subject != "" && revocation.RevokedAt.After(subjects[revocation.Subject]) {
+		subjects[revocation.Subject] = revocation.RevokedAt
+	}
+}
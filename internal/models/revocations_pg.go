@@ -0,0 +1,96 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Make sure we conform to the RevocationData interface
+var _ RevocationData = (*RevocationsPostgres)(nil)
+
+type RevocationsPostgres struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewRevocationsPostgres(dbPool *pgxpool.Pool) *RevocationsPostgres {
+
+	return &RevocationsPostgres{
+		dbPool: dbPool,
+	}
+}
+
+func (p *RevocationsPostgres) Insert(tokenID string, subject string, revokedBy string, reason string, revokedAt time.Time, expiresAt *time.Time) (RevocationDetails, error) {
+	revocation := RevocationDetails{}
+
+	err := scanRevocation(p.dbPool.QueryRow(context.Background(), "SELECT * FROM public.insert_revoked_token($1, $2, $3, $4, $5, $6)",
+		nullableString(tokenID), nullableString(subject), revokedBy, reason, revokedAt, expiresAt), &revocation)
+
+	return revocation, err
+}
+
+func (p *RevocationsPostgres) GetAll() ([]RevocationDetails, error) {
+	rows, err := p.dbPool.Query(context.Background(), "SELECT * FROM public.get_revoked_tokens()")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revocations := make([]RevocationDetails, 0)
+	for rows.Next() {
+		var revocation RevocationDetails
+
+		if err := scanRevocation(rows, &revocation); err != nil {
+			return nil, err
+		}
+
+		revocations = append(revocations, revocation)
+	}
+
+	return revocations, rows.Err()
+}
+
+func (p *RevocationsPostgres) DeleteExpired(now time.Time) (int, error) {
+	deleted := 0
+
+	err := p.dbPool.QueryRow(context.Background(), "SELECT public.delete_expired_revoked_tokens($1)", now).Scan(&deleted)
+
+	return deleted, err
+}
+
+// Helper to store the empty string as NULL
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func scanRevocation(row pgx.Row, revocation *RevocationDetails) error {
+	var tokenID, subject *string
+
+	err := row.Scan(
+		&revocation.UUID,
+		&tokenID,
+		&subject,
+		&revocation.RevokedBy,
+		&revocation.RevokedAt,
+		&revocation.ExpiresAt,
+		&revocation.Reason)
+	if err != nil {
+		return err
+	}
+
+	if tokenID != nil {
+		revocation.TokenID = *tokenID
+	}
+
+	if subject != nil {
+		revocation.Subject = *subject
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+)
+
+// fakeRevocationData keeps the revocations in memory
+type fakeRevocationData struct {
+	revocations []RevocationDetails
+}
+
+func (f *fakeRevocationData) Insert(tokenID string, subject string, revokedBy string, reason string, revokedAt time.Time, expiresAt *time.Time) (RevocationDetails, error) {
+	revocation := RevocationDetails{
+		UUID:      "revocation",
+		TokenID:   tokenID,
+		Subject:   subject,
+		RevokedBy: revokedBy,
+		RevokedAt: revokedAt,
+		ExpiresAt: expiresAt,
+		Reason:    reason,
+	}
+	f.revocations = append(f.revocations, revocation)
+
+	return revocation, nil
+}
+
+func (f *fakeRevocationData) GetAll() ([]RevocationDetails, error) {
+	return f.revocations, nil
+}
+
+func (f *fakeRevocationData) DeleteExpired(now time.Time) (int, error) {
+	kept := []RevocationDetails{}
+
+	for _, revocation := range f.revocations {
+		if revocation.ExpiresAt == nil || revocation.ExpiresAt.After(now) {
+			kept = append(kept, revocation)
+		}
+	}
+
+	deleted := len(f.revocations) - len(kept)
+	f.revocations = kept
+
+	return deleted, nil
+}
+
+func TestRevocationsIsRevoked(t *testing.T) {
+	revokedAt := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := time.Now().Add(time.Hour)
+	expiredAt := time.Now().Add(-time.Hour)
+
+	revocations := NewRevocations(&fakeRevocationData{revocations: []RevocationDetails{
+		{TokenID: "leaked-jti", RevokedAt: revokedAt, ExpiresAt: &expiresAt},
+		{TokenID: "expired-jti", RevokedAt: revokedAt, ExpiresAt: &expiredAt},
+		{Subject: "alice", RevokedAt: revokedAt.Add(-time.Hour)},
+		{Subject: "alice", RevokedAt: revokedAt},
+	}})
+	if err := revocations.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		tokenID  string
+		subject  string
+		issuedAt time.Time
+		revoked  bool
+	}{
+		{"revoked jti", "leaked-jti", "bob", revokedAt.Add(time.Hour), true},
+		{"other jti", "other-jti", "bob", revokedAt.Add(-time.Hour), false},
+		{"expired jti", "expired-jti", "bob", revokedAt.Add(-time.Hour), false},
+		{"issued before the latest revocation", "other-jti", "alice", revokedAt.Add(-time.Minute), true},
+		{"issued at the revocation", "other-jti", "alice", revokedAt, true},
+		{"issued after", "other-jti", "alice", revokedAt.Add(time.Minute), false},
+		{"no issue time", "", "alice", time.Time{}, true},
+		{"no subject", "", "", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := revocations.IsRevoked(tt.tokenID, tt.subject, tt.issuedAt); got != tt.revoked {
+				t.Errorf("IsRevoked() = %v, want %v", got, tt.revoked)
+			}
+		})
+	}
+}
+
+func TestRevocationsRevoke(t *testing.T) {
+	log.InitLoggers(false)
+
+	admin := Principal{Subject: "admin", Permissions: []string{PermissionAdmin}}
+
+	tests := []struct {
+		name    string
+		tokenID string
+		subject string
+		err     error
+	}{
+		{"jti", "leaked-jti", "", nil},
+		{"subject", "", "alice", nil},
+		{"neither", " ", "", NewFieldError("jti", "")},
+		{"both", "leaked-jti", "alice", NewFieldError("jti", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revocations := NewRevocations(&fakeRevocationData{})
+
+			revocation, err := revocations.Revoke(admin, tt.tokenID, tt.subject, "leaked", nil)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Revoke() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			if revocation.RevokedBy != "admin" {
+				t.Errorf("Revoke() revokedBy = %q, want admin", revocation.RevokedBy)
+			}
+			if revocation.RevokedAt.Location() != time.UTC {
+				t.Errorf("Revoke() revokedAt = %v, want UTC", revocation.RevokedAt)
+			}
+			if (revocation.ExpiresAt != nil) != (tt.tokenID != "") {
+				t.Errorf("Revoke() expiresAt = %v", revocation.ExpiresAt)
+			}
+
+			// Denied right away, without the reload
+			if !revocations.IsRevoked(tt.tokenID, tt.subject, revocation.RevokedAt.Add(-time.Second)) {
+				t.Error("IsRevoked() = false for the token revoked by the instance")
+			}
+		})
+	}
+}
+
+func TestRevocationsExpiry(t *testing.T) {
+	log.InitLoggers(false)
+
+	admin := Principal{Subject: "admin", Permissions: []string{PermissionAdmin}}
+	berlin := time.FixedZone("UTC+2", 2*60*60)
+	expiresAt := time.Now().Add(time.Hour).In(berlin)
+	expiredAt := time.Now().Add(-time.Minute)
+
+	data := &fakeRevocationData{}
+	revocations := NewRevocations(data)
+
+	if _, err := revocations.Revoke(admin, "", "alice", "leaked", &expiresAt); !errors.Is(err, NewFieldError("expiresAt", "")) {
+		t.Errorf("Revoke() of the subject with expiresAt error = %v, want the field error", err)
+	}
+	if _, err := revocations.Revoke(admin, "old-jti", "", "leaked", &expiredAt); !errors.Is(err, NewFieldError("expiresAt", "")) {
+		t.Errorf("Revoke() of the expired token error = %v, want the field error", err)
+	}
+
+	revocation, err := revocations.Revoke(admin, "leaked-jti", "", "leaked", &expiresAt)
+	if err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if got := revocation.ExpiresAt; got == nil || got.Location() != time.UTC || !got.Equal(expiresAt) {
+		t.Errorf("Revoke() expiresAt = %v, want %v", got, expiresAt.UTC())
+	}
+
+	// The revocations of the expired tokens are dropped from the store and
+	// from the cache
+	data.revocations = append(data.revocations, RevocationDetails{TokenID: "expired-jti", ExpiresAt: &expiredAt})
+	if err := revocations.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if revocations.IsRevoked("expired-jti", "", time.Time{}) {
+		t.Error("IsRevoked() = true for the expired token")
+	}
+
+	if err := revocations.Prune(); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.revocations) != 1 || data.revocations[0].TokenID != "leaked-jti" {
+		t.Errorf("Prune() kept %+v, want the leaked-jti only", data.revocations)
+	}
+}
@@ -2,36 +2,185 @@ package models
 
 import "time"
 
+// Sandbox statuses as stored in the database
 const (
-	error_not_found       = "not found"
-	error_wrong_status    = "wrong status"
-	error_already_deleted = "already deleted"
+	StatusRunning = "RUNNING"
+	StatusStopped = "STOPPED"
+	StatusExpired = "EXPIRED"
+	StatusPending = "PENDING"
+	StatusFailed  = "FAILED"
+	StatusDeleted = "DELETED"
+	// Scheduled sandboxes wait for the StartAt to be provisioned
+	StatusScheduled = "SCHEDULED"
+	// Sandboxes exceeding the policy wait for the approval to be provisioned
+	StatusPendingApproval = "PENDING_APPROVAL"
+	// Sandboxes being deleted hold the name until the resources are removed
+	StatusDeleting = "DELETING"
+	// Sandboxes being stopped or started, so no other action runs meanwhile
+	StatusStopping = "STOPPING"
+	StatusStarting = "STARTING"
 )
 
 type SandboxDetails struct {
-	Name      string
-	UUID      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	ExpiresAt time.Time
-	Status    string
+	Name        string
+	UUID        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ExpiresAt   time.Time
+	Status      string
+	Version     int
+	Owner       string
+	Labels      map[string]string
+	Description string
+	CostCenter  string
+	Notes       string
+	// StartAt is set for the sandboxes created with the schedule
+	StartAt *time.Time
+	// KeepWhenIdle opts the sandbox out of the early expiry when idle
+	KeepWhenIdle bool
+	// IdleWarnedAt is set once the owner is warned the sandbox is idle
+	IdleWarnedAt *time.Time
+	// Role is assigned to the application of the sandbox on its resource group
+	Role string
+	// Template the resources of the sandbox are deployed from, empty for none
+	Template string
+}
+
+// Azure built-in roles the application of the sandbox can be assigned
+const (
+	RoleReader                  = "Reader"
+	RoleContributor             = "Contributor"
+	RoleOwner                   = "Owner"
+	RoleUserAccessAdministrator = "User Access Administrator"
+	DefaultRole                 = RoleContributor
+)
+
+// SandboxOptions are the resources requested for the new sandbox, the empty
+// role is the DefaultRole
+type SandboxOptions struct {
+	Role     string
+	Template string
+}
+
+// SandboxPatch holds the changes of the sandbox metadata, nil fields are left
+// as they are. Empty strings clear the text fields.
+type SandboxPatch struct {
+	ExpiresAt    *time.Time
+	Description  *string
+	CostCenter   *string
+	Notes        *string
+	KeepWhenIdle *bool
+	// Labels are merged into the existing ones, nil values remove the labels
+	Labels map[string]*string
+	// ClearLabels removes all the existing labels before the merge
+	ClearLabels bool
+}
+
+// Empty is true if the patch doesn't change anything
+func (p SandboxPatch) Empty() bool {
+	return p.ExpiresAt == nil && p.Description == nil && p.CostCenter == nil && p.Notes == nil &&
+		p.KeepWhenIdle == nil && len(p.Labels) == 0 && !p.ClearLabels
+}
+
+// Fields the sandboxes can be sorted by
+const (
+	SortByCreatedAt = "createdAt"
+	SortByUpdatedAt = "updatedAt"
+	SortByExpiresAt = "expiresAt"
+	SortByName      = "name"
+)
+
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// SandboxFilter selects the sandboxes to list, empty fields don't filter anything
+type SandboxFilter struct {
+	Statuses       []string
+	Owner          string
+	Name           string
+	NamePrefix     string
+	Selector       LabelSelector
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	ExpiresAfter   *time.Time
+	ExpiresBefore  *time.Time
+	ExpiringWithin time.Duration
+	SortBy         string
+	SortOrder      string
+	Limit          int
+	Offset         int
+
+	// After continues the listing after the cursor, instead of the Offset
+	After *Cursor
+	// WithTotal requests the number of all the sandboxes matching the filter
+	WithTotal bool
+}
+
+// SandboxPage is a single page of the sandbox listing
+type SandboxPage struct {
+	Sandboxes []SandboxDetails
+	// Next points to the start of the next page, nil on the last page
+	Next *Cursor
+	// Total is the number of all the sandboxes matching the filter, -1 if not requested
+	Total int
 }
 
 type SandboxData interface {
-	Insert(name string, expireTime time.Time) (string, error)
+	// startAt schedules the sandbox, nil means the sandbox is provisioned right
+	// away. The sandbox pending approval waits for the decision first.
+	Insert(name string, expireTime time.Time, startAt *time.Time, owner string, labels map[string]string, options SandboxOptions, pendingApproval bool) (string, error)
 	Delete(id string) (bool, error)
-	GetAll(limit int, offset int) ([]SandboxDetails, error)
-	GetByName(name string) ([]SandboxDetails, error)
+	GetAll(filter SandboxFilter) ([]SandboxDetails, error)
+	Count(filter SandboxFilter) (int, error)
 	GetByID(id string) (SandboxDetails, error)
-	UpdateExpiration(id string, expiresAt time.Time) (bool, error)
-	UpdateStatus(id string, status string) (bool, error)
+	GetActiveByName(name string) (SandboxDetails, error)
+	// version is the expected version of the sandbox, 0 skips the check
+	Update(id string, patch SandboxPatch, actor string, version int) (bool, error)
+	UpdateStatus(id string, status string, version int) (bool, error)
+	// UpdateIdleWarning sets the time the owner was warned, nil clears the warning
+	UpdateIdleWarning(id string, warnedAt *time.Time, actor string) (bool, error)
+	// ExpireIdle expires the sandbox, only if it is still warned at warnedAt
+	ExpireIdle(id string, warnedAt time.Time, actor string) (bool, error)
+	// ClaimScheduled moves up to limit due scheduled sandboxes to PENDING and
+	// returns their ids, the claims older than claimTimeout are taken over if
+	// the provisioning didn't start
+	ClaimScheduled(limit int, claimTimeout time.Duration) ([]string, error)
 }
 
+// The actions take the principal making them. The sandboxes are created for
+// the principal, the existing ones of someone else are refused with
+// ErrNotOwner unless the principal is an admin.
 type SandboxController interface { //TODO: find a better name
-	Create(name string, expireTime time.Time) (SandboxDetails, error)
-	Remove(id string) (SandboxDetails, error)
-	ListAll(limit int, offset int) ([]SandboxDetails, error)
-	GetByName(name string) ([]SandboxDetails, error)
+	Create(name string, expireTime time.Time, startAt *time.Time, principal Principal, labels map[string]string, options SandboxOptions) (SandboxDetails, OperationDetails, error)
+	// ifMatch is the expected version of the sandbox, 0 skips the check
+	Remove(id string, principal Principal, ifMatch int) (OperationDetails, error)
+	// ForceRemove and Reconcile are for the admins only
+	ForceRemove(id string, principal Principal) (OperationDetails, error)
+	Reconcile(principal Principal, dryRun bool) ([]ReconcileResult, error)
+	Stop(id string, principal Principal) (OperationDetails, error)
+	Start(id string, principal Principal) (OperationDetails, error)
+	ListAll(filter SandboxFilter) (SandboxPage, error)
 	GetByUUID(id string) (SandboxDetails, error)
-	UpdateExpiration(id string, expiresAt time.Time) (SandboxDetails, error)
+	ResolveByName(name string) (SandboxDetails, error)
+	Update(id string, patch SandboxPatch, principal Principal, ifMatch int) (SandboxDetails, OperationDetails, error)
+	GetOperation(id string) (OperationDetails, error)
+	CancelOperation(id string, principal Principal) (OperationDetails, error)
+	GetSchedule(id string) (SandboxSchedule, error)
+	SetSchedule(id string, stopCron string, startCron string, timezone string, principal Principal) (SandboxSchedule, error)
+	DeleteSchedule(id string, principal Principal) error
+	SkipSchedule(id string, action string, principal Principal) (SandboxSchedule, error)
+	GetCatalogSchedule(catalog string) (CatalogSchedule, error)
+	SetCatalogSchedule(catalog string, stopCron string, startCron string, timezone string, principal Principal) (CatalogSchedule, error)
+	DeleteCatalogSchedule(catalog string, principal Principal) error
+	ListApprovals(status string, limit int, offset int) ([]ApprovalDetails, error)
+	GetApproval(id string) (ApprovalDetails, error)
+	Approve(id string, principal Principal, comment string) (ApprovalDetails, error)
+	Deny(id string, principal Principal, comment string) (ApprovalDetails, error)
 }
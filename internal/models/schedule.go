@@ -0,0 +1,514 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/makirill/sandbox-azure/internal/log"
+	"github.com/robfig/cron/v3"
+)
+
+// Actor of the history records made by the scheduler
+const schedulerActor = "scheduler"
+
+// Results of the schedule runs as recorded in the history
+const (
+	scheduleRunStarted = "started"
+	scheduleRunSkipped = "skipped"
+	scheduleRunFailed  = "failed"
+)
+
+// Helper to parse the cron expression of the schedule field. The timezone is
+// a separate field, so the TZ prefixes of the cron library are not allowed.
+func parseCron(field string, spec string) (cron.Schedule, error) {
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		return nil, NewFieldError(field, "must not set the timezone, use the timezone field")
+	}
+	if strings.HasPrefix(spec, "@every") {
+		return nil, NewFieldError(field, "must be a cron expression, intervals are not supported")
+	}
+
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, NewFieldError(field, "is not a valid cron expression: "+err.Error())
+	}
+
+	return schedule, nil
+}
+
+// Helper to get the next run after the time, in UTC as it is stored
+func nextRun(schedule cron.Schedule, location *time.Location, after time.Time) time.Time {
+	return schedule.Next(after.In(location)).UTC()
+}
+
+// newSandboxSchedule validates the schedule and computes its next runs
+func newSandboxSchedule(sandboxID string, stopCron string, startCron string, timezone string) (SandboxSchedule, error) {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	errs := fieldErrors{}
+
+	stop, err := parseCron("stopCron", stopCron)
+	errs.add(err)
+
+	var start cron.Schedule
+	if startCron != "" {
+		start, err = parseCron("startCron", startCron)
+		errs.add(err)
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		errs.addField("timezone", "is not a known IANA timezone")
+	}
+
+	if err := errs.err(); err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	now := time.Now()
+
+	schedule := SandboxSchedule{
+		SandboxID:  sandboxID,
+		StopCron:   stopCron,
+		StartCron:  startCron,
+		Timezone:   timezone,
+		NextStopAt: nextRun(stop, location, now),
+	}
+
+	// Expressions like 30 February never match
+	if schedule.NextStopAt.IsZero() {
+		errs.addField("stopCron", "never runs")
+	}
+
+	if start != nil {
+		nextStartAt := nextRun(start, location, now)
+		if nextStartAt.IsZero() {
+			errs.addField("startCron", "never runs")
+		}
+		schedule.NextStartAt = &nextStartAt
+	}
+
+	return schedule, errs.err()
+}
+
+// Helper to get the next run of the action after the given one
+func (schedule SandboxSchedule) nextAfter(action string, after time.Time) (time.Time, error) {
+	spec := schedule.StopCron
+	if action == ScheduleStart {
+		spec = schedule.StartCron
+	}
+
+	parsed, err := parseCron(action+"Cron", spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return nextRun(parsed, location, after), nil
+}
+
+// Helper to get the next run of the action, nil if the action is not scheduled
+func (schedule SandboxSchedule) next(action string) *time.Time {
+	if action == ScheduleStart {
+		return schedule.NextStartAt
+	}
+
+	return &schedule.NextStopAt
+}
+
+func (s *AzureSandbox) GetSchedule(id string) (SandboxSchedule, error) {
+	if err := validateID(id); err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	return s.schedules.Get(id)
+}
+
+// SetSchedule replaces the schedule of the sandbox, the next runs start from now
+func (s *AzureSandbox) SetSchedule(id string, stopCron string, startCron string, timezone string, principal Principal) (SandboxSchedule, error) {
+	if err := validateID(id); err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	schedule, err := newSandboxSchedule(id, stopCron, startCron, timezone)
+	if err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	details, err := s.instances.GetByID(id)
+	if err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	if err := principal.canActOn(details); err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	if details.Status == StatusDeleted {
+		return SandboxSchedule{}, ErrAlreadyDeleted
+	}
+
+	if err := s.schedules.Upsert(schedule); err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	s.addScheduleHistory(id, "schedule", map[string]string{
+		"stopCron":  schedule.StopCron,
+		"startCron": schedule.StartCron,
+		"timezone":  schedule.Timezone,
+	}, principal.Subject)
+
+	return schedule, nil
+}
+
+// DeleteSchedule deletes the schedule set for the sandbox, the sandbox falls
+// back to the schedule of its catalog, if there is one. The inherited schedule
+// is changed with the catalog schedule only.
+func (s *AzureSandbox) DeleteSchedule(id string, principal Principal) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	details, err := s.instances.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := principal.canActOn(details); err != nil {
+		return err
+	}
+
+	schedule, err := s.schedules.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if schedule.Catalog != "" {
+		return ErrScheduleInherited
+	}
+
+	ok, err := s.schedules.Delete(id)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrScheduleNotFound
+	}
+
+	s.addScheduleHistory(id, "schedule", nil, principal.Subject)
+
+	s.inheritSchedule(id, details.Labels)
+
+	return nil
+}
+
+// Helper to get the catalog of the sandbox by its labels
+func catalogOf(labels map[string]string) string {
+	if catalog := labels[CatalogLabel]; catalog != "" {
+		return catalog
+	}
+
+	return DefaultCatalog
+}
+
+// inheritSchedule copies the schedule of the catalog to the sandbox, e.g. once
+// it is created or moved to another catalog. The schedule set for the sandbox
+// itself is kept. The failure doesn't fail the request, the sandbox is
+// changed already.
+func (s *AzureSandbox) inheritSchedule(id string, labels map[string]string) {
+	catalog := catalogOf(labels)
+
+	err := func() error {
+		current, err := s.schedules.Get(id)
+		switch {
+		case err == nil && current.Catalog == "":
+			return nil
+		case err != nil && !errors.Is(err, ErrScheduleNotFound):
+			return err
+		}
+		inherited := err == nil
+
+		shared, err := s.schedules.GetCatalog(catalog)
+		if errors.Is(err, ErrScheduleNotFound) {
+			// The schedule of the previous catalog doesn't apply anymore
+			if inherited {
+				_, err = s.schedules.Delete(id)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if inherited && current.Catalog == catalog {
+			return nil
+		}
+
+		schedule, err := newSandboxSchedule(id, shared.StopCron, shared.StartCron, shared.Timezone)
+		if err != nil {
+			return err
+		}
+		schedule.Catalog = catalog
+
+		return s.schedules.Upsert(schedule)
+	}()
+	if err != nil {
+		log.Logger.Error("Failed to inherit catalog schedule", "id", id, "catalog", catalog, "err", err)
+	}
+}
+
+// Helper to check the name of the catalog before it gets to the database
+func validateCatalog(catalog string) error {
+	if catalog == "" || len(catalog) > MaxLabelValueLength {
+		return NewFieldError("catalog", fmt.Sprintf("must be between 1 and %d characters", MaxLabelValueLength))
+	}
+
+	return nil
+}
+
+func (s *AzureSandbox) GetCatalogSchedule(catalog string) (CatalogSchedule, error) {
+	if err := validateCatalog(catalog); err != nil {
+		return CatalogSchedule{}, err
+	}
+
+	return s.schedules.GetCatalog(catalog)
+}
+
+// SetCatalogSchedule replaces the schedule of the catalog, the sandboxes of
+// the catalog without their own schedule get it with the next runs from now
+func (s *AzureSandbox) SetCatalogSchedule(catalog string, stopCron string, startCron string, timezone string, principal Principal) (CatalogSchedule, error) {
+	if !principal.Has(PermissionAdmin) {
+		return CatalogSchedule{}, ErrAdminRequired
+	}
+
+	if err := validateCatalog(catalog); err != nil {
+		return CatalogSchedule{}, err
+	}
+
+	runs, err := newSandboxSchedule("", stopCron, startCron, timezone)
+	if err != nil {
+		return CatalogSchedule{}, err
+	}
+
+	schedule := CatalogSchedule{
+		Catalog:   catalog,
+		StopCron:  runs.StopCron,
+		StartCron: runs.StartCron,
+		Timezone:  runs.Timezone,
+	}
+
+	if err := s.schedules.UpsertCatalog(schedule, runs); err != nil {
+		return CatalogSchedule{}, err
+	}
+
+	return schedule, nil
+}
+
+// DeleteCatalogSchedule deletes the schedule of the catalog along with the
+// copies its sandboxes inherited
+func (s *AzureSandbox) DeleteCatalogSchedule(catalog string, principal Principal) error {
+	if !principal.Has(PermissionAdmin) {
+		return ErrAdminRequired
+	}
+
+	if err := validateCatalog(catalog); err != nil {
+		return err
+	}
+
+	ok, err := s.schedules.DeleteCatalog(catalog)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrScheduleNotFound
+	}
+
+	return nil
+}
+
+// SkipSchedule moves the next run of the action to the one after it
+func (s *AzureSandbox) SkipSchedule(id string, action string, principal Principal) (SandboxSchedule, error) {
+	if err := validateID(id); err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	if action != ScheduleStop && action != ScheduleStart {
+		return SandboxSchedule{}, NewFieldError("action", "must be stop or start")
+	}
+
+	if err := s.canActOn(principal, id); err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	schedule, err := s.schedules.Get(id)
+	if err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	due := schedule.next(action)
+	if due == nil {
+		return SandboxSchedule{}, NewFieldError("action", "is not scheduled")
+	}
+
+	next, err := schedule.nextAfter(action, *due)
+	if err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	// Fails if the scheduler ran the action or the schedule was replaced meanwhile
+	ok, err := s.schedules.Advance(id, action, *due, next)
+	if err != nil {
+		return SandboxSchedule{}, err
+	}
+
+	if !ok {
+		return SandboxSchedule{}, ErrScheduleChanged
+	}
+
+	s.addScheduleHistory(id, "schedule."+action, map[string]interface{}{
+		"result":      scheduleRunSkipped,
+		"scheduledAt": *due,
+		"reason":      "skipped on request",
+	}, principal.Subject)
+
+	if action == ScheduleStart {
+		schedule.NextStartAt = &next
+	} else {
+		schedule.NextStopAt = next
+	}
+
+	return schedule, nil
+}
+
+// dueRun is the due action of the schedule, it is only recorded as skipped
+// unless run is set
+type dueRun struct {
+	action string
+	run    bool
+}
+
+// dueRuns returns the due actions of the schedule in the order they were
+// due. If both the stop and the start are due, e.g. after a downtime, only
+// the latest one is run.
+func (schedule SandboxSchedule) dueRuns(now time.Time) []dueRun {
+	stopDue := !schedule.NextStopAt.After(now)
+	startDue := schedule.NextStartAt != nil && !schedule.NextStartAt.After(now)
+
+	switch {
+	case stopDue && startDue && schedule.NextStopAt.Before(*schedule.NextStartAt):
+		return []dueRun{{ScheduleStop, false}, {ScheduleStart, true}}
+	case stopDue && startDue:
+		return []dueRun{{ScheduleStart, false}, {ScheduleStop, true}}
+	case stopDue:
+		return []dueRun{{ScheduleStop, true}}
+	case startDue:
+		return []dueRun{{ScheduleStart, true}}
+	}
+
+	return nil
+}
+
+// runSchedules runs the due actions of the schedules, see dueRuns
+func (s *AzureSandbox) runSchedules() {
+	schedules, err := s.schedules.GetDue(scheduledBatchSize)
+	if err != nil {
+		log.Logger.Error("Failed to get due schedules", "err", err)
+		return
+	}
+
+	now := time.Now()
+
+	for _, schedule := range schedules {
+		for _, due := range schedule.dueRuns(now) {
+			s.runSchedule(schedule, due.action, now, due.run)
+		}
+	}
+}
+
+// runSchedule claims the due run of the action and, if run is set, stops or
+// starts the sandbox. The outcome is recorded in the history.
+func (s *AzureSandbox) runSchedule(schedule SandboxSchedule, action string, now time.Time, run bool) {
+	id := schedule.SandboxID
+	due := *schedule.next(action)
+
+	// The missed runs are not caught up, the next run is always in the future
+	next, err := schedule.nextAfter(action, now)
+	if err != nil {
+		log.Logger.Error("Failed to parse schedule", "id", id, "action", action, "err", err)
+		return
+	}
+
+	// Only one of the API instances gets to run it
+	ok, err := s.schedules.Advance(id, action, due, next)
+	if err != nil {
+		log.Logger.Error("Failed to advance schedule", "id", id, "action", action, "err", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	record := map[string]interface{}{"scheduledAt": due}
+
+	switch {
+	case !run:
+		record["result"] = scheduleRunSkipped
+		record["reason"] = "superseded by a later run"
+	default:
+		operation, err := s.runScheduleAction(id, action)
+		switch {
+		case errors.Is(err, ErrWrongStatus) && action == ScheduleStart:
+			record["result"] = scheduleRunSkipped
+			record["reason"] = "sandbox is not stopped"
+		case errors.Is(err, ErrWrongStatus):
+			record["result"] = scheduleRunSkipped
+			record["reason"] = "sandbox is not running"
+		case err != nil:
+			log.Logger.Error("Failed to run schedule", "id", id, "action", action, "err", err)
+			record["result"] = scheduleRunFailed
+			record["code"] = scheduleErrorCode(err)
+		default:
+			log.Logger.Info("Schedule run", "id", id, "action", action, "operation", operation.UUID)
+			record["result"] = scheduleRunStarted
+			record["operation"] = operation.UUID
+		}
+	}
+
+	s.addScheduleHistory(id, "schedule."+action, record, schedulerActor)
+}
+
+// Helper to record the failed run in the history, which the clients see, so
+// only the codes of the domain errors are kept. The rest goes to the logs.
+func scheduleErrorCode(err error) string {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+
+	return "ScheduleRunFailed"
+}
+
+func (s *AzureSandbox) runScheduleAction(id string, action string) (OperationDetails, error) {
+	if action == ScheduleStart {
+		return s.Start(id, systemPrincipal(schedulerActor))
+	}
+
+	return s.Stop(id, systemPrincipal(schedulerActor))
+}
+
+// Helper to record the schedule event, failing to do so doesn't fail the request
+func (s *AzureSandbox) addScheduleHistory(id string, field string, value interface{}, actor string) {
+	if err := s.schedules.AddHistory(id, field, value, actor); err != nil {
+		log.Logger.Error("Failed to record schedule history", "id", id, "field", field, "err", err)
+	}
+}
@@ -0,0 +1,285 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		spec  string
+		valid bool
+	}{
+		{"0 19 * * 1-5", true},
+		{"*/15 8-18 * * *", true},
+		{"0 0 1 * *", true},
+		{"@daily", true},
+		{"", false},
+		{"0 19 * *", false},
+		{"0 19 * * * *", false},
+		{"61 19 * * *", false},
+		{"@every 1h", false},
+		{"TZ=Europe/Berlin 0 19 * * *", false},
+		{"CRON_TZ=Europe/Berlin 0 19 * * *", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := parseCron("stopCron", tt.spec)
+
+			if tt.valid && err != nil {
+				t.Errorf("parseCron(%q) error = %v, want nil", tt.spec, err)
+			}
+
+			if !tt.valid {
+				var domainErr *Error
+				if !errors.As(err, &domainErr) || domainErr.Fields[0].Field != "stopCron" {
+					t.Errorf("parseCron(%q) error = %v, want the stopCron field error", tt.spec, err)
+				}
+			}
+		})
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone database is not available")
+	}
+
+	tests := []struct {
+		name     string
+		spec     string
+		location *time.Location
+		after    time.Time
+		want     time.Time
+	}{
+		{
+			name:     "later the same day",
+			spec:     "0 19 * * *",
+			location: time.UTC,
+			after:    time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+			want:     time.Date(2023, 5, 1, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "at the run",
+			spec:     "0 19 * * *",
+			location: time.UTC,
+			after:    time.Date(2023, 5, 1, 19, 0, 0, 0, time.UTC),
+			want:     time.Date(2023, 5, 2, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "skips the weekend",
+			spec:     "0 8 * * 1-5",
+			location: time.UTC,
+			after:    time.Date(2023, 5, 5, 9, 0, 0, 0, time.UTC),
+			want:     time.Date(2023, 5, 8, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "summer time",
+			spec:     "0 19 * * *",
+			location: berlin,
+			after:    time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC),
+			want:     time.Date(2023, 7, 1, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "winter time",
+			spec:     "0 19 * * *",
+			location: berlin,
+			after:    time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC),
+			want:     time.Date(2023, 12, 1, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "never",
+			spec:     "0 0 30 2 *",
+			location: time.UTC,
+			after:    time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+			want:     time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCron("stopCron", tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := nextRun(schedule, tt.location, tt.after)
+			if !got.Equal(tt.want) {
+				t.Errorf("nextRun() = %v, want %v", got, tt.want)
+			}
+			if !got.IsZero() && got.Location() != time.UTC {
+				t.Errorf("nextRun() location = %v, want UTC", got.Location())
+			}
+		})
+	}
+}
+
+func TestDueRuns(t *testing.T) {
+	now := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name        string
+		nextStopAt  time.Time
+		nextStartAt *time.Time
+		want        []dueRun
+	}{
+		{"nothing due", now.Add(time.Hour), at(2 * time.Hour), nil},
+		{"stop due", now, at(time.Hour), []dueRun{{ScheduleStop, true}}},
+		{"stop only schedule", now.Add(-time.Minute), nil, []dueRun{{ScheduleStop, true}}},
+		{"start due", now.Add(time.Hour), at(0), []dueRun{{ScheduleStart, true}}},
+		{
+			name:        "start is the latest",
+			nextStopAt:  now.Add(-2 * time.Hour),
+			nextStartAt: at(-time.Hour),
+			want:        []dueRun{{ScheduleStop, false}, {ScheduleStart, true}},
+		},
+		{
+			name:        "stop is the latest",
+			nextStopAt:  now.Add(-time.Hour),
+			nextStartAt: at(-2 * time.Hour),
+			want:        []dueRun{{ScheduleStart, false}, {ScheduleStop, true}},
+		},
+		{
+			name:        "both at once",
+			nextStopAt:  now.Add(-time.Hour),
+			nextStartAt: at(-time.Hour),
+			want:        []dueRun{{ScheduleStart, false}, {ScheduleStop, true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := SandboxSchedule{NextStopAt: tt.nextStopAt, NextStartAt: tt.nextStartAt}
+
+			if got := schedule.dueRuns(now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dueRuns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeScheduleData holds the schedules of the sandboxes and the catalogs in
+// memory, the inherited schedule doesn't replace the one set for the sandbox
+type fakeScheduleData struct {
+	ScheduleData
+	sandboxes map[string]SandboxSchedule
+	catalogs  map[string]CatalogSchedule
+}
+
+func (f *fakeScheduleData) Upsert(schedule SandboxSchedule) error {
+	if current, ok := f.sandboxes[schedule.SandboxID]; ok && current.Catalog == "" && schedule.Catalog != "" {
+		return nil
+	}
+
+	f.sandboxes[schedule.SandboxID] = schedule
+
+	return nil
+}
+
+func (f *fakeScheduleData) Get(sandboxID string) (SandboxSchedule, error) {
+	schedule, ok := f.sandboxes[sandboxID]
+	if !ok {
+		return SandboxSchedule{}, ErrScheduleNotFound
+	}
+
+	return schedule, nil
+}
+
+func (f *fakeScheduleData) Delete(sandboxID string) (bool, error) {
+	_, ok := f.sandboxes[sandboxID]
+	delete(f.sandboxes, sandboxID)
+
+	return ok, nil
+}
+
+func (f *fakeScheduleData) GetCatalog(catalog string) (CatalogSchedule, error) {
+	schedule, ok := f.catalogs[catalog]
+	if !ok {
+		return CatalogSchedule{}, ErrScheduleNotFound
+	}
+
+	return schedule, nil
+}
+
+func TestInheritSchedule(t *testing.T) {
+	nightly := CatalogSchedule{Catalog: "training", StopCron: "0 19 * * *", StartCron: "0 7 * * 1-5", Timezone: "UTC"}
+	weekly := CatalogSchedule{Catalog: DefaultCatalog, StopCron: "0 19 * * 5", Timezone: "UTC"}
+
+	own := SandboxSchedule{SandboxID: testSandboxID, StopCron: "0 22 * * *", Timezone: "UTC"}
+	inherited := SandboxSchedule{SandboxID: testSandboxID, StopCron: "0 19 * * *", Timezone: "UTC", Catalog: "training"}
+
+	tests := []struct {
+		name     string
+		current  *SandboxSchedule
+		catalogs []CatalogSchedule
+		labels   map[string]string
+		// want is the stop cron of the sandbox schedule after, empty if none
+		want        string
+		wantCatalog string
+	}{
+		{"catalog label", nil, []CatalogSchedule{nightly, weekly}, map[string]string{CatalogLabel: "training"}, "0 19 * * *", "training"},
+		{"default catalog", nil, []CatalogSchedule{nightly, weekly}, nil, "0 19 * * 5", DefaultCatalog},
+		{"no catalog schedule", nil, []CatalogSchedule{weekly}, map[string]string{CatalogLabel: "training"}, "", ""},
+		{"own schedule kept", &own, []CatalogSchedule{nightly}, map[string]string{CatalogLabel: "training"}, "0 22 * * *", ""},
+		{"moved to catalog without schedule", &inherited, []CatalogSchedule{nightly}, map[string]string{CatalogLabel: "demo"}, "", ""},
+		{"moved to default catalog", &inherited, []CatalogSchedule{nightly, weekly}, nil, "0 19 * * 5", DefaultCatalog},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &fakeScheduleData{sandboxes: map[string]SandboxSchedule{}, catalogs: map[string]CatalogSchedule{}}
+			if tt.current != nil {
+				data.sandboxes[testSandboxID] = *tt.current
+			}
+			for _, catalog := range tt.catalogs {
+				data.catalogs[catalog.Catalog] = catalog
+			}
+
+			s := &AzureSandbox{schedules: data}
+			s.inheritSchedule(testSandboxID, tt.labels)
+
+			schedule, ok := data.sandboxes[testSandboxID]
+			if tt.want == "" {
+				if ok {
+					t.Errorf("inheritSchedule() left %+v, want no schedule", schedule)
+				}
+				return
+			}
+
+			if !ok || schedule.StopCron != tt.want || schedule.Catalog != tt.wantCatalog {
+				t.Errorf("inheritSchedule() left %+v, want stop %q of catalog %q", schedule, tt.want, tt.wantCatalog)
+			}
+		})
+	}
+}
+
+func TestSetCatalogScheduleRequiresAdmin(t *testing.T) {
+	s := &AzureSandbox{schedules: &fakeScheduleData{}}
+
+	writer := Principal{Subject: "alice", Permissions: []string{PermissionWrite}}
+	if _, err := s.SetCatalogSchedule("training", "0 19 * * *", "", "", writer); !errors.Is(err, ErrAdminRequired) {
+		t.Errorf("SetCatalogSchedule() error = %v, want %v", err, ErrAdminRequired)
+	}
+	if err := s.DeleteCatalogSchedule("training", writer); !errors.Is(err, ErrAdminRequired) {
+		t.Errorf("DeleteCatalogSchedule() error = %v, want %v", err, ErrAdminRequired)
+	}
+}
+
+func TestScheduleErrorCode(t *testing.T) {
+	if got := scheduleErrorCode(ErrWrongStatus.Wrap(errors.New("status changed"))); got != ErrWrongStatus.Code {
+		t.Errorf("scheduleErrorCode() = %s, want %s", got, ErrWrongStatus.Code)
+	}
+
+	// The details of the other errors are not shown to the clients
+	if got := scheduleErrorCode(errors.New("dial tcp 10.0.0.1:5432: connection refused")); got != "ScheduleRunFailed" {
+		t.Errorf("scheduleErrorCode() = %s, want ScheduleRunFailed", got)
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Actions of the sandbox schedule
+const (
+	ScheduleStop  = "stop"
+	ScheduleStart = "start"
+)
+
+// The label of the sandboxes telling their catalog, the sandboxes without it
+// are in the DefaultCatalog
+const (
+	CatalogLabel   = "catalog"
+	DefaultCatalog = "default"
+)
+
+// SandboxSchedule stops the sandbox and starts it again on the cron
+// expressions, evaluated in the Timezone. The next runs are in UTC.
+type SandboxSchedule struct {
+	SandboxID string
+	StopCron  string
+	// StartCron is empty if the sandbox is only stopped
+	StartCron   string
+	Timezone    string
+	NextStopAt  time.Time
+	NextStartAt *time.Time
+	// Catalog is set if the schedule is inherited from the catalog schedule,
+	// empty if it is set for the sandbox itself
+	Catalog string
+}
+
+// CatalogSchedule is shared by the sandboxes of the catalog without their own
+// schedule, every sandbox gets a copy of it to run and skip on its own
+type CatalogSchedule struct {
+	Catalog   string
+	StopCron  string
+	StartCron string
+	Timezone  string
+}
+
+type ScheduleData interface {
+	// Upsert sets the schedule of the sandbox, the inherited one, Catalog set,
+	// doesn't replace the schedule set for the sandbox itself
+	Upsert(schedule SandboxSchedule) error
+	Get(sandboxID string) (SandboxSchedule, error)
+	Delete(sandboxID string) (bool, error)
+	// UpsertCatalog sets the catalog schedule and applies it, with the next
+	// runs of the runs schedule, to the sandboxes of the catalog
+	UpsertCatalog(schedule CatalogSchedule, runs SandboxSchedule) error
+	GetCatalog(catalog string) (CatalogSchedule, error)
+	// DeleteCatalog deletes the catalog schedule and the copies of it
+	DeleteCatalog(catalog string) (bool, error)
+	// GetDue returns up to limit schedules with a run due
+	GetDue(limit int) ([]SandboxSchedule, error)
+	// Advance moves the next run of the action, only if it is still at the from time
+	Advance(sandboxID string, action string, from time.Time, to time.Time) (bool, error)
+	// AddHistory records the run of the schedule in the sandbox history
+	AddHistory(sandboxID string, field string, value interface{}, actor string) error
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Make sure we conform to the ScheduleData interface
+var _ ScheduleData = (*SchedulesPostgres)(nil)
+
+type SchedulesPostgres struct {
+	dbPool *pgxpool.Pool
+}
+
+func NewSchedulesPostgres(dbPool *pgxpool.Pool) *SchedulesPostgres {
+
+	return &SchedulesPostgres{
+		dbPool: dbPool,
+	}
+}
+
+func (p *SchedulesPostgres) Upsert(schedule SandboxSchedule) error {
+	_, err := p.dbPool.Exec(context.Background(), "SELECT public.upsert_sandbox_schedule($1, $2, $3, $4, $5, $6, $7)",
+		schedule.SandboxID,
+		schedule.StopCron,
+		schedule.StartCron,
+		schedule.Timezone,
+		schedule.NextStopAt,
+		schedule.NextStartAt,
+		schedule.Catalog)
+
+	return err
+}
+
+func (p *SchedulesPostgres) UpsertCatalog(schedule CatalogSchedule, runs SandboxSchedule) error {
+	_, err := p.dbPool.Exec(context.Background(), "SELECT public.upsert_catalog_schedule($1, $2, $3, $4, $5, $6)",
+		schedule.Catalog,
+		schedule.StopCron,
+		schedule.StartCron,
+		schedule.Timezone,
+		runs.NextStopAt,
+		runs.NextStartAt)
+
+	return err
+}
+
+func (p *SchedulesPostgres) GetCatalog(catalog string) (CatalogSchedule, error) {
+	schedule := CatalogSchedule{}
+
+	err := p.dbPool.QueryRow(context.Background(), "SELECT * FROM public.get_catalog_schedule($1)", catalog).Scan(
+		&schedule.Catalog,
+		&schedule.StopCron,
+		&schedule.StartCron,
+		&schedule.Timezone)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return CatalogSchedule{}, ErrScheduleNotFound
+	}
+
+	return schedule, err
+}
+
+func (p *SchedulesPostgres) DeleteCatalog(catalog string) (bool, error) {
+	ok := false
+
+	err := p.dbPool.QueryRow(context.Background(), "SELECT public.delete_catalog_schedule($1)", catalog).Scan(&ok)
+
+	return ok, err
+}
+
+func (p *SchedulesPostgres) Get(sandboxID string) (SandboxSchedule, error) {
+	schedule := SandboxSchedule{}
+
+	err := scanSchedule(p.dbPool.QueryRow(context.Background(), "SELECT * FROM public.get_sandbox_schedule($1)", sandboxID), &schedule)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return SandboxSchedule{}, ErrScheduleNotFound
+	}
+
+	return schedule, err
+}
+
+func (p *SchedulesPostgres) Delete(sandboxID string) (bool, error) {
+	ok := false
+
+	err := p.dbPool.QueryRow(context.Background(), "SELECT public.delete_sandbox_schedule($1)", sandboxID).Scan(&ok)
+
+	return ok, err
+}
+
+func (p *SchedulesPostgres) GetDue(limit int) ([]SandboxSchedule, error) {
+	rows, err := p.dbPool.Query(context.Background(), "SELECT * FROM public.get_due_sandbox_schedules($1)", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []SandboxSchedule{}
+	for rows.Next() {
+		var schedule SandboxSchedule
+
+		if err := scanSchedule(rows, &schedule); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+func (p *SchedulesPostgres) Advance(sandboxID string, action string, from time.Time, to time.Time) (bool, error) {
+	ok := false
+
+	err := p.dbPool.QueryRow(context.Background(), "SELECT public.advance_sandbox_schedule($1, $2, $3, $4)",
+		sandboxID, action, from, to).Scan(&ok)
+
+	return ok, err
+}
+
+func (p *SchedulesPostgres) AddHistory(sandboxID string, field string, value interface{}, actor string) error {
+	_, err := p.dbPool.Exec(context.Background(), "SELECT public.insert_sandbox_history($1, $2, NULL, $3, $4)",
+		sandboxID, field, value, actor)
+
+	return err
+}
+
+func scanSchedule(row pgx.Row, schedule *SandboxSchedule) error {
+	return row.Scan(
+		&schedule.SandboxID,
+		&schedule.StopCron,
+		&schedule.StartCron,
+		&schedule.Timezone,
+		&schedule.NextStopAt,
+		&schedule.NextStartAt,
+		&schedule.Catalog)
+}
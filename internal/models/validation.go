@@ -0,0 +1,196 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxNameLength is the limit of the Azure resource group names
+const MaxNameLength = 90
+
+// Limits of the sandbox metadata, the labels follow the limits of the Azure
+// resource group tags
+const (
+	MaxDescriptionLength = 1024
+	MaxCostCenterLength  = 64
+	MaxNotesLength       = 4096
+	MaxLabels            = 50
+	MaxLabelKeyLength    = 512
+	MaxLabelValueLength  = 256
+	MaxTemplateLength    = 256
+)
+
+// Sandbox names follow the rules of the Azure resource group names, except
+// parentheses and non-ASCII letters are not allowed, so the name can be used
+// in the application identifier URI as is. The same pattern is used by the
+// OpenAPI spec and by the sandboxes_name_check constraint.
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{0,89}[A-Za-z0-9_-]$`)
+
+var costCenterPattern = regexp.MustCompile(`^[A-Za-z0-9._-]*$`)
+
+// Characters Azure doesn't allow in the tag names
+const forbiddenLabelKeyChars = `<>%&\?/`
+
+// ValidateName checks the sandbox name before it is used for the Azure resources
+func ValidateName(name string) error {
+	if name == "" {
+		return NewFieldError("name", "must not be empty")
+	}
+
+	if len(name) > MaxNameLength {
+		return NewFieldError("name", "must be at most 90 characters long")
+	}
+
+	if !namePattern.MatchString(name) {
+		return NewFieldError("name", "may contain only letters, digits, underscores, hyphens and periods and must not end with a period")
+	}
+
+	return nil
+}
+
+// validateExpiresAt makes sure the sandbox is not expired right away
+func validateExpiresAt(expiresAt time.Time) error {
+	if !expiresAt.After(time.Now()) {
+		return NewFieldError("expiresAt", "must be in the future")
+	}
+
+	return nil
+}
+
+// validateStartAt checks the schedule of the sandbox
+func validateStartAt(startAt time.Time, expiresAt time.Time) error {
+	if !startAt.After(time.Now()) {
+		return NewFieldError("startAt", "must be in the future")
+	}
+
+	if !startAt.Before(expiresAt) {
+		return NewFieldError("startAt", "must be before expiresAt")
+	}
+
+	return nil
+}
+
+// fieldErrors collects the invalid fields, so all of them are reported at once
+type fieldErrors []FieldError
+
+func (f *fieldErrors) add(err error) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		*f = append(*f, domainErr.Fields...)
+	}
+}
+
+func (f *fieldErrors) addField(field string, message string) {
+	*f = append(*f, FieldError{Field: field, Message: message})
+}
+
+func (f fieldErrors) err() error {
+	if len(f) == 0 {
+		return nil
+	}
+
+	if len(f) == 1 {
+		return NewFieldError(f[0].Field, f[0].Message)
+	}
+
+	return &Error{
+		Kind:    KindValidation,
+		Code:    "ValidationFailed",
+		Message: "request has invalid fields",
+		Fields:  f,
+	}
+}
+
+// Helper to check a single label, the labels are synced to the resource group tags
+func validateLabel(errs *fieldErrors, key string, value string) {
+	field := "labels." + key
+
+	switch {
+	case key == "":
+		errs.addField("labels", "label key must not be empty")
+	case utf8.RuneCountInString(key) > MaxLabelKeyLength:
+		errs.addField(field, "label key must be at most 512 characters long")
+	case strings.ContainsAny(key, forbiddenLabelKeyChars):
+		errs.addField(field, "label key must not contain any of "+forbiddenLabelKeyChars)
+	}
+
+	if utf8.RuneCountInString(value) > MaxLabelValueLength {
+		errs.addField(field, "label value must be at most 256 characters long")
+	}
+}
+
+// validateOptions checks the role and the template of the new sandbox
+func validateOptions(errs *fieldErrors, options SandboxOptions) {
+	switch options.Role {
+	case "", RoleReader, RoleContributor, RoleOwner, RoleUserAccessAdministrator:
+	default:
+		errs.addField("role", "must be one of Reader, Contributor, Owner or User Access Administrator")
+	}
+
+	if utf8.RuneCountInString(options.Template) > MaxTemplateLength {
+		errs.addField("template", "must be at most 256 characters long")
+	}
+}
+
+// validateLabels checks the labels of the new sandbox
+func validateLabels(labels map[string]string) error {
+	errs := fieldErrors{}
+
+	if len(labels) > MaxLabels {
+		errs.addField("labels", "must have at most 50 labels")
+	}
+
+	for key, value := range labels {
+		validateLabel(&errs, key, value)
+	}
+
+	return errs.err()
+}
+
+// validatePatch checks every field of the patch, the labels are checked as
+// they are after the merge. The scheduled sandbox must not expire before it
+// is started.
+func validatePatch(patch SandboxPatch, labels map[string]string, startAt *time.Time) error {
+	errs := fieldErrors{}
+
+	if patch.ExpiresAt != nil {
+		switch {
+		case !patch.ExpiresAt.After(time.Now()):
+			errs.addField("expiresAt", "must be in the future")
+		case startAt != nil && !patch.ExpiresAt.After(*startAt):
+			errs.addField("expiresAt", "must be after startAt")
+		}
+	}
+
+	if patch.Description != nil && utf8.RuneCountInString(*patch.Description) > MaxDescriptionLength {
+		errs.addField("description", "must be at most 1024 characters long")
+	}
+
+	if patch.CostCenter != nil {
+		switch {
+		case utf8.RuneCountInString(*patch.CostCenter) > MaxCostCenterLength:
+			errs.addField("costCenter", "must be at most 64 characters long")
+		case !costCenterPattern.MatchString(*patch.CostCenter):
+			errs.addField("costCenter", "may contain only letters, digits, underscores, hyphens and periods")
+		}
+	}
+
+	if patch.Notes != nil && utf8.RuneCountInString(*patch.Notes) > MaxNotesLength {
+		errs.addField("notes", "must be at most 4096 characters long")
+	}
+
+	for key, value := range patch.Labels {
+		if value != nil {
+			validateLabel(&errs, key, *value)
+		}
+	}
+
+	if len(labels) > MaxLabels {
+		errs.addField("labels", "must have at most 50 labels")
+	}
+
+	return errs.err()
+}
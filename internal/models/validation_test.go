@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		valid bool
+	}{
+		{"single letter", "a", true},
+		{"letters and digits", "Workshop42", true},
+		{"separators", "team_a-workshop.2023", true},
+		{"ends with underscore", "workshop_", true},
+		{"ends with hyphen", "workshop-", true},
+		{"starts with period", ".workshop", true},
+		{"longest", strings.Repeat("a", MaxNameLength), true},
+		{"empty", "", false},
+		{"too long", strings.Repeat("a", MaxNameLength+1), false},
+		{"ends with period", "workshop.", false},
+		{"only period", ".", false},
+		{"space", "work shop", false},
+		{"parentheses", "workshop(1)", false},
+		{"non-ASCII letter", "wörkshop", false},
+		{"slash", "team/workshop", false},
+		{"newline", "workshop\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(tt.input)
+
+			if tt.valid && err != nil {
+				t.Errorf("ValidateName(%q) error = %v, want nil", tt.input, err)
+			}
+
+			if !tt.valid {
+				var domainErr *Error
+				if !errors.As(err, &domainErr) || domainErr.Kind != KindValidation {
+					t.Fatalf("ValidateName(%q) error = %v, want validation error", tt.input, err)
+				}
+				if len(domainErr.Fields) != 1 || domainErr.Fields[0].Field != "name" {
+					t.Errorf("ValidateName(%q) fields = %v, want the name field", tt.input, domainErr.Fields)
+				}
+			}
+		})
+	}
+}
+
+func TestNamePatternLength(t *testing.T) {
+	// The pattern alone enforces the length, as the database constraint does
+	tests := []struct {
+		length int
+		match  bool
+	}{
+		{0, false},
+		{1, true},
+		{MaxNameLength, true},
+		{MaxNameLength + 1, false},
+	}
+
+	for _, tt := range tests {
+		if got := namePattern.MatchString(strings.Repeat("a", tt.length)); got != tt.match {
+			t.Errorf("namePattern matches %d characters = %v, want %v", tt.length, got, tt.match)
+		}
+	}
+}
+
+func TestValidatePatchExpiresAt(t *testing.T) {
+	now := time.Now()
+	startAt := now.Add(48 * time.Hour)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		startAt   *time.Time
+		valid     bool
+	}{
+		{"future", now.Add(time.Hour), nil, true},
+		{"past", now.Add(-time.Hour), nil, false},
+		{"after startAt", startAt.Add(time.Hour), &startAt, true},
+		{"before startAt", startAt.Add(-time.Hour), &startAt, false},
+		{"at startAt", startAt, &startAt, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePatch(SandboxPatch{ExpiresAt: &tt.expiresAt}, nil, tt.startAt)
+
+			if tt.valid && err != nil {
+				t.Errorf("validatePatch() error = %v, want nil", err)
+			}
+
+			if !tt.valid {
+				var domainErr *Error
+				if !errors.As(err, &domainErr) || len(domainErr.Fields) != 1 || domainErr.Fields[0].Field != "expiresAt" {
+					t.Errorf("validatePatch() error = %v, want the expiresAt field error", err)
+				}
+			}
+		})
+	}
+}
@@ -9,20 +9,61 @@ CREATE TYPE public.status AS ENUM (
     'EXPIRED',
     'PENDING',
     'FAILED',
-    'DELETED'
+    'DELETED',
+    'SCHEDULED',
+    'PENDING_APPROVAL',
+    -- The resources are being removed, the name is still held
+    'DELETING',
+    -- The resources are being deallocated or started again
+    'STOPPING',
+    'STARTING'
 );
 
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
 CREATE TABLE sandboxes (
     id uuid DEFAULT uuid_generate_v4() CONSTRAINT sandboxes_pk PRIMARY KEY,
-    name varchar(100) NOT NULL CHECK (name <> ''),
+    -- Same rules as the Azure resource group names, except parentheses and
+    -- non-ASCII letters, so the name also fits the application identifier URI
+    name varchar(90) NOT NULL CONSTRAINT sandboxes_name_check CHECK (name ~ '^[A-Za-z0-9._-]{0,89}[A-Za-z0-9_-]$'),
     created_at timestamp NOT NULL DEFAULT now(),
     updated_at timestamp NOT NULL DEFAULT now(),
     expires_at timestamp NOT NULL,
-    status public.status NOT NULL
+    status public.status NOT NULL,
+    version integer NOT NULL DEFAULT 1,
+    owner varchar(255) NOT NULL DEFAULT '',
+    labels jsonb NOT NULL DEFAULT '{}'::jsonb,
+    description varchar(1024) NOT NULL DEFAULT '',
+    cost_center varchar(64) NOT NULL DEFAULT '',
+    notes varchar(4096) NOT NULL DEFAULT '',
+    -- Scheduled sandboxes are provisioned at start_at, NULL for the others
+    start_at timestamp,
+    -- The owner opted out of the early expiry of the idle sandbox
+    keep_when_idle boolean NOT NULL DEFAULT false,
+    -- Set when the owner is warned the sandbox is idle, cleared on activity
+    idle_warned_at timestamp,
+    -- Azure built-in role assigned to the application of the sandbox
+    role varchar(64) NOT NULL DEFAULT 'Contributor',
+    -- Template the resources are deployed from, empty for none
+    template varchar(256) NOT NULL DEFAULT '',
+    CONSTRAINT sandboxes_start_at_check CHECK (start_at IS NULL OR start_at < expires_at)
 );
 
+-- Indexes for the filters and the sort orders of the sandbox listing
+CREATE INDEX sandboxes_created_at_id_idx ON sandboxes (created_at, id);
+CREATE INDEX sandboxes_updated_at_id_idx ON sandboxes (updated_at, id);
+CREATE INDEX sandboxes_expires_at_id_idx ON sandboxes (expires_at, id);
+CREATE INDEX sandboxes_name_idx ON sandboxes (name text_pattern_ops);
+CREATE INDEX sandboxes_status_idx ON sandboxes (status);
+CREATE INDEX sandboxes_owner_created_at_idx ON sandboxes (owner, created_at);
+CREATE INDEX sandboxes_labels_idx ON sandboxes USING GIN (labels jsonb_path_ops);
+CREATE INDEX sandboxes_scheduled_start_at_idx ON sandboxes (start_at) WHERE status = 'SCHEDULED';
+
+-- The name maps to the Azure resource group, so it can be reused only after
+-- the sandbox is deleted. The DELETING sandboxes hold it until the resource
+-- group is removed, the failed teardown keeps it FAILED.
+CREATE UNIQUE INDEX sandboxes_active_name_idx ON sandboxes (name) WHERE status <> 'DELETED';
+
 /*
 TODO: add permissions to sandboxes table
 e.g.: GRANT SELECT ON TABLE sandboxes TO public;
@@ -3,7 +3,17 @@ SET client_min_messages TO warning;
 BEGIN;
 
 -- TODO: Check input values
-CREATE OR REPLACE FUNCTION insert_sandbox(in_name varchar, in_expires_at timestamp)
+-- Sandboxes with in_start_at are SCHEDULED, the scheduler provisions them later.
+-- The sandboxes exceeding the policy wait in PENDING_APPROVAL first.
+CREATE OR REPLACE FUNCTION insert_sandbox(
+    in_name varchar,
+    in_expires_at timestamp,
+    in_owner varchar,
+    in_labels jsonb,
+    in_start_at timestamp DEFAULT NULL,
+    in_pending_approval boolean DEFAULT false,
+    in_role varchar DEFAULT 'Contributor',
+    in_template varchar DEFAULT '')
     RETURNS uuid
     LANGUAGE 'plpgsql'
 AS
@@ -11,15 +21,22 @@ $$
 DECLARE
     sandbox_id uuid;
 BEGIN
-    INSERT INTO sandboxes (name, expires_at,status)
-    VALUES (in_name, in_expires_at, 'Pending')
+    INSERT INTO sandboxes (name, expires_at, status, owner, labels, start_at, role, template)
+    VALUES (in_name, in_expires_at,
+        CASE
+            WHEN in_pending_approval THEN 'PENDING_APPROVAL'
+            WHEN in_start_at IS NULL THEN 'PENDING'
+            ELSE 'SCHEDULED'
+        END::public.status,
+        in_owner, in_labels, in_start_at, in_role, in_template)
     RETURNING id INTO sandbox_id;
 
     RETURN sandbox_id;
 END;
 $$;
 
-CREATE OR REPLACE FUNCTION update_sandbox_status(in_sandbox_id uuid, in_status public.status)
+-- in_version is the version the caller expects the sandbox to have, NULL skips the check
+CREATE OR REPLACE FUNCTION update_sandbox_status(in_sandbox_id uuid, in_status public.status, in_version integer DEFAULT NULL)
     RETURNS boolean
     LANGUAGE 'plpgsql'
 AS
@@ -27,72 +44,198 @@ $$
 BEGIN
     UPDATE sandboxes
     SET status = in_status,
-        updated_at = now()
-    WHERE id = in_sandbox_id;
+        updated_at = now(),
+        version = version + 1
+    WHERE id = in_sandbox_id AND
+        (in_version IS NULL OR version = in_version);
 
     RETURN FOUND;
 END;
 $$;
 
-CREATE OR REPLACE FUNCTION update_sandbox_expires_at(in_sandbox_id uuid, in_expires_at timestamp)
+-- Applies the merge patch of the sandbox metadata, NULL arguments leave the
+-- fields as they are. The labels are merged, the labels set to null are
+-- removed. Every changed field is recorded in the history separately.
+CREATE OR REPLACE FUNCTION update_sandbox_metadata(
+    in_sandbox_id uuid,
+    in_actor varchar,
+    in_expires_at timestamp,
+    in_description varchar,
+    in_cost_center varchar,
+    in_notes varchar,
+    in_labels jsonb,
+    in_keep_when_idle boolean,
+    in_version integer DEFAULT NULL)
     RETURNS boolean
     LANGUAGE 'plpgsql'
 AS
 $$
+DECLARE
+    old_sandbox sandboxes%ROWTYPE;
+    new_sandbox sandboxes%ROWTYPE;
 BEGIN
+    SELECT * INTO old_sandbox
+    FROM sandboxes
+    WHERE id = in_sandbox_id AND
+        status NOT IN ('DELETED', 'DELETING') AND
+        (in_version IS NULL OR version = in_version)
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RETURN FALSE;
+    END IF;
+
     UPDATE sandboxes
-    SET expires_at = in_expires_at, 
-        updated_at = now()
-    WHERE id = in_sandbox_id;
+    SET expires_at = COALESCE(in_expires_at, expires_at),
+        description = COALESCE(in_description, description),
+        cost_center = COALESCE(in_cost_center, cost_center),
+        notes = COALESCE(in_notes, notes),
+        labels = CASE WHEN in_labels IS NULL THEN labels ELSE jsonb_strip_nulls(labels || in_labels) END,
+        keep_when_idle = COALESCE(in_keep_when_idle, keep_when_idle),
+        idle_warned_at = CASE WHEN in_keep_when_idle THEN NULL ELSE idle_warned_at END,
+        updated_at = now(),
+        version = version + 1
+    WHERE id = in_sandbox_id
+    RETURNING * INTO new_sandbox;
 
-    RETURN FOUND;
+    INSERT INTO sandbox_history (sandbox_id, field, old_value, new_value, actor)
+    SELECT in_sandbox_id, f.field, f.old_value, f.new_value, in_actor
+    FROM (VALUES
+        ('expiresAt', to_jsonb(old_sandbox.expires_at), to_jsonb(new_sandbox.expires_at)),
+        ('description', to_jsonb(old_sandbox.description), to_jsonb(new_sandbox.description)),
+        ('costCenter', to_jsonb(old_sandbox.cost_center), to_jsonb(new_sandbox.cost_center)),
+        ('notes', to_jsonb(old_sandbox.notes), to_jsonb(new_sandbox.notes)),
+        ('labels', old_sandbox.labels, new_sandbox.labels),
+        ('keepWhenIdle', to_jsonb(old_sandbox.keep_when_idle), to_jsonb(new_sandbox.keep_when_idle))
+    ) AS f (field, old_value, new_value)
+    WHERE f.old_value IS DISTINCT FROM f.new_value;
+
+    RETURN TRUE;
 END;
 $$;
 
+-- Sets or, with NULL, clears the idle warning. The sandboxes kept when idle
+-- are never warned. The metadata is not changed, so neither is the version.
+CREATE OR REPLACE FUNCTION update_sandbox_idle_warning(in_sandbox_id uuid, in_warned_at timestamp, in_actor varchar)
+    RETURNS boolean
+    LANGUAGE 'plpgsql'
+AS
+$$
+DECLARE
+    old_warned_at timestamp;
+BEGIN
+    SELECT idle_warned_at INTO old_warned_at
+    FROM sandboxes
+    WHERE id = in_sandbox_id AND
+        status IN ('RUNNING', 'STOPPED') AND
+        (in_warned_at IS NULL OR NOT keep_when_idle)
+    FOR UPDATE;
+
+    IF NOT FOUND OR old_warned_at IS NOT DISTINCT FROM in_warned_at THEN
+        RETURN FALSE;
+    END IF;
 
-CREATE OR REPLACE FUNCTION delete_sandbox(in_sandbox_id uuid)
+    UPDATE sandboxes
+    SET idle_warned_at = in_warned_at
+    WHERE id = in_sandbox_id;
+
+    INSERT INTO sandbox_history (sandbox_id, field, old_value, new_value, actor)
+    VALUES (in_sandbox_id, 'idleWarnedAt', to_jsonb(old_warned_at), to_jsonb(in_warned_at), in_actor);
+
+    RETURN TRUE;
+END;
+$$;
+
+-- Expires the idle sandbox right away. It is done only if the sandbox is still
+-- warned with in_warned_at, so the activity or the opt-out in the meantime
+-- keeps it.
+CREATE OR REPLACE FUNCTION expire_idle_sandbox(in_sandbox_id uuid, in_warned_at timestamp, in_actor varchar)
     RETURNS boolean
     LANGUAGE 'plpgsql'
 AS
 $$
+DECLARE
+    old_sandbox sandboxes%ROWTYPE;
 BEGIN
-    DELETE FROM sandboxes
+    SELECT * INTO old_sandbox
+    FROM sandboxes
+    WHERE id = in_sandbox_id AND
+        status IN ('RUNNING', 'STOPPED') AND
+        idle_warned_at = in_warned_at AND
+        NOT keep_when_idle
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RETURN FALSE;
+    END IF;
+
+    UPDATE sandboxes
+    SET status = 'EXPIRED',
+        expires_at = LEAST(expires_at, now()::timestamp),
+        updated_at = now(),
+        version = version + 1
     WHERE id = in_sandbox_id;
 
-    RETURN FOUND;
+    INSERT INTO sandbox_history (sandbox_id, field, old_value, new_value, actor)
+    VALUES
+        (in_sandbox_id, 'status', to_jsonb(old_sandbox.status), to_jsonb('EXPIRED'::text), in_actor),
+        (in_sandbox_id, 'expiresAt', to_jsonb(old_sandbox.expires_at), to_jsonb(LEAST(old_sandbox.expires_at, now()::timestamp)), in_actor);
+
+    RETURN TRUE;
 END;
 $$;
 
-CREATE OR REPLACE FUNCTION get_sandbox_by_id(in_sandbox_id uuid)
-    RETURNS table
-    (
-        id uuid,
-        name varchar,
-        created_at timestamp,
-        updated_at timestamp,
-        expires_at timestamp,
-        status public.status
-    )
+-- Moves the due scheduled sandboxes to PENDING and returns their ids. The rows
+-- locked by another scheduler are skipped, so every sandbox is claimed once.
+-- The claim is taken over once it is older than in_claim_timeout_s seconds and
+-- the create operation is still NOT_STARTED, i.e. the scheduler which claimed
+-- the sandbox stopped before starting the provisioning.
+CREATE OR REPLACE FUNCTION claim_scheduled_sandboxes(in_limit integer, in_claim_timeout_s integer)
+    RETURNS SETOF uuid
     LANGUAGE 'plpgsql'
 AS
 $$
 BEGIN
     RETURN QUERY
-    SELECT
-        s.id,
-        s.name,
-        s.created_at,
-        s.updated_at,
-        s.expires_at,
-        s.status
-    FROM
-        sandboxes s
-    WHERE
-        s.id = in_sandbox_id;
+    UPDATE sandboxes s
+    SET status = 'PENDING',
+        updated_at = now(),
+        version = s.version + 1
+    WHERE s.id IN (
+        SELECT d.id
+        FROM sandboxes d
+        WHERE (d.status = 'SCHEDULED' AND
+                d.start_at <= now()) OR
+            (d.status = 'PENDING' AND
+                d.start_at IS NOT NULL AND
+                d.updated_at < now() - make_interval(secs => in_claim_timeout_s) AND
+                EXISTS (
+                    SELECT 1
+                    FROM operations o
+                    WHERE o.sandbox_id = d.id AND
+                        o.kind = 'CREATE' AND
+                        o.status = 'NOT_STARTED'))
+        ORDER BY d.start_at
+        LIMIT in_limit
+        FOR UPDATE SKIP LOCKED)
+    RETURNING s.id;
 END;
 $$;
 
-CREATE OR REPLACE FUNCTION get_sandbox_by_name(in_name varchar)
+CREATE OR REPLACE FUNCTION delete_sandbox(in_sandbox_id uuid)
+    RETURNS boolean
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    DELETE FROM sandboxes
+    WHERE id = in_sandbox_id;
+
+    RETURN FOUND;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION get_sandbox_by_id(in_sandbox_id uuid)
     RETURNS table
     (
         id uuid,
@@ -100,7 +243,18 @@ CREATE OR REPLACE FUNCTION get_sandbox_by_name(in_name varchar)
         created_at timestamp,
         updated_at timestamp,
         expires_at timestamp,
-        status public.status
+        status public.status,
+        version integer,
+        owner varchar,
+        labels jsonb,
+        description varchar,
+        cost_center varchar,
+        notes varchar,
+        start_at timestamp,
+        keep_when_idle boolean,
+        idle_warned_at timestamp,
+        role varchar,
+        template varchar
     )
     LANGUAGE 'plpgsql'
 AS
@@ -113,15 +267,26 @@ BEGIN
         s.created_at,
         s.updated_at,
         s.expires_at,
-        s.status
+        s.status,
+        s.version,
+        s.owner,
+        s.labels,
+        s.description,
+        s.cost_center,
+        s.notes,
+        s.start_at,
+        s.keep_when_idle,
+        s.idle_warned_at,
+        s.role,
+        s.template
     FROM
         sandboxes s
     WHERE
-        s.name = in_name;
+        s.id = in_sandbox_id;
 END;
 $$;
 
-CREATE OR REPLACE FUNCTION get_sandbox_all(in_limit integer, in_offset integer)
+CREATE OR REPLACE FUNCTION get_active_sandbox_by_name(in_name varchar)
     RETURNS table
     (
         id uuid,
@@ -129,7 +294,18 @@ CREATE OR REPLACE FUNCTION get_sandbox_all(in_limit integer, in_offset integer)
         created_at timestamp,
         updated_at timestamp,
         expires_at timestamp,
-        status public.status
+        status public.status,
+        version integer,
+        owner varchar,
+        labels jsonb,
+        description varchar,
+        cost_center varchar,
+        notes varchar,
+        start_at timestamp,
+        keep_when_idle boolean,
+        idle_warned_at timestamp,
+        role varchar,
+        template varchar
     )
     LANGUAGE 'plpgsql'
 AS
@@ -142,12 +318,23 @@ BEGIN
         s.created_at,
         s.updated_at,
         s.expires_at,
-        s.status
+        s.status,
+        s.version,
+        s.owner,
+        s.labels,
+        s.description,
+        s.cost_center,
+        s.notes,
+        s.start_at,
+        s.keep_when_idle,
+        s.idle_warned_at,
+        s.role,
+        s.template
     FROM
         sandboxes s
-    ORDER BY s.created_at DESC
-    LIMIT in_limit
-    OFFSET in_offset;
+    WHERE
+        s.name = in_name AND
+        s.status <> 'DELETED';
 END;
 $$;
 
@@ -159,7 +346,18 @@ CREATE OR REPLACE FUNCTION get_sandbox_by_status(in_status public.status)
         created_at timestamp,
         updated_at timestamp,
         expires_at timestamp,
-        status public.status
+        status public.status,
+        version integer,
+        owner varchar,
+        labels jsonb,
+        description varchar,
+        cost_center varchar,
+        notes varchar,
+        start_at timestamp,
+        keep_when_idle boolean,
+        idle_warned_at timestamp,
+        role varchar,
+        template varchar
     )
     LANGUAGE 'plpgsql'
 AS
@@ -172,7 +370,18 @@ BEGIN
         s.created_at,
         s.updated_at,
         s.expires_at,
-        s.status
+        s.status,
+        s.version,
+        s.owner,
+        s.labels,
+        s.description,
+        s.cost_center,
+        s.notes,
+        s.start_at,
+        s.keep_when_idle,
+        s.idle_warned_at,
+        s.role,
+        s.template
     FROM
         sandboxes s
     WHERE
@@ -0,0 +1,168 @@
+SET client_min_messages TO warning;
+
+BEGIN;
+
+CREATE TYPE public.operation_kind AS ENUM (
+    'CREATE',
+    'DELETE',
+    'STOP',
+    'EXTEND',
+    'UPDATE',
+    'START',
+    'EXPIRE'
+);
+
+CREATE TYPE public.operation_status AS ENUM (
+    'NOT_STARTED',
+    'RUNNING',
+    'SUCCEEDED',
+    'FAILED',
+    'CANCELED'
+);
+
+-- Operations outlive the sandbox record they act on (e.g. DELETE), so
+-- there is no foreign key to sandboxes.
+CREATE TABLE operations (
+    id uuid DEFAULT uuid_generate_v4() CONSTRAINT operations_pk PRIMARY KEY,
+    sandbox_id uuid NOT NULL,
+    kind public.operation_kind NOT NULL,
+    status public.operation_status NOT NULL DEFAULT 'NOT_STARTED',
+    percent_complete smallint NOT NULL DEFAULT 0 CHECK (percent_complete BETWEEN 0 AND 100),
+    step varchar(100) NOT NULL DEFAULT '',
+    error_code varchar(100),
+    error_message text,
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX operations_sandbox_id_idx ON operations (sandbox_id);
+CREATE INDEX operations_running_updated_at_idx ON operations (updated_at) WHERE status = 'RUNNING';
+
+CREATE OR REPLACE FUNCTION insert_operation(in_sandbox_id uuid, in_kind public.operation_kind)
+    RETURNS uuid
+    LANGUAGE 'plpgsql'
+AS
+$$
+DECLARE
+    operation_id uuid;
+BEGIN
+    INSERT INTO operations (sandbox_id, kind)
+    VALUES (in_sandbox_id, in_kind)
+    RETURNING id INTO operation_id;
+
+    RETURN operation_id;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION update_operation_progress(in_operation_id uuid, in_status public.operation_status, in_percent_complete smallint, in_step varchar)
+    RETURNS boolean
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    UPDATE operations
+    SET status = in_status,
+        percent_complete = in_percent_complete,
+        step = in_step,
+        updated_at = now()
+    WHERE id = in_operation_id;
+
+    RETURN FOUND;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION update_operation_error(in_operation_id uuid, in_status public.operation_status, in_error_code varchar, in_error_message text)
+    RETURNS boolean
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    UPDATE operations
+    SET status = in_status,
+        error_code = in_error_code,
+        error_message = in_error_message,
+        updated_at = now()
+    WHERE id = in_operation_id;
+
+    RETURN FOUND;
+END;
+$$;
+
+-- Returns the operation of the sandbox waiting to be started, e.g. creation of
+-- a scheduled sandbox, NULL if there is none
+CREATE OR REPLACE FUNCTION get_not_started_operation_id(in_sandbox_id uuid, in_kind public.operation_kind)
+    RETURNS uuid
+    LANGUAGE 'plpgsql'
+AS
+$$
+DECLARE
+    operation_id uuid;
+BEGIN
+    SELECT o.id INTO operation_id
+    FROM operations o
+    WHERE o.sandbox_id = in_sandbox_id AND
+        o.kind = in_kind AND
+        o.status = 'NOT_STARTED'
+    ORDER BY o.created_at DESC
+    LIMIT 1;
+
+    RETURN operation_id;
+END;
+$$;
+
+-- Returns the running operations not updated for in_stale_after_s seconds,
+-- i.e. the instance running them stopped, oldest first
+CREATE OR REPLACE FUNCTION get_stale_operation_ids(in_limit integer, in_stale_after_s integer)
+    RETURNS SETOF uuid
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT o.id
+    FROM operations o
+    WHERE o.status = 'RUNNING' AND
+        o.updated_at < now() - make_interval(secs => in_stale_after_s)
+    ORDER BY o.updated_at
+    LIMIT in_limit;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION get_operation_by_id(in_operation_id uuid)
+    RETURNS table
+    (
+        id uuid,
+        sandbox_id uuid,
+        kind public.operation_kind,
+        status public.operation_status,
+        percent_complete smallint,
+        step varchar,
+        error_code varchar,
+        error_message text,
+        created_at timestamp,
+        updated_at timestamp
+    )
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT
+        o.id,
+        o.sandbox_id,
+        o.kind,
+        o.status,
+        o.percent_complete,
+        o.step,
+        COALESCE(o.error_code, ''),
+        COALESCE(o.error_message, ''),
+        o.created_at,
+        o.updated_at
+    FROM
+        operations o
+    WHERE
+        o.id = in_operation_id;
+END;
+$$;
+
+COMMIT;
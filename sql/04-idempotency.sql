@@ -0,0 +1,122 @@
+SET client_min_messages TO warning;
+
+BEGIN;
+
+-- Responses of mutating requests, keyed by the caller and the client supplied
+-- Idempotency-Key, so the callers can't see the responses of each other.
+-- status_code is NULL while the original request is still in flight.
+CREATE TABLE idempotency_keys (
+    owner varchar(255) NOT NULL,
+    key varchar(255) NOT NULL CHECK (key <> ''),
+    request_hash char(64) NOT NULL,
+    status_code integer,
+    response_headers jsonb,
+    response_body bytea,
+    created_at timestamp NOT NULL DEFAULT now(),
+    -- The request in flight past the time is considered lost, e.g. the
+    -- server crashed, and the key can be taken over by a retry
+    locked_until timestamp NOT NULL,
+    expires_at timestamp NOT NULL,
+    CONSTRAINT idempotency_keys_pk PRIMARY KEY (owner, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+
+-- Returns false if the key is already taken by a request which hasn't expired
+-- yet, unless the request is in flight past its lock
+CREATE OR REPLACE FUNCTION reserve_idempotency_key(
+    in_owner varchar,
+    in_key varchar,
+    in_request_hash char,
+    in_ttl_seconds integer,
+    in_lock_seconds integer)
+    RETURNS boolean
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    DELETE FROM idempotency_keys
+    WHERE expires_at < now();
+
+    INSERT INTO idempotency_keys (owner, key, request_hash, locked_until, expires_at)
+    VALUES (in_owner, in_key, in_request_hash,
+        now() + make_interval(secs => in_lock_seconds),
+        now() + make_interval(secs => in_ttl_seconds))
+    ON CONFLICT (owner, key) DO UPDATE
+    SET request_hash = EXCLUDED.request_hash,
+        created_at = now(),
+        locked_until = EXCLUDED.locked_until,
+        expires_at = EXCLUDED.expires_at
+    WHERE idempotency_keys.status_code IS NULL AND
+        idempotency_keys.locked_until < now();
+
+    RETURN FOUND;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION complete_idempotency_key(
+    in_owner varchar,
+    in_key varchar,
+    in_status_code integer,
+    in_response_headers jsonb,
+    in_response_body bytea)
+    RETURNS boolean
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    UPDATE idempotency_keys
+    SET status_code = in_status_code,
+        response_headers = in_response_headers,
+        response_body = in_response_body
+    WHERE owner = in_owner AND
+        key = in_key;
+
+    RETURN FOUND;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION release_idempotency_key(in_owner varchar, in_key varchar)
+    RETURNS boolean
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    DELETE FROM idempotency_keys
+    WHERE owner = in_owner AND
+        key = in_key;
+
+    RETURN FOUND;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION get_idempotency_key(in_owner varchar, in_key varchar)
+    RETURNS table
+    (
+        key varchar,
+        request_hash char,
+        status_code integer,
+        response_headers jsonb,
+        response_body bytea
+    )
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT
+        i.key,
+        i.request_hash,
+        COALESCE(i.status_code, 0),
+        COALESCE(i.response_headers, '{}'::jsonb),
+        COALESCE(i.response_body, ''::bytea)
+    FROM
+        idempotency_keys i
+    WHERE
+        i.owner = in_owner AND
+        i.key = in_key AND
+        i.expires_at >= now();
+END;
+$$;
+
+COMMIT;
@@ -0,0 +1,37 @@
+SET client_min_messages TO warning;
+
+BEGIN;
+
+-- Changes of the sandbox fields, one row per field. The rows are kept after
+-- the sandbox is deleted, so there is no foreign key.
+CREATE TABLE sandbox_history (
+    id bigserial CONSTRAINT sandbox_history_pk PRIMARY KEY,
+    sandbox_id uuid NOT NULL,
+    field varchar(64) NOT NULL,
+    old_value jsonb,
+    new_value jsonb,
+    actor varchar(255) NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX sandbox_history_sandbox_id_idx ON sandbox_history (sandbox_id, created_at);
+
+-- Records the events which are not the changes of the sandbox record, like
+-- the runs of the schedule
+CREATE OR REPLACE FUNCTION insert_sandbox_history(
+    in_sandbox_id uuid,
+    in_field varchar,
+    in_old_value jsonb,
+    in_new_value jsonb,
+    in_actor varchar)
+    RETURNS void
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    INSERT INTO sandbox_history (sandbox_id, field, old_value, new_value, actor)
+    VALUES (in_sandbox_id, in_field, in_old_value, in_new_value, in_actor);
+END;
+$$;
+
+COMMIT;
@@ -0,0 +1,258 @@
+SET client_min_messages TO warning;
+
+BEGIN;
+
+-- Recurring auto stop/start of the sandbox. The cron expressions are
+-- evaluated in the timezone by the API, the next runs are stored in UTC.
+-- start_cron is empty if the sandbox is only stopped. catalog is set if the
+-- schedule is inherited from the catalog schedule, empty if it is set for the
+-- sandbox itself.
+CREATE TABLE sandbox_schedules (
+    sandbox_id uuid CONSTRAINT sandbox_schedules_pk PRIMARY KEY
+        REFERENCES sandboxes (id) ON DELETE CASCADE,
+    stop_cron varchar(100) NOT NULL CHECK (stop_cron <> ''),
+    start_cron varchar(100) NOT NULL DEFAULT '',
+    timezone varchar(64) NOT NULL DEFAULT 'UTC',
+    next_stop_at timestamp NOT NULL,
+    next_start_at timestamp,
+    catalog varchar(256) NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX sandbox_schedules_next_stop_at_idx ON sandbox_schedules (next_stop_at);
+CREATE INDEX sandbox_schedules_next_start_at_idx ON sandbox_schedules (next_start_at);
+CREATE INDEX sandbox_schedules_catalog_idx ON sandbox_schedules (catalog) WHERE catalog <> '';
+
+-- Schedules shared by the sandboxes of the catalog, the value of their
+-- catalog label. The sandboxes without the label are in the default catalog.
+-- The sandboxes get a copy of the schedule, so they run and skip it on their
+-- own, the next runs are computed once the copy is made.
+CREATE TABLE catalog_schedules (
+    catalog varchar(256) CONSTRAINT catalog_schedules_pk PRIMARY KEY CHECK (catalog <> ''),
+    stop_cron varchar(100) NOT NULL CHECK (stop_cron <> ''),
+    start_cron varchar(100) NOT NULL DEFAULT '',
+    timezone varchar(64) NOT NULL DEFAULT 'UTC',
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp NOT NULL DEFAULT now()
+);
+
+-- Sets the schedule of the sandbox. The schedule of the catalog, in_catalog
+-- set, replaces only the inherited schedule, never the one set for the
+-- sandbox itself.
+CREATE OR REPLACE FUNCTION upsert_sandbox_schedule(
+    in_sandbox_id uuid,
+    in_stop_cron varchar,
+    in_start_cron varchar,
+    in_timezone varchar,
+    in_next_stop_at timestamp,
+    in_next_start_at timestamp,
+    in_catalog varchar DEFAULT '')
+    RETURNS void
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    INSERT INTO sandbox_schedules (sandbox_id, stop_cron, start_cron, timezone, next_stop_at, next_start_at, catalog)
+    VALUES (in_sandbox_id, in_stop_cron, in_start_cron, in_timezone, in_next_stop_at, in_next_start_at, in_catalog)
+    ON CONFLICT (sandbox_id) DO UPDATE
+    SET stop_cron = EXCLUDED.stop_cron,
+        start_cron = EXCLUDED.start_cron,
+        timezone = EXCLUDED.timezone,
+        next_stop_at = EXCLUDED.next_stop_at,
+        next_start_at = EXCLUDED.next_start_at,
+        catalog = EXCLUDED.catalog,
+        updated_at = now()
+    WHERE in_catalog = '' OR sandbox_schedules.catalog <> '';
+END;
+$$;
+
+-- Sets the schedule of the catalog and applies it to the active sandboxes of
+-- the catalog, except the ones with their own schedule
+CREATE OR REPLACE FUNCTION upsert_catalog_schedule(
+    in_catalog varchar,
+    in_stop_cron varchar,
+    in_start_cron varchar,
+    in_timezone varchar,
+    in_next_stop_at timestamp,
+    in_next_start_at timestamp)
+    RETURNS void
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    INSERT INTO catalog_schedules (catalog, stop_cron, start_cron, timezone)
+    VALUES (in_catalog, in_stop_cron, in_start_cron, in_timezone)
+    ON CONFLICT (catalog) DO UPDATE
+    SET stop_cron = EXCLUDED.stop_cron,
+        start_cron = EXCLUDED.start_cron,
+        timezone = EXCLUDED.timezone,
+        updated_at = now();
+
+    INSERT INTO sandbox_schedules (sandbox_id, stop_cron, start_cron, timezone, next_stop_at, next_start_at, catalog)
+    SELECT sb.id, in_stop_cron, in_start_cron, in_timezone, in_next_stop_at, in_next_start_at, in_catalog
+    FROM sandboxes sb
+    WHERE sb.status NOT IN ('DELETED', 'DELETING') AND
+        coalesce(sb.labels ->> 'catalog', 'default') = in_catalog
+    ON CONFLICT (sandbox_id) DO UPDATE
+    SET stop_cron = EXCLUDED.stop_cron,
+        start_cron = EXCLUDED.start_cron,
+        timezone = EXCLUDED.timezone,
+        next_stop_at = EXCLUDED.next_stop_at,
+        next_start_at = EXCLUDED.next_start_at,
+        catalog = EXCLUDED.catalog,
+        updated_at = now()
+    WHERE sandbox_schedules.catalog <> '';
+END;
+$$;
+
+-- Deletes the schedule of the catalog along with the schedules its sandboxes
+-- inherited
+CREATE OR REPLACE FUNCTION delete_catalog_schedule(in_catalog varchar)
+    RETURNS boolean
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    DELETE FROM sandbox_schedules
+    WHERE catalog = in_catalog;
+
+    DELETE FROM catalog_schedules
+    WHERE catalog = in_catalog;
+
+    RETURN FOUND;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION get_catalog_schedule(in_catalog varchar)
+    RETURNS table
+    (
+        catalog varchar,
+        stop_cron varchar,
+        start_cron varchar,
+        timezone varchar
+    )
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT
+        c.catalog,
+        c.stop_cron,
+        c.start_cron,
+        c.timezone
+    FROM
+        catalog_schedules c
+    WHERE
+        c.catalog = in_catalog;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION delete_sandbox_schedule(in_sandbox_id uuid)
+    RETURNS boolean
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    DELETE FROM sandbox_schedules
+    WHERE sandbox_id = in_sandbox_id;
+
+    RETURN FOUND;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION get_sandbox_schedule(in_sandbox_id uuid)
+    RETURNS table
+    (
+        sandbox_id uuid,
+        stop_cron varchar,
+        start_cron varchar,
+        timezone varchar,
+        next_stop_at timestamp,
+        next_start_at timestamp,
+        catalog varchar
+    )
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT
+        s.sandbox_id,
+        s.stop_cron,
+        s.start_cron,
+        s.timezone,
+        s.next_stop_at,
+        s.next_start_at,
+        s.catalog
+    FROM
+        sandbox_schedules s
+    WHERE
+        s.sandbox_id = in_sandbox_id;
+END;
+$$;
+
+-- Returns the schedules with a stop or a start run due
+CREATE OR REPLACE FUNCTION get_due_sandbox_schedules(in_limit integer)
+    RETURNS table
+    (
+        sandbox_id uuid,
+        stop_cron varchar,
+        start_cron varchar,
+        timezone varchar,
+        next_stop_at timestamp,
+        next_start_at timestamp,
+        catalog varchar
+    )
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT
+        s.sandbox_id,
+        s.stop_cron,
+        s.start_cron,
+        s.timezone,
+        s.next_stop_at,
+        s.next_start_at,
+        s.catalog
+    FROM
+        sandbox_schedules s
+        JOIN sandboxes sb ON sb.id = s.sandbox_id
+    WHERE
+        sb.status NOT IN ('DELETED', 'DELETING') AND
+        (s.next_stop_at <= now() OR s.next_start_at <= now())
+    ORDER BY least(s.next_stop_at, s.next_start_at)
+    LIMIT in_limit;
+END;
+$$;
+
+-- Moves the next run of the action from in_from to in_to. Only one of the
+-- concurrent callers succeeds, so every run is executed once.
+CREATE OR REPLACE FUNCTION advance_sandbox_schedule(in_sandbox_id uuid, in_action varchar, in_from timestamp, in_to timestamp)
+    RETURNS boolean
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    IF in_action = 'stop' THEN
+        UPDATE sandbox_schedules
+        SET next_stop_at = in_to,
+            updated_at = now()
+        WHERE sandbox_id = in_sandbox_id AND
+            next_stop_at = in_from;
+    ELSE
+        UPDATE sandbox_schedules
+        SET next_start_at = in_to,
+            updated_at = now()
+        WHERE sandbox_id = in_sandbox_id AND
+            next_start_at = in_from;
+    END IF;
+
+    RETURN FOUND;
+END;
+$$;
+
+COMMIT;
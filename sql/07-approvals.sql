@@ -0,0 +1,163 @@
+SET client_min_messages TO warning;
+
+BEGIN;
+
+CREATE TYPE public.approval_status AS ENUM (
+    'PENDING',
+    'APPROVED',
+    'DENIED',
+    'EXPIRED',
+    'CANCELED'
+);
+
+-- Requests for the sandboxes exceeding the policy. The name of the sandbox is
+-- kept, the denied sandboxes are deleted.
+CREATE TABLE approvals (
+    id uuid DEFAULT uuid_generate_v4() CONSTRAINT approvals_pk PRIMARY KEY,
+    sandbox_id uuid NOT NULL,
+    sandbox_name varchar(90) NOT NULL,
+    status public.approval_status NOT NULL DEFAULT 'PENDING',
+    reasons jsonb NOT NULL DEFAULT '[]'::jsonb,
+    requested_by varchar(255) NOT NULL DEFAULT '',
+    requested_at timestamp NOT NULL DEFAULT now(),
+    -- Pending requests expire at expires_at
+    expires_at timestamp NOT NULL,
+    decided_by varchar(255) NOT NULL DEFAULT '',
+    decided_at timestamp,
+    comment varchar(1024) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX approvals_status_requested_at_idx ON approvals (status, requested_at);
+CREATE INDEX approvals_pending_expires_at_idx ON approvals (expires_at) WHERE status = 'PENDING';
+CREATE UNIQUE INDEX approvals_pending_sandbox_id_idx ON approvals (sandbox_id) WHERE status = 'PENDING';
+
+CREATE OR REPLACE FUNCTION insert_approval(
+    in_sandbox_id uuid,
+    in_sandbox_name varchar,
+    in_reasons jsonb,
+    in_requested_by varchar,
+    in_expires_at timestamp)
+    RETURNS uuid
+    LANGUAGE 'plpgsql'
+AS
+$$
+DECLARE
+    approval_id uuid;
+BEGIN
+    INSERT INTO approvals (sandbox_id, sandbox_name, reasons, requested_by, expires_at)
+    VALUES (in_sandbox_id, in_sandbox_name, in_reasons, in_requested_by, in_expires_at)
+    RETURNING id INTO approval_id;
+
+    RETURN approval_id;
+END;
+$$;
+
+-- Decides the pending request and moves the sandbox out of PENDING_APPROVAL in
+-- the same transaction. The approved sandbox is SCHEDULED if its start is
+-- still ahead, PENDING otherwise, the others are DELETED. Returns the new
+-- status of the sandbox, NULL if the request is not pending anymore.
+CREATE OR REPLACE FUNCTION decide_approval(
+    in_approval_id uuid,
+    in_status public.approval_status,
+    in_decided_by varchar,
+    in_comment varchar)
+    RETURNS public.status
+    LANGUAGE 'plpgsql'
+AS
+$$
+DECLARE
+    approval approvals%ROWTYPE;
+    new_status public.status;
+BEGIN
+    UPDATE approvals
+    SET status = in_status,
+        decided_by = in_decided_by,
+        decided_at = now(),
+        comment = in_comment
+    WHERE id = in_approval_id AND
+        status = 'PENDING'
+    RETURNING * INTO approval;
+
+    IF NOT FOUND THEN
+        RETURN NULL;
+    END IF;
+
+    UPDATE sandboxes
+    SET status = CASE
+            WHEN in_status <> 'APPROVED' THEN 'DELETED'
+            WHEN start_at > now() THEN 'SCHEDULED'
+            ELSE 'PENDING'
+        END::public.status,
+        updated_at = now(),
+        version = version + 1
+    WHERE id = approval.sandbox_id AND
+        status = 'PENDING_APPROVAL'
+    RETURNING status INTO new_status;
+
+    -- The sandbox is gone, the decision can't be applied
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'sandbox % is not pending approval', approval.sandbox_id;
+    END IF;
+
+    RETURN new_status;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION get_approval_by_id(in_approval_id uuid)
+    RETURNS SETOF approvals
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT * FROM approvals
+    WHERE id = in_approval_id;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION get_pending_approval_by_sandbox(in_sandbox_id uuid)
+    RETURNS SETOF approvals
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT * FROM approvals
+    WHERE sandbox_id = in_sandbox_id AND
+        status = 'PENDING';
+END;
+$$;
+
+-- Lists the requests, oldest first, NULL status lists all of them
+CREATE OR REPLACE FUNCTION get_approvals(in_status public.approval_status, in_limit integer, in_offset integer)
+    RETURNS SETOF approvals
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT * FROM approvals
+    WHERE in_status IS NULL OR status = in_status
+    ORDER BY requested_at, id
+    LIMIT in_limit
+    OFFSET in_offset;
+END;
+$$;
+
+-- Returns the pending requests past their expiration
+CREATE OR REPLACE FUNCTION get_stale_approvals(in_limit integer)
+    RETURNS SETOF approvals
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT * FROM approvals
+    WHERE status = 'PENDING' AND
+        expires_at <= now()
+    ORDER BY expires_at
+    LIMIT in_limit;
+END;
+$$;
+
+COMMIT;
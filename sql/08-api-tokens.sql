@@ -0,0 +1,180 @@
+SET client_min_messages TO warning;
+
+BEGIN;
+
+-- Personal access tokens the users create for the scripts and the CI. Only
+-- the SHA-256 hash of the token is kept, the token itself is shown once.
+CREATE TABLE api_tokens (
+    id uuid DEFAULT uuid_generate_v4() CONSTRAINT api_tokens_pk PRIMARY KEY,
+    owner varchar(255) NOT NULL CHECK (owner <> ''),
+    name varchar(100) NOT NULL,
+    token_hash bytea NOT NULL CONSTRAINT api_tokens_token_hash_key UNIQUE,
+    scopes varchar[] NOT NULL,
+    created_at timestamp NOT NULL DEFAULT now(),
+    expires_at timestamp NOT NULL,
+    -- Updated at most once per the interval passed to touch_api_token
+    last_used_at timestamp,
+    revoked_at timestamp
+);
+
+CREATE INDEX api_tokens_owner_created_at_idx ON api_tokens (owner, created_at);
+
+CREATE OR REPLACE FUNCTION insert_api_token(
+    in_owner varchar,
+    in_name varchar,
+    in_token_hash bytea,
+    in_scopes varchar[],
+    in_expires_at timestamp)
+    RETURNS uuid
+    LANGUAGE 'plpgsql'
+AS
+$$
+DECLARE
+    token_id uuid;
+BEGIN
+    INSERT INTO api_tokens (owner, name, token_hash, scopes, expires_at)
+    VALUES (in_owner, in_name, in_token_hash, in_scopes, in_expires_at)
+    RETURNING id INTO token_id;
+
+    RETURN token_id;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION get_api_token_by_id(in_token_id uuid)
+    RETURNS table
+    (
+        id uuid,
+        owner varchar,
+        name varchar,
+        scopes varchar[],
+        created_at timestamp,
+        expires_at timestamp,
+        last_used_at timestamp,
+        revoked_at timestamp
+    )
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT
+        t.id,
+        t.owner,
+        t.name,
+        t.scopes,
+        t.created_at,
+        t.expires_at,
+        t.last_used_at,
+        t.revoked_at
+    FROM
+        api_tokens t
+    WHERE
+        t.id = in_token_id;
+END;
+$$;
+
+-- Returns the token by its hash only while it is neither revoked nor expired
+CREATE OR REPLACE FUNCTION get_active_api_token_by_hash(in_token_hash bytea)
+    RETURNS table
+    (
+        id uuid,
+        owner varchar,
+        name varchar,
+        scopes varchar[],
+        created_at timestamp,
+        expires_at timestamp,
+        last_used_at timestamp,
+        revoked_at timestamp
+    )
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT
+        t.id,
+        t.owner,
+        t.name,
+        t.scopes,
+        t.created_at,
+        t.expires_at,
+        t.last_used_at,
+        t.revoked_at
+    FROM
+        api_tokens t
+    WHERE
+        t.token_hash = in_token_hash AND
+        t.revoked_at IS NULL AND
+        t.expires_at > now();
+END;
+$$;
+
+-- Lists the tokens of the owner, newest first, the revoked and the expired
+-- ones included
+CREATE OR REPLACE FUNCTION get_api_tokens_by_owner(in_owner varchar)
+    RETURNS table
+    (
+        id uuid,
+        owner varchar,
+        name varchar,
+        scopes varchar[],
+        created_at timestamp,
+        expires_at timestamp,
+        last_used_at timestamp,
+        revoked_at timestamp
+    )
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT
+        t.id,
+        t.owner,
+        t.name,
+        t.scopes,
+        t.created_at,
+        t.expires_at,
+        t.last_used_at,
+        t.revoked_at
+    FROM
+        api_tokens t
+    WHERE
+        t.owner = in_owner
+    ORDER BY t.created_at DESC, t.id;
+END;
+$$;
+
+-- Returns false if the token is already revoked
+CREATE OR REPLACE FUNCTION revoke_api_token(in_token_id uuid)
+    RETURNS boolean
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    UPDATE api_tokens
+    SET revoked_at = now()
+    WHERE id = in_token_id AND
+        revoked_at IS NULL;
+
+    RETURN FOUND;
+END;
+$$;
+
+-- Records the use of the token unless it was recorded within the interval, so
+-- the busy tokens don't turn every request into a write
+CREATE OR REPLACE FUNCTION touch_api_token(in_token_id uuid, in_interval_seconds integer)
+    RETURNS void
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    UPDATE api_tokens
+    SET last_used_at = now()
+    WHERE id = in_token_id AND
+        (last_used_at IS NULL OR
+         last_used_at < now() - make_interval(secs => in_interval_seconds));
+END;
+$$;
+
+COMMIT;
@@ -0,0 +1,77 @@
+SET client_min_messages TO warning;
+
+BEGIN;
+
+-- Deny-list of the tokens, either a single token by its jti, the id of the
+-- personal access token, or every token of the subject issued before the
+-- revocation. The list is small, the instances cache it in memory. The single
+-- tokens are dropped once they expire.
+CREATE TABLE revoked_tokens (
+    id uuid DEFAULT uuid_generate_v4() CONSTRAINT revoked_tokens_pk PRIMARY KEY,
+    token_id varchar(255),
+    subject varchar(255),
+    revoked_by varchar(255) NOT NULL DEFAULT '',
+    -- UTC, set by the service like the issue times of the tokens it is
+    -- compared with
+    revoked_at timestamp NOT NULL,
+    -- Expiry of the revoked token, NULL for the subjects
+    expires_at timestamp,
+    reason varchar(1024) NOT NULL DEFAULT '',
+    CONSTRAINT revoked_tokens_target_check CHECK ((token_id IS NULL) <> (subject IS NULL)),
+    CONSTRAINT revoked_tokens_expires_at_check CHECK ((token_id IS NULL) = (expires_at IS NULL))
+);
+
+CREATE INDEX revoked_tokens_revoked_at_idx ON revoked_tokens (revoked_at);
+CREATE INDEX revoked_tokens_expires_at_idx ON revoked_tokens (expires_at) WHERE expires_at IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION insert_revoked_token(
+    in_token_id varchar,
+    in_subject varchar,
+    in_revoked_by varchar,
+    in_reason varchar,
+    in_revoked_at timestamp,
+    in_expires_at timestamp)
+    RETURNS SETOF revoked_tokens
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    INSERT INTO revoked_tokens (token_id, subject, revoked_by, reason, revoked_at, expires_at)
+    VALUES (in_token_id, in_subject, in_revoked_by, in_reason, in_revoked_at, in_expires_at)
+    RETURNING *;
+END;
+$$;
+
+-- Removes the revocations of the tokens expired before in_now, the expired
+-- tokens are denied anyway
+CREATE OR REPLACE FUNCTION delete_expired_revoked_tokens(in_now timestamp)
+    RETURNS integer
+    LANGUAGE 'plpgsql'
+AS
+$$
+DECLARE
+    deleted integer;
+BEGIN
+    DELETE FROM revoked_tokens
+    WHERE expires_at <= in_now;
+
+    GET DIAGNOSTICS deleted = ROW_COUNT;
+
+    RETURN deleted;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION get_revoked_tokens()
+    RETURNS SETOF revoked_tokens
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT * FROM revoked_tokens
+    ORDER BY revoked_at, id;
+END;
+$$;
+
+COMMIT;
@@ -0,0 +1,80 @@
+SET client_min_messages TO warning;
+
+BEGIN;
+
+-- Token buckets of the rate limits shared by the instances. The buckets are
+-- refilled on the next take, so a row is written by every limited request.
+-- Losing them on a crash only resets the limits, so the table is not logged.
+CREATE UNLOGGED TABLE rate_limit_buckets (
+    key varchar(512) CONSTRAINT rate_limit_buckets_pk PRIMARY KEY,
+    tokens double precision NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+CREATE INDEX rate_limit_buckets_updated_at_idx ON rate_limit_buckets (updated_at);
+
+-- Takes a token from the bucket of the key, the bucket is refilled by in_rate
+-- tokens a second up to in_burst first. Returns whether the token was taken
+-- and the tokens left. The row lock serializes the takes of the same key.
+CREATE OR REPLACE FUNCTION take_rate_limit_token(
+    in_key varchar,
+    in_rate double precision,
+    in_burst integer)
+    RETURNS table
+    (
+        allowed boolean,
+        tokens double precision
+    )
+    LANGUAGE 'plpgsql'
+AS
+$$
+DECLARE
+    taken_at timestamp := clock_timestamp();
+    bucket rate_limit_buckets%ROWTYPE;
+    available double precision;
+    is_allowed boolean;
+BEGIN
+    INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+    VALUES (in_key, in_burst, taken_at)
+    ON CONFLICT (key) DO NOTHING;
+
+    SELECT * INTO bucket
+    FROM rate_limit_buckets b
+    WHERE b.key = in_key
+    FOR UPDATE;
+
+    available := least(in_burst::double precision,
+        bucket.tokens + greatest(0, extract(epoch FROM taken_at - bucket.updated_at)) * in_rate);
+
+    is_allowed := available >= 1;
+    IF is_allowed THEN
+        available := available - 1;
+    END IF;
+
+    UPDATE rate_limit_buckets b
+    SET tokens = available, updated_at = taken_at
+    WHERE b.key = in_key;
+
+    RETURN QUERY SELECT is_allowed, available;
+END;
+$$;
+
+-- Deletes the buckets untouched for the interval, they are full by then
+CREATE OR REPLACE FUNCTION delete_idle_rate_limit_buckets(in_interval_seconds integer)
+    RETURNS integer
+    LANGUAGE 'plpgsql'
+AS
+$$
+DECLARE
+    deleted integer;
+BEGIN
+    DELETE FROM rate_limit_buckets
+    WHERE updated_at < now() - make_interval(secs => in_interval_seconds);
+
+    GET DIAGNOSTICS deleted = ROW_COUNT;
+
+    RETURN deleted;
+END;
+$$;
+
+COMMIT;
@@ -0,0 +1,206 @@
+SET client_min_messages TO warning;
+
+BEGIN;
+
+-- Append-only log of the actions on the sandboxes and the tokens, and of the
+-- status transitions of the sandboxes. Every event carries the SHA-256 hash
+-- of its fields and of the hash of the previous event, so changing or
+-- deleting an event breaks the chain, see verify_audit_chain.
+CREATE TABLE audit_events (
+    id bigserial CONSTRAINT audit_events_pk PRIMARY KEY,
+    -- UTC, like the times the service writes
+    occurred_at timestamp NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    actor varchar(255) NOT NULL DEFAULT '',
+    action varchar(64) NOT NULL,
+    target_type varchar(64) NOT NULL DEFAULT '',
+    target_id varchar(255) NOT NULL DEFAULT '',
+    request_id varchar(128) NOT NULL DEFAULT '',
+    outcome varchar(16) NOT NULL CHECK (outcome IN ('success', 'failure')),
+    status_code integer,
+    -- The changed fields only, before and after the action
+    old_values jsonb,
+    new_values jsonb,
+    prev_hash bytea NOT NULL,
+    hash bytea NOT NULL
+);
+
+CREATE INDEX audit_events_occurred_at_idx ON audit_events (occurred_at);
+CREATE INDEX audit_events_actor_idx ON audit_events (actor, id);
+CREATE INDEX audit_events_target_idx ON audit_events (target_type, target_id, id);
+CREATE INDEX audit_events_request_id_idx ON audit_events (request_id) WHERE request_id <> '';
+
+CREATE OR REPLACE FUNCTION reject_audit_event_change()
+    RETURNS trigger
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit events are append-only';
+END;
+$$;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_event_change();
+
+-- The hash of the event, the fields are encoded as a JSON array, so they
+-- can't be shifted from one to another
+CREATE OR REPLACE FUNCTION audit_event_hash(event audit_events)
+    RETURNS bytea
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN sha256(event.prev_hash || convert_to(jsonb_build_array(
+        event.id,
+        to_char(event.occurred_at, 'YYYY-MM-DD"T"HH24:MI:SS.US'),
+        event.actor,
+        event.action,
+        event.target_type,
+        event.target_id,
+        event.request_id,
+        event.outcome,
+        event.status_code,
+        event.old_values,
+        event.new_values)::text, 'UTF8'));
+END;
+$$;
+
+-- Appends the event to the chain. The advisory lock serializes the writers,
+-- so the ids follow the order of the chain.
+CREATE OR REPLACE FUNCTION insert_audit_event(
+    in_actor varchar,
+    in_action varchar,
+    in_target_type varchar,
+    in_target_id varchar,
+    in_request_id varchar,
+    in_outcome varchar,
+    in_status_code integer,
+    in_old_values jsonb,
+    in_new_values jsonb)
+    RETURNS SETOF audit_events
+    LANGUAGE 'plpgsql'
+AS
+$$
+DECLARE
+    event audit_events%ROWTYPE;
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('audit_events'));
+
+    SELECT a.hash INTO event.prev_hash
+    FROM audit_events a
+    ORDER BY a.id DESC
+    LIMIT 1;
+
+    -- The first event is chained to the zero hash
+    event.prev_hash := coalesce(event.prev_hash, decode(repeat('00', 32), 'hex'));
+
+    event.id := nextval(pg_get_serial_sequence('audit_events', 'id'));
+    event.occurred_at := clock_timestamp() AT TIME ZONE 'UTC';
+    event.actor := in_actor;
+    event.action := in_action;
+    event.target_type := in_target_type;
+    event.target_id := in_target_id;
+    event.request_id := in_request_id;
+    event.outcome := in_outcome;
+    event.status_code := in_status_code;
+    event.old_values := in_old_values;
+    event.new_values := in_new_values;
+    event.hash := audit_event_hash(event);
+
+    INSERT INTO audit_events VALUES (event.*);
+
+    RETURN NEXT event;
+END;
+$$;
+
+-- Lists the events matching the filters, oldest first. NULL arguments don't
+-- filter, the events are listed after in_after_id, up to in_limit if set.
+CREATE OR REPLACE FUNCTION get_audit_events(
+    in_actor varchar,
+    in_action varchar,
+    in_target_type varchar,
+    in_target_id varchar,
+    in_request_id varchar,
+    in_outcome varchar,
+    in_from timestamp,
+    in_to timestamp,
+    in_after_id bigint,
+    in_limit integer)
+    RETURNS SETOF audit_events
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    RETURN QUERY
+    SELECT * FROM audit_events a
+    WHERE
+        (in_actor IS NULL OR a.actor = in_actor) AND
+        (in_action IS NULL OR a.action = in_action) AND
+        (in_target_type IS NULL OR a.target_type = in_target_type) AND
+        (in_target_id IS NULL OR a.target_id = in_target_id) AND
+        (in_request_id IS NULL OR a.request_id = in_request_id) AND
+        (in_outcome IS NULL OR a.outcome = in_outcome) AND
+        (in_from IS NULL OR a.occurred_at >= in_from) AND
+        (in_to IS NULL OR a.occurred_at < in_to) AND
+        a.id > coalesce(in_after_id, 0)
+    ORDER BY a.id
+    LIMIT in_limit;
+END;
+$$;
+
+-- Walks the chain from the first event, returns the number of the events
+-- checked and the id of the first event which doesn't match its hash or the
+-- hash of the previous one, NULL if the chain is intact. The events dropped
+-- from the end of the chain can't be detected this way.
+CREATE OR REPLACE FUNCTION verify_audit_chain()
+    RETURNS table
+    (
+        events bigint,
+        first_invalid_id bigint
+    )
+    LANGUAGE 'plpgsql'
+AS
+$$
+DECLARE
+    event audit_events%ROWTYPE;
+    expected_prev_hash bytea := decode(repeat('00', 32), 'hex');
+    checked bigint := 0;
+BEGIN
+    FOR event IN SELECT * FROM audit_events a ORDER BY a.id LOOP
+        checked := checked + 1;
+
+        IF event.prev_hash <> expected_prev_hash OR event.hash <> audit_event_hash(event) THEN
+            RETURN QUERY SELECT checked, event.id;
+            RETURN;
+        END IF;
+
+        expected_prev_hash := event.hash;
+    END LOOP;
+
+    RETURN QUERY SELECT checked, NULL::bigint;
+END;
+$$;
+
+-- Records the status transitions of the sandboxes, whoever makes them. Most
+-- of them are made by the service itself, e.g. once the provisioning is done.
+CREATE OR REPLACE FUNCTION audit_sandbox_status()
+    RETURNS trigger
+    LANGUAGE 'plpgsql'
+AS
+$$
+BEGIN
+    PERFORM insert_audit_event('system', 'sandbox.status', 'sandbox', NEW.id::text, '', 'success', NULL,
+        jsonb_build_object('status', OLD.status), jsonb_build_object('status', NEW.status));
+
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER sandboxes_status_audit
+    AFTER UPDATE OF status ON sandboxes
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION audit_sandbox_status();
+
+COMMIT;